@@ -2,31 +2,152 @@ package main
 
 import (
 	"context"
+	"crypto"
+	"flag"
 	"github.com/dominati-one/backend/internal/app/backend"
+	"github.com/dominati-one/backend/internal/pkg/blockchain/network"
+	"github.com/dominati-one/backend/internal/pkg/security"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 )
 
+// authorityKeyEnv holds hex encoded ed25519 seed of authority key, when -authority-key-file is not given.
+const authorityKeyEnv = "DOMINATIONE_AUTHORITY_KEY"
+
 func main() {
+	dataDirectory := flag.String("data-dir", "", "directory for persistent blockchain storage, blocks are kept in memory when empty")
+	snapshotInterval := flag.Int("snapshot-interval", 1000, "number of blocks between game snapshots, requires data directory, zero disables snapshots")
+	apiListenPort := flag.Uint("api-port", 3009, "port of game gRPC API")
+	peerListenAddress := flag.String("peer-listen", "", "host:port for gossip with other nodes, node runs alone when empty")
+	peers := flag.String("peers", "", "comma separated host:port addresses of other nodes")
+	authority := flag.Bool("authority", false, "seal blocks as authority, requires authority key from -authority-key-file or "+authorityKeyEnv)
+	authorityKeyFile := flag.String("authority-key-file", "", "file with hex encoded ed25519 seed of authority key")
+	authorityPublicKeys := flag.String("authority-public-keys", "", "comma separated hex encoded public keys of trusted authorities")
+	devAuthority := flag.Bool("dev-authority", false, "trust publicly known development authority and seal blocks with its key in authority mode, for local development only")
+	flag.Parse()
+
 	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.Stamp})
 
 	ctx := context.Background()
 
+	trustedPublicKeys := []crypto.PublicKey{}
+	for _, hexPublicKey := range splitList(*authorityPublicKeys) {
+		publicKey, err := security.NewPublicKeyFromHex(hexPublicKey)
+		if err != nil {
+			log.Fatal().Err(err).Str("publicKey", hexPublicKey).Msg("Invalid authority public key.")
+		}
+		trustedPublicKeys = append(trustedPublicKeys, publicKey)
+	}
+
+	var privateKey *security.PrivateKey
+	if *devAuthority {
+		log.Warn().Msg("Development authority is publicly known, never use it outside local development.")
+
+		trustedPublicKeys = append(trustedPublicKeys, network.CreateDevAuthorityPrivateKey().PublicKey())
+		if *authority {
+			privateKey = network.CreateDevAuthorityPrivateKey()
+		}
+	} else if *authority {
+		authorityKey, err := loadAuthorityKey(*authorityKeyFile)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Unable to load authority key.")
+		}
+
+		privateKey = authorityKey
+		trustedPublicKeys = append(trustedPublicKeys, privateKey.PublicKey())
+	}
+
+	if len(trustedPublicKeys) == 0 {
+		log.Fatal().Msg("No trusted authority, use -authority-public-keys, -authority or -dev-authority.")
+	}
+
+	if privateKey == nil {
+		var err error
+		if privateKey, err = security.GeneratePrivateKey(); err != nil {
+			panic(err)
+		}
+	}
+
 	parameters := backend.AppParameters{
 		GrpcApiListenAddress: "127.0.0.1",
-		GrpcApiListenPort:    3009,
+		GrpcApiListenPort:    uint32(*apiListenPort),
+		PrivateKey:           privateKey,
+		AuthorityPublicKeys:  security.NewPublicKeysBag(trustedPublicKeys),
+		DataDirectory:        *dataDirectory,
+		SnapshotInterval:     *snapshotInterval,
+		PeerListenAddress:    *peerListenAddress,
+		Peers:                splitList(*peers),
 	}
 
-	app := backend.NewApp(parameters)
+	app, err := backend.NewApp(parameters)
+	if err != nil {
+		panic(err)
+	}
 
-	err := app.Start(ctx)
+	err = app.Start(ctx)
 	if err != nil {
 		panic(err)
 	}
 
-	select {}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	failed := false
+
+	select {
+	case receivedSignal := <-signals:
+		log.Info().Str("signal", receivedSignal.String()).Msg("Shutting down.")
+	case err := <-app.Failed():
+		log.Error().Err(err).Msg("Game does not follow chain anymore. Shutting down.")
+		failed = true
+	}
+
+	// Second signal terminates node immediately, when shutdown hangs.
+	signal.Reset(syscall.SIGINT, syscall.SIGTERM)
+
+	if err := app.Stop(); err != nil {
+		log.Error().Err(err).Msg("Shutdown failed.")
+		os.Exit(1)
+	}
+
+	log.Info().Msg("Shutdown finished.")
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// loadAuthorityKey reads authority key from file or from environment variable, when file is not given.
+func loadAuthorityKey(path string) (*security.PrivateKey, error) {
+	if path != "" {
+		return security.LoadPrivateKeyFromFile(path)
+	}
+
+	if hexSeed := os.Getenv(authorityKeyEnv); hexSeed != "" {
+		return security.NewPrivateKeyFromHexSeed(hexSeed)
+	}
 
-	os.Exit(0)
+	return nil, errAuthorityKeyMissing
 }
+
+func splitList(list string) []string {
+	result := []string{}
+
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+var (
+	errAuthorityKeyMissing = errors.New("authority key missing")
+)
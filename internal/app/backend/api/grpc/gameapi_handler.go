@@ -2,6 +2,8 @@ package grpc
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/binary"
 	"github.com/dominati-one/backend/internal/pkg/blockchain"
 	"github.com/dominati-one/backend/internal/pkg/game"
 	"github.com/dominati-one/backend/internal/pkg/game/world"
@@ -10,27 +12,59 @@ import (
 	protocolComponent "github.com/dominati-one/backend/pkg/protocol/component"
 	protocolEntity "github.com/dominati-one/backend/pkg/protocol/entity"
 	"github.com/dominati-one/backend/pkg/protocol/gameapi"
+	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
+// EventResults provides outcome of application of events to game.
+type EventResults interface {
+	// Get returns error, with which event was rejected, or empty string, when event was applied. False is returned,
+	// when event was not applied to game yet.
+	Get(eventId blockchain.EventId) (string, bool)
+	// Changed returns channel, which is closed, when next result is recorded.
+	Changed() <-chan struct{}
+}
+
 type GameApiHandler struct {
 	log          zerolog.Logger
 	eventBacklog *blockchain.LocalEventBacklog
+	eventEmitter *blockchain.EventEmitter
+	blockStorage blockchain.BlockStorage
+	eventStorage blockchain.EventStorage
+	eventResults EventResults
 	game         *game.Game
 }
 
-func NewGameApiHandler(game *game.Game, eventBacklog *blockchain.LocalEventBacklog) *GameApiHandler {
+func NewGameApiHandler(game *game.Game, eventBacklog *blockchain.LocalEventBacklog, eventEmitter *blockchain.EventEmitter, blockStorage blockchain.BlockStorage, eventStorage blockchain.EventStorage, eventResults EventResults) *GameApiHandler {
 	return &GameApiHandler{
 		log:          log.With().Str("applicationComponent", "gameApiHandler").Logger(),
 		game:         game,
 		eventBacklog: eventBacklog,
+		eventEmitter: eventEmitter,
+		blockStorage: blockStorage,
+		eventStorage: eventStorage,
+		eventResults: eventResults,
 	}
 }
 
 func (h *GameApiHandler) CreatePlanet(ctx context.Context, request *gameapi.CreatePlanetRequest) (*gameapi.CreatePlanetResponse, error) {
-	createPlanetEvent := &blockchainProtocol.EventCreatePlanet{}
+	var seed int64
+	if request.Seed != nil {
+		seed = *request.Seed
+	} else {
+		randomSeed, err := newRandomPlanetSeed()
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to create random planet seed")
+		}
+		seed = randomSeed
+	}
+
+	createPlanetEvent := &blockchainProtocol.EventCreatePlanet{
+		Seed:              seed,
+		GenerationVersion: world.CurrentPlanetGenerationVersion,
+	}
 
 	eventId, err := h.eventBacklog.Add(createPlanetEvent)
 	if err != nil {
@@ -41,13 +75,15 @@ func (h *GameApiHandler) CreatePlanet(ctx context.Context, request *gameapi.Crea
 }
 
 func (h *GameApiHandler) GetPlanet(ctx context.Context, request *gameapi.GetPlanetRequest) (*gameapi.GetPlanetResponse, error) {
+	state := h.game.State()
+
 	planetEntity := component.Entity(request.Entity)
-	planet, err := h.game.State().Planet().Get(planetEntity)
+	planet, err := state.Planet().Get(planetEntity)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to get planet")
 	}
 
-	area, err := h.game.State().Area().GetArea(planetEntity)
+	area, err := state.Area().GetArea(planetEntity)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to get area")
 	}
@@ -62,8 +98,10 @@ func (h *GameApiHandler) GetPlanet(ctx context.Context, request *gameapi.GetPlan
 }
 
 func (h *GameApiHandler) GetAreaTiles(ctx context.Context, request *gameapi.GetAreaTilesRequest) (*gameapi.GetAreaTilesResponse, error) {
+	state := h.game.State()
+
 	areaEntity := component.Entity(request.Entity)
-	areaTiles, err := h.game.State().Area().GetAreaTiles(areaEntity, world.AreaTilesExtent{
+	areaTiles, err := state.Area().GetAreaTiles(areaEntity, world.AreaTilesExtent{
 		Left:   request.Left,
 		Top:    request.Top,
 		Right:  request.Right,
@@ -89,11 +127,13 @@ func (h *GameApiHandler) GetAreaTiles(ctx context.Context, request *gameapi.GetA
 }
 
 func (h *GameApiHandler) GetSeeds(ctx context.Context, request *gameapi.GetSeedsRequest) (*gameapi.GetSeedsResponse, error) {
-	entities := h.game.State().Seed().Entities()
+	state := h.game.State()
+
+	entities := state.Seed().Entities()
 
 	if request.QueryParams != nil {
 		if request.QueryParams.Owner != nil {
-			entities = h.game.State().Possession().Filter(entities, func(possession component.Possession) bool {
+			entities = state.Possession().Filter(entities, func(possession component.Possession) bool {
 				return uint64(possession.OwnerEntity) == request.QueryParams.Owner.OwnerEntity
 			})
 		}
@@ -102,13 +142,13 @@ func (h *GameApiHandler) GetSeeds(ctx context.Context, request *gameapi.GetSeeds
 	seeds := []*protocolEntity.Seed{}
 
 	for _, seedEntity := range entities {
-		seedComponent, err := h.game.State().Seed().Get(seedEntity)
+		seedComponent, err := state.Seed().Get(seedEntity)
 		if err != nil {
 			return nil, errors.Wrap(err, "unable to get seed component")
 		}
 
-		areaPositionComponent, _ := h.game.State().Area().GetPosition(seedEntity)
-		possessionComponent, _ := h.game.State().Possession().Get(seedEntity)
+		areaPositionComponent, _ := state.Area().GetPosition(seedEntity)
+		possessionComponent, _ := state.Possession().Get(seedEntity)
 
 		seedItem := &protocolEntity.Seed{
 			Entity: uint64(seedEntity),
@@ -129,17 +169,19 @@ func (h *GameApiHandler) GetSeeds(ctx context.Context, request *gameapi.GetSeeds
 }
 
 func (h *GameApiHandler) GetPlanets(ctx context.Context, request *gameapi.GetPlanetsRequest) (*gameapi.GetPlanetsResponse, error) {
-	entities := h.game.State().Planet().Entities()
+	state := h.game.State()
+
+	entities := state.Planet().Entities()
 
 	planets := []*protocolEntity.Planet{}
 
 	for _, planetEntity := range entities {
-		planetComponent, err := h.game.State().Planet().Get(planetEntity)
+		planetComponent, err := state.Planet().Get(planetEntity)
 		if err != nil {
 			return nil, errors.Wrap(err, "unable to get planet")
 		}
 
-		areaComponent, err := h.game.State().Area().GetArea(planetEntity)
+		areaComponent, err := state.Area().GetArea(planetEntity)
 		if err != nil {
 			return nil, errors.Wrap(err, "unable to get area")
 		}
@@ -155,3 +197,236 @@ func (h *GameApiHandler) GetPlanets(ctx context.Context, request *gameapi.GetPla
 		Planets: planets,
 	}, nil
 }
+
+func (h *GameApiHandler) GetPlayer(ctx context.Context, request *gameapi.GetPlayerRequest) (*gameapi.GetPlayerResponse, error) {
+	state := h.game.State()
+
+	player, err := state.Player().Get(component.Entity(request.Entity))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get player")
+	}
+
+	return &gameapi.GetPlayerResponse{
+		Player: &protocolEntity.Player{
+			Entity: request.Entity,
+			Player: player.Protobuf(),
+		},
+	}, nil
+}
+
+func (h *GameApiHandler) GetPlayers(ctx context.Context, request *gameapi.GetPlayersRequest) (*gameapi.GetPlayersResponse, error) {
+	state := h.game.State()
+
+	entities := state.Player().Entities()
+
+	players := []*protocolEntity.Player{}
+
+	for _, playerEntity := range entities {
+		playerComponent, err := state.Player().Get(playerEntity)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to get player")
+		}
+
+		players = append(players, &protocolEntity.Player{
+			Entity: uint64(playerEntity),
+			Player: playerComponent.Protobuf(),
+		})
+	}
+
+	return &gameapi.GetPlayersResponse{
+		Players: players,
+	}, nil
+}
+
+func (h *GameApiHandler) GetPlayerByPublicKey(ctx context.Context, request *gameapi.GetPlayerByPublicKeyRequest) (*gameapi.GetPlayerByPublicKeyResponse, error) {
+	state := h.game.State()
+
+	playerEntity, err := state.Player().GetByPublicKey(request.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get player entity by public key")
+	}
+
+	player, err := state.Player().Get(*playerEntity)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get player")
+	}
+
+	return &gameapi.GetPlayerByPublicKeyResponse{
+		Player: &protocolEntity.Player{
+			Entity: uint64(*playerEntity),
+			Player: player.Protobuf(),
+		},
+	}, nil
+}
+
+// GetEventProof returns header of block, which includes event, with Merkle proof of event inclusion, so client can
+// verify it against events root signed by authority.
+func (h *GameApiHandler) GetEventProof(ctx context.Context, request *gameapi.GetEventProofRequest) (*gameapi.GetEventProofResponse, error) {
+	eventId, err := newEventIdFromRequest(request.EventId)
+	if err != nil {
+		return nil, err
+	}
+
+	blockId, err := h.eventStorage.GetBlockId(eventId)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to find block of event")
+	}
+
+	block, err := h.blockStorage.Get(blockId)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get block")
+	}
+
+	blockHeight, err := h.blockStorage.GetHeight(blockId)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get block height")
+	}
+
+	proof, err := blockchain.NewEventInclusionProof(block.Body, eventId)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create event inclusion proof")
+	}
+
+	return &gameapi.GetEventProofResponse{
+		BlockId:        blockId.Bytes(),
+		BlockHeight:    uint64(blockHeight),
+		BlockHeader:    blockchain.NewBlockHeader(block.Body),
+		BlockSignature: block.Signature,
+		Proof:          proof,
+	}, nil
+}
+
+// GetEventStatus returns how far event got on its way into game. Including block and apply error are returned, once event
+// is included in block.
+func (h *GameApiHandler) GetEventStatus(ctx context.Context, request *gameapi.GetEventStatusRequest) (*gameapi.GetEventStatusResponse, error) {
+	eventId, err := newEventIdFromRequest(request.EventId)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.eventStatus(eventId)
+}
+
+// WatchEvent streams status of event, whenever it changes. Status is checked again, when block is emitted or result of
+// event application is recorded, so event included in block and its result are reported right away, while short
+// lived statuses of local backlog may be skipped. Stream ends after event is applied or rejected, or when event is
+// unknown to node, because it was never created here or it expired before it was included in block.
+func (h *GameApiHandler) WatchEvent(request *gameapi.WatchEventRequest, stream gameapi.Api_WatchEventServer) error {
+	eventId, err := newEventIdFromRequest(request.EventId)
+	if err != nil {
+		return err
+	}
+
+	// Subscription is created before first status is read, so no block emitted meanwhile is missed. Blocks only wake
+	// watch up, so slow watch drops them instead of stalling emitter.
+	subscription := h.eventEmitter.Subscribe(blockchain.EventEmitterSubscriptionSettings{
+		Name:       "watchEvent",
+		Policy:     blockchain.EventEmitterPolicyDrop,
+		BlocksOnly: true,
+	})
+	defer subscription.Unsubscribe()
+
+	var lastStatus *gameapi.GetEventStatusResponse
+
+	for {
+		resultsChanged := h.eventResults.Changed()
+
+		status, err := h.eventStatus(eventId)
+		if err != nil {
+			return err
+		}
+
+		if lastStatus == nil || !proto.Equal(status, lastStatus) {
+			err := stream.Send(&gameapi.WatchEventResponse{
+				Status:      status.Status,
+				BlockId:     status.BlockId,
+				BlockHeight: status.BlockHeight,
+				ApplyError:  status.ApplyError,
+			})
+			if err != nil {
+				return errors.Wrap(err, "unable to send event status")
+			}
+
+			lastStatus = status
+		}
+
+		switch status.Status {
+		case gameapi.EventStatus_EVENT_APPLIED, gameapi.EventStatus_EVENT_REJECTED, gameapi.EventStatus_EVENT_UNKNOWN:
+			return nil
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-subscription.Closed():
+			return ErrGameApiWatchEventSubscriptionClosed
+		case <-subscription.Messages():
+		case <-resultsChanged:
+		}
+	}
+}
+
+// eventStatus resolves status of event from block inclusion and outcome of its application, or from state of event in
+// local backlog, when event is not included in block yet.
+func (h *GameApiHandler) eventStatus(eventId blockchain.EventId) (*gameapi.GetEventStatusResponse, error) {
+	if blockId, err := h.eventStorage.GetBlockId(eventId); err == nil {
+		blockHeight, err := h.blockStorage.GetHeight(blockId)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to get block height")
+		}
+
+		response := &gameapi.GetEventStatusResponse{
+			Status:      gameapi.EventStatus_EVENT_INCLUDED,
+			BlockId:     blockId.Bytes(),
+			BlockHeight: uint64(blockHeight),
+		}
+
+		if applyError, applied := h.eventResults.Get(eventId); applied {
+			if applyError == "" {
+				response.Status = gameapi.EventStatus_EVENT_APPLIED
+			} else {
+				response.Status = gameapi.EventStatus_EVENT_REJECTED
+				response.ApplyError = applyError
+			}
+		}
+
+		return response, nil
+	}
+
+	localStatus, exists := h.eventBacklog.Status(eventId)
+
+	switch {
+	case !exists:
+		return &gameapi.GetEventStatusResponse{Status: gameapi.EventStatus_EVENT_UNKNOWN}, nil
+	case localStatus.Received || localStatus.Confirmed:
+		return &gameapi.GetEventStatusResponse{Status: gameapi.EventStatus_EVENT_RECEIVED}, nil
+	case localStatus.Sent:
+		return &gameapi.GetEventStatusResponse{Status: gameapi.EventStatus_EVENT_SENT}, nil
+	default:
+		return &gameapi.GetEventStatusResponse{Status: gameapi.EventStatus_EVENT_PENDING}, nil
+	}
+}
+
+func newEventIdFromRequest(requestEventId []byte) (blockchain.EventId, error) {
+	eventId := blockchain.EventId{}
+	if len(requestEventId) != len(eventId) {
+		return eventId, ErrGameApiInvalidEventId
+	}
+	copy(eventId[:], requestEventId)
+
+	return eventId, nil
+}
+
+var (
+	ErrGameApiInvalidEventId               = errors.New("game api invalid event id")
+	ErrGameApiWatchEventSubscriptionClosed = errors.New("game api watch event subscription closed")
+)
+
+func newRandomPlanetSeed() (int64, error) {
+	seedBytes := make([]byte, 8)
+	if _, err := rand.Read(seedBytes); err != nil {
+		return 0, err
+	}
+
+	return int64(binary.BigEndian.Uint64(seedBytes) >> 1), nil
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	"github.com/dominati-one/backend/internal/pkg/game"
+	"github.com/dominati-one/backend/internal/pkg/game/world"
+	"github.com/dominati-one/backend/internal/pkg/game/world/component"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/dominati-one/backend/pkg/protocol/gameapi"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestGameApiHandler_GetSeedsWhileBlocksApplied(t *testing.T) {
+	gameInstance := game.NewGame()
+
+	_, err := gameInstance.WorldClock().SetCurrentTimestamp(1000)
+	assert.NoError(t, err)
+
+	planetEntity, err := gameInstance.State().Actions().Planet().Create(42, world.CurrentPlanetGenerationVersion)
+	assert.NoError(t, err)
+
+	var seedEntities []component.Entity
+	for position := uint32(0); position < 1000 && len(seedEntities) < 10; position += 10 {
+		// Some tiles are unplantable, seeds are created only on those, which are not.
+		if seedEntity, err := gameInstance.State().Actions().Seed().CreateWheatSeed(*planetEntity, *planetEntity, position, position); err == nil {
+			seedEntities = append(seedEntities, *seedEntity)
+		}
+	}
+	assert.NotEmpty(t, seedEntities)
+
+	handler := NewGameApiHandler(gameInstance, nil, nil, nil, nil, nil)
+
+	const blockCount = 10
+
+	var wait sync.WaitGroup
+	wait.Add(2)
+
+	go func() {
+		defer wait.Done()
+
+		for blockIndex := 1; blockIndex <= blockCount; blockIndex++ {
+			block := &blockchainProtocol.Block{
+				Body: &blockchainProtocol.Block_Body{Timestamp: uint64(1000 + blockIndex*1000)},
+			}
+
+			_, err := gameInstance.ApplyBlock(block)
+			assert.NoError(t, err)
+		}
+	}()
+
+	go func() {
+		defer wait.Done()
+
+		for requestIndex := 0; requestIndex < blockCount; requestIndex++ {
+			response, err := handler.GetSeeds(context.TODO(), &gameapi.GetSeedsRequest{})
+			assert.NoError(t, err)
+			assert.NotNil(t, response)
+		}
+	}()
+
+	wait.Wait()
+
+	response, err := handler.GetSeeds(context.TODO(), &gameapi.GetSeedsRequest{})
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, len(response.Seeds), len(seedEntities))
+}
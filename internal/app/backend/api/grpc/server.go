@@ -9,14 +9,23 @@ import (
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
 	"net"
+	"sync"
 	"time"
 )
 
+const (
+	// serverStopTimeout limits how long running calls, like event watch streams, are waited for on stop.
+	serverStopTimeout = 5 * time.Second
+)
+
 type Server struct {
 	log           zerolog.Logger
 	listenPort    uint32
 	listenAddress string
 	socket        net.Listener
+	server        *grpc.Server
+	stopped       chan struct{}
+	serving       sync.WaitGroup
 
 	gameapiHandler *GameApiHandler
 }
@@ -40,22 +49,67 @@ func (s *Server) Start(ctx context.Context) error {
 		return errors.Wrap(err, "unable to start network listener")
 	}
 
-	server := grpc.NewServer()
+	s.server = grpc.NewServer()
+	s.stopped = make(chan struct{})
+
+	gameapi.RegisterApiServer(s.server, s.gameapiHandler)
 
-	gameapi.RegisterApiServer(server, s.gameapiHandler)
+	s.serving.Add(1)
 
 	go func() {
+		defer s.serving.Done()
+
 		for {
 			log.Info().Msg("Listening for connections.")
-			if err := server.Serve(s.socket); err != nil {
+			err := s.server.Serve(s.socket)
+
+			select {
+			case <-s.stopped:
+				return
+			default:
+			}
+
+			if err != nil {
 				backOffDuration := time.Second * 5
 				log.Error().Dur("backOffDuration", backOffDuration).
 					Err(err).
 					Msg("Unable to start gRPC API server. Backing off.")
-				time.Sleep(backOffDuration)
+
+				select {
+				case <-s.stopped:
+					return
+				case <-time.After(backOffDuration):
+				}
 			}
 		}
 	}()
 
 	return nil
 }
+
+// Stop stops accepting connections and waits for running calls. Calls still running after stop timeout are canceled.
+func (s *Server) Stop() {
+	if s.server == nil {
+		return
+	}
+
+	close(s.stopped)
+
+	gracefullyStopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(gracefullyStopped)
+	}()
+
+	select {
+	case <-gracefullyStopped:
+	case <-time.After(serverStopTimeout):
+		s.log.Warn().Dur("stopTimeout", serverStopTimeout).Msg("Running calls did not finish in time. Canceling them.")
+		s.server.Stop()
+		<-gracefullyStopped
+	}
+
+	s.serving.Wait()
+
+	s.log.Info().Msg("Stopped.")
+}
@@ -6,7 +6,9 @@ import (
 	"github.com/dominati-one/backend/internal/pkg/blockchain"
 	"github.com/dominati-one/backend/internal/pkg/blockchain/local"
 	"github.com/dominati-one/backend/internal/pkg/blockchain/network"
+	"github.com/dominati-one/backend/internal/pkg/blockchain/p2p"
 	"github.com/dominati-one/backend/internal/pkg/game"
+	"github.com/dominati-one/backend/internal/pkg/security"
 	"github.com/pkg/errors"
 	"time"
 )
@@ -14,6 +16,18 @@ import (
 type AppParameters struct {
 	GrpcApiListenPort    uint32
 	GrpcApiListenAddress string
+	PrivateKey           *security.PrivateKey
+	// AuthorityPublicKeys are keys of authorities trusted to seal blocks. Node must trust at least one authority.
+	AuthorityPublicKeys *security.PublicKeysBag
+	// DataDirectory is directory for persistent storage. Blocks are kept in memory only, when empty.
+	DataDirectory string
+	// SnapshotInterval is number of blocks between game snapshots. Snapshots are disabled, when zero or when data
+	// directory is not set.
+	SnapshotInterval int
+	// PeerListenAddress is host:port for gossip with other nodes. Node runs without peers, when empty.
+	PeerListenAddress string
+	// Peers are host:port addresses of other nodes.
+	Peers []string
 }
 
 type App struct {
@@ -21,44 +35,123 @@ type App struct {
 	game                *game.Game
 	grpcApiServer       *grpc.Server
 	blockchainConnector blockchain.Connector
+	peerConnector       *p2p.Connector
 	blockchain          *blockchain.Network
+	blockReplayer       *BlockReplayer
 	eventPump           *EventPump
+	// fileBlockStorage and fileLocalEventJournal are flushed on stop. Both are nil without data directory.
+	fileBlockStorage      *FileBlockStorage
+	fileLocalEventJournal *FileLocalEventJournal
+	cancelNetwork         context.CancelFunc
+	cancelEventPump       context.CancelFunc
 }
 
-func NewApp(parameters AppParameters) *App {
+func NewApp(parameters AppParameters) (*App, error) {
+	if parameters.AuthorityPublicKeys == nil || len(parameters.AuthorityPublicKeys.Keys()) == 0 {
+		return nil, ErrAppNoAuthorityPublicKeys
+	}
+
 	game := game.NewGame()
 
 	blockchainSettings := blockchain.NetworkSettings{
 		BlockInterval:       10 * time.Second,
-		AuthorityPublicKeys: network.CreateTestNetAuthority(),
+		BlockSlotTimeout:    5 * time.Second,
+		AuthorityPublicKeys: parameters.AuthorityPublicKeys,
 		GenesisBlock:        network.CreateTestNetGenesisBlock(),
 	}
 
-	blockchainConnector := local.NewConnector()
 	blockchainEventStorage := NewEventStorage()
-	blockchainBlockStorage := NewBlockStorage()
-	blockchain := blockchain.NewNetwork(blockchainSettings, blockchainConnector, blockchainEventStorage, blockchainBlockStorage)
+	var blockchainBlockStorage blockchain.BlockStorage = NewBlockStorage()
+	var snapshotStorage *SnapshotStorage
+	var localEventJournal blockchain.LocalEventJournal
+	var fileBlockStorage *FileBlockStorage
+	var fileLocalEventJournal *FileLocalEventJournal
+	if parameters.DataDirectory != "" {
+		var err error
+		fileBlockStorage, err = NewFileBlockStorage(parameters.DataDirectory, FileBlockStorageOptions{
+			SyncPolicy: FileBlockStorageSyncAlways,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to open block storage")
+		}
+		blockchainBlockStorage = fileBlockStorage
+
+		snapshotStorage, err = NewSnapshotStorage(parameters.DataDirectory, DefaultSnapshotStorageKeepCount)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to open snapshot storage")
+		}
+
+		fileLocalEventJournal, err = NewFileLocalEventJournal(parameters.DataDirectory)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to open local event journal")
+		}
+		localEventJournal = fileLocalEventJournal
+	}
+
+	var blockchainConnector blockchain.Connector = local.NewConnector()
+	var peerConnector *p2p.Connector
+	if parameters.PeerListenAddress != "" {
+		gossipEventValidator := blockchain.NewEventValidator(nil, blockchainSettings)
+		gossipBlockValidator := blockchain.NewBlockValidator(gossipEventValidator, nil, nil, blockchainSettings)
+
+		peerConnector = p2p.NewConnector(p2p.ConnectorSettings{
+			ListenAddress: parameters.PeerListenAddress,
+			Peers:         parameters.Peers,
+		}, blockchainBlockStorage, gossipEventValidator, gossipBlockValidator)
+		blockchainConnector = peerConnector
+	}
+
+	eventResults := NewEventResults(DefaultEventResultsMaxCount)
+	blockReplayer := NewBlockReplayer(blockchainBlockStorage, blockchainEventStorage, eventResults, snapshotStorage, game)
 
-	gameApiHandler := grpc.NewGameApiHandler(game, blockchain.LocalEventBacklog())
+	gameLock := NewGameLock()
+	stateHasher := NewGameStateHasher(game, gameLock, blockchainBlockStorage)
+	stateRewinder := NewGameStateRewinder(game, gameLock, blockchainBlockStorage, blockReplayer)
+
+	blockchain := blockchain.NewNetwork(blockchainSettings, blockchainConnector, blockchainEventStorage, blockchainBlockStorage, stateHasher, stateRewinder, localEventJournal, parameters.PrivateKey)
+
+	gameApiHandler := grpc.NewGameApiHandler(game, blockchain.LocalEventBacklog(), blockchain.EventEmitter(), blockchainBlockStorage, blockchainEventStorage, eventResults)
 	grpcApiServer := grpc.NewServer(parameters.GrpcApiListenPort, parameters.GrpcApiListenAddress, gameApiHandler)
 
-	eventPump := NewEventPump(blockchain.EventEmitter(), game)
+	eventPump := NewEventPump(blockchain.EventEmitter(), game, gameLock, eventResults, blockchainBlockStorage, snapshotStorage, parameters.SnapshotInterval)
 
 	return &App{
-		parameters:          parameters,
-		game:                game,
-		grpcApiServer:       grpcApiServer,
-		blockchainConnector: blockchainConnector,
-		blockchain:          blockchain,
-		eventPump:           eventPump,
-	}
+		parameters:            parameters,
+		game:                  game,
+		grpcApiServer:         grpcApiServer,
+		blockchainConnector:   blockchainConnector,
+		peerConnector:         peerConnector,
+		blockchain:            blockchain,
+		blockReplayer:         blockReplayer,
+		eventPump:             eventPump,
+		fileBlockStorage:      fileBlockStorage,
+		fileLocalEventJournal: fileLocalEventJournal,
+	}, nil
 }
 
+// Start starts all components. App is shut down by Stop, components have separate contexts derived from given one, so
+// network can be stopped, while event pump still applies blocks it emitted.
 func (a *App) Start(ctx context.Context) error {
+	eventPumpCtx, cancelEventPump := context.WithCancel(ctx)
+	a.cancelEventPump = cancelEventPump
+
+	ctx, cancelNetwork := context.WithCancel(ctx)
+	a.cancelNetwork = cancelNetwork
+
 	if err := a.game.Start(ctx); err != nil {
 		return errors.Wrap(err, "error while starting game")
 	}
 
+	if err := a.blockReplayer.Replay(ctx); err != nil {
+		return errors.Wrap(err, "error while replaying stored blocks")
+	}
+
+	if a.peerConnector != nil {
+		if err := a.peerConnector.Start(ctx); err != nil {
+			return errors.Wrap(err, "error while starting peer connector")
+		}
+	}
+
 	if err := a.blockchain.Start(ctx); err != nil {
 		return errors.Wrap(err, "error while starting blockchain")
 
@@ -68,9 +161,49 @@ func (a *App) Start(ctx context.Context) error {
 		return errors.Wrap(err, "error while starting game gRPC API server")
 	}
 
-	if err := a.eventPump.Start(ctx); err != nil {
+	if err := a.eventPump.Start(eventPumpCtx); err != nil {
 		return errors.Wrap(err, "error while starting event pump")
 	}
 
 	return nil
 }
+
+// Failed receives error, when app can not continue, because game could not apply block. App has to be stopped then.
+func (a *App) Failed() <-chan error {
+	return a.eventPump.Failed()
+}
+
+// Stop shuts app down. API server stops first, so no new events are accepted. Network stops next, while event pump
+// still applies emitted blocks, because block validation waits for them. Persistent storage is flushed last.
+func (a *App) Stop() error {
+	a.grpcApiServer.Stop()
+
+	a.cancelNetwork()
+	a.blockchain.Wait()
+
+	if a.peerConnector != nil {
+		a.peerConnector.Stop()
+		a.peerConnector.Wait()
+	}
+
+	a.cancelEventPump()
+	a.eventPump.Wait()
+
+	if a.fileLocalEventJournal != nil {
+		if err := a.fileLocalEventJournal.Close(); err != nil {
+			return errors.Wrap(err, "unable to close local event journal")
+		}
+	}
+
+	if a.fileBlockStorage != nil {
+		if err := a.fileBlockStorage.Close(); err != nil {
+			return errors.Wrap(err, "unable to close block storage")
+		}
+	}
+
+	return nil
+}
+
+var (
+	ErrAppNoAuthorityPublicKeys = errors.New("app no authority public keys")
+)
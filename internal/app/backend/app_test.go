@@ -0,0 +1,46 @@
+package backend
+
+import (
+	"context"
+	"github.com/dominati-one/backend/internal/pkg/blockchain/network"
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestApp_StartStop(t *testing.T) {
+	goroutinesCount := runtime.NumGoroutine()
+
+	app, err := NewApp(AppParameters{
+		GrpcApiListenAddress: "127.0.0.1",
+		PrivateKey:           network.CreateDevAuthorityPrivateKey(),
+		AuthorityPublicKeys:  network.CreateDevAuthority(),
+		DataDirectory:        t.TempDir(),
+		SnapshotInterval:     1,
+		PeerListenAddress:    "127.0.0.1:0",
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, app.Start(context.TODO()))
+	time.Sleep(200 * time.Millisecond)
+	assert.NoError(t, app.Stop())
+
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > goroutinesCount && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if leakedCount := runtime.NumGoroutine() - goroutinesCount; leakedCount > 0 {
+		buffer := make([]byte, 1<<20)
+		t.Fatalf("%d goroutines leaked:\n%s", leakedCount, buffer[:runtime.Stack(buffer, true)])
+	}
+}
+
+func TestNewApp_NoAuthorityPublicKeys(t *testing.T) {
+	_, err := NewApp(AppParameters{
+		GrpcApiListenAddress: "127.0.0.1",
+		PrivateKey:           network.CreateDevAuthorityPrivateKey(),
+	})
+	assert.ErrorIs(t, err, ErrAppNoAuthorityPublicKeys)
+}
@@ -0,0 +1,193 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	"github.com/dominati-one/backend/internal/pkg/game"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+const (
+	blockReplayerProgressInterval = 1000
+)
+
+// BlockReplayer rebuilds game world from blocks already present in block storage.
+type BlockReplayer struct {
+	log             zerolog.Logger
+	blockStorage    blockchain.BlockStorage
+	eventStorage    blockchain.EventStorage
+	eventResults    *EventResults
+	snapshotStorage *SnapshotStorage
+	game            *game.Game
+}
+
+// NewBlockReplayer creates replayer. Snapshot storage is optional, whole chain is replayed without it. Outcome of events
+// replayed into main game is recorded in event results.
+func NewBlockReplayer(blockStorage blockchain.BlockStorage, eventStorage blockchain.EventStorage, eventResults *EventResults, snapshotStorage *SnapshotStorage, game *game.Game) *BlockReplayer {
+	return &BlockReplayer{
+		log:             log.With().Str("applicationComponent", "blockReplayer").Logger(),
+		blockStorage:    blockStorage,
+		eventStorage:    eventStorage,
+		eventResults:    eventResults,
+		snapshotStorage: snapshotStorage,
+		game:            game,
+	}
+}
+
+// Replay applies stored blocks in order. Genesis block is skipped, because it is never emitted to game by network.
+// When snapshot is restored, blocks up to its height only fill event storage and are not applied to game again.
+func (r *BlockReplayer) Replay(ctx context.Context) error {
+	blocksCount := r.blockStorage.Count()
+	if blocksCount <= 1 {
+		r.log.Info().Msg("No blocks to replay.")
+		return nil
+	}
+
+	return r.replay(ctx, r.game, blocksCount-1, true)
+}
+
+// ReplayInto rebuilds given game from snapshot and stored blocks up to given height. Event storage is not touched.
+func (r *BlockReplayer) ReplayInto(ctx context.Context, game *game.Game, toHeight int) error {
+	if toHeight >= r.blockStorage.Count() {
+		return errors.Wrapf(ErrBlockReplayInconsistentChain, "no block at height %d", toHeight)
+	}
+
+	return r.replay(ctx, game, toHeight, false)
+}
+
+func (r *BlockReplayer) replay(ctx context.Context, game *game.Game, toHeight int, fillEventStorage bool) error {
+	blocksCount := toHeight + 1
+
+	startTime := time.Now()
+
+	snapshotHeight := r.restoreSnapshot(game, toHeight)
+
+	r.log.Info().Int("blocksCount", blocksCount).Int("snapshotHeight", snapshotHeight).Msg("Replay started.")
+
+	previousBlock, err := r.blockStorage.GetByHeight(0)
+	if err != nil {
+		return errors.Wrap(err, "unable to read genesis block")
+	}
+
+	for height := 1; height < blocksCount; height++ {
+		if ctx.Err() != nil {
+			return ErrCanceledBlockReplay
+		}
+
+		block, err := r.blockStorage.GetByHeight(height)
+		if err != nil {
+			return errors.Wrapf(err, "unable to read block at height %d", height)
+		}
+
+		previousBlockId, err := blockchain.NewBlockId(previousBlock)
+		if err != nil {
+			return errors.Wrapf(err, "unable to calculate block id at height %d", height-1)
+		}
+
+		if !bytes.Equal(previousBlockId.Bytes(), block.Body.PreviousBlockId) {
+			return errors.Wrapf(ErrBlockReplayInconsistentChain, "block at height %d", height)
+		}
+
+		applyToGame := height > snapshotHeight
+
+		if !applyToGame && !fillEventStorage {
+			previousBlock = block
+			continue
+		}
+
+		var eventErrors []error
+
+		if applyToGame {
+			// Block is applied as whole with recovery from panic, exactly like in event pump. Rejected event leaves
+			// game untouched.
+			eventErrors, err = game.ApplyBlock(block)
+			if err != nil {
+				return errors.Wrapf(err, "unable to apply block at height %d", height)
+			}
+		}
+
+		if fillEventStorage {
+			blockId, err := blockchain.NewBlockId(block)
+			if err != nil {
+				return errors.Wrapf(err, "unable to calculate block id at height %d", height)
+			}
+
+			for eventIndex, blockEvent := range block.Body.Events {
+				eventId, err := r.eventStorage.Add(blockEvent.Event, *blockId)
+				if err != nil {
+					return errors.Wrapf(err, "unable to add event of block at height %d to storage", height)
+				}
+
+				if applyToGame {
+					r.eventResults.Record(eventId, eventErrors[eventIndex])
+				}
+			}
+		}
+
+		if height%blockReplayerProgressInterval == 0 {
+			r.log.Info().Int("height", height).Int("blocksCount", blocksCount).Msg("Replay in progress.")
+		}
+
+		previousBlock = block
+	}
+
+	r.log.Info().
+		Int("blocksCount", blocksCount).
+		Dur("replayDuration", time.Since(startTime)).
+		Msg("Replay finished.")
+
+	return nil
+}
+
+// restoreSnapshot loads newest usable snapshot not above given height into game and returns its height, or zero when
+// none was restored.
+func (r *BlockReplayer) restoreSnapshot(game *game.Game, maxHeight int) int {
+	if r.snapshotStorage == nil {
+		return 0
+	}
+
+	heights, err := r.snapshotStorage.Heights()
+	if err != nil {
+		r.log.Warn().Err(err).Msg("Unable to list snapshots. Replaying whole chain.")
+		return 0
+	}
+
+	for _, height := range heights {
+		if height <= 0 || height > maxHeight {
+			r.log.Debug().Int("height", height).Msg("Snapshot is ahead of replayed chain. Skipping.")
+			continue
+		}
+
+		block, err := r.blockStorage.GetByHeight(height)
+		if err != nil {
+			r.log.Warn().Err(err).Int("height", height).Msg("Unable to read block of snapshot. Skipping.")
+			continue
+		}
+
+		blockId, err := blockchain.NewBlockId(block)
+		if err != nil {
+			r.log.Warn().Err(err).Int("height", height).Msg("Unable to calculate block id of snapshot. Skipping.")
+			continue
+		}
+
+		if err := r.snapshotStorage.Load(height, *blockId, game); err != nil {
+			r.log.Warn().Err(err).Int("height", height).Msg("Unable to load snapshot. Skipping.")
+			continue
+		}
+
+		r.log.Info().Int("height", height).Str("blockId", blockId.String()).Msg("Snapshot restored.")
+
+		return height
+	}
+
+	return 0
+}
+
+var (
+	ErrCanceledBlockReplay          = errors.New("canceled block replay")
+	ErrBlockReplayInconsistentChain = errors.New("block replay inconsistent chain")
+)
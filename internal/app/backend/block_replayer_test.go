@@ -0,0 +1,212 @@
+package backend
+
+import (
+	"context"
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	"github.com/dominati-one/backend/internal/pkg/game"
+	"github.com/dominati-one/backend/internal/pkg/game/world"
+	"github.com/dominati-one/backend/internal/pkg/game/world/component"
+	"github.com/dominati-one/backend/internal/pkg/security"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBlockReplayer_Replay(t *testing.T) {
+	blockStorage := NewBlockStorage()
+	gameInstance := game.NewGame()
+
+	genesisBlock := createTestBlock(1000)
+	_, err := blockStorage.Add(genesisBlock)
+	assert.NoError(t, err)
+
+	firstBlock := createTestChildBlock(t, genesisBlock, 2000)
+	_, err = blockStorage.Add(firstBlock)
+	assert.NoError(t, err)
+
+	_, err = blockStorage.Add(createTestChildBlock(t, firstBlock, 3000))
+	assert.NoError(t, err)
+
+	err = NewBlockReplayer(blockStorage, NewEventStorage(), NewEventResults(0), nil, gameInstance).Replay(context.TODO())
+	assert.NoError(t, err)
+
+	_, err = gameInstance.WorldClock().SetCurrentTimestamp(2999)
+	assert.ErrorIs(t, err, world.ErrCurrentTimeLessThanLastTimeEvent)
+}
+
+func TestBlockReplayer_ReplayInconsistentChain(t *testing.T) {
+	blockStorage := NewBlockStorage()
+
+	genesisBlock := createTestBlock(1000)
+	_, err := blockStorage.Add(genesisBlock)
+	assert.NoError(t, err)
+
+	_, err = blockStorage.Add(createTestBlock(2000))
+	assert.NoError(t, err)
+
+	err = NewBlockReplayer(blockStorage, NewEventStorage(), NewEventResults(0), nil, game.NewGame()).Replay(context.TODO())
+	assert.Error(t, err)
+}
+
+func TestBlockReplayer_ReplayRecordsEventResults(t *testing.T) {
+	blockStorage := NewBlockStorage()
+	eventResults := NewEventResults(0)
+
+	genesisBlock := createTestBlock(1000)
+	_, err := blockStorage.Add(genesisBlock)
+	assert.NoError(t, err)
+
+	unsignedEvent := &blockchainProtocol.Event{
+		Body: &blockchainProtocol.Event_Body{
+			Event: &blockchainProtocol.Event_Body_CreatePlanet{CreatePlanet: &blockchainProtocol.EventCreatePlanet{}},
+		},
+		Timestamp: 2000,
+	}
+	unsignedEventId, err := blockchain.NewEventId(unsignedEvent)
+	assert.NoError(t, err)
+
+	firstBlock := createTestChildBlock(t, genesisBlock, 2000)
+	firstBlock.Body.Events = []*blockchainProtocol.Block_Body_BlockEvent{{Id: unsignedEventId.Bytes(), Event: unsignedEvent}}
+	_, err = blockStorage.Add(firstBlock)
+	assert.NoError(t, err)
+
+	err = NewBlockReplayer(blockStorage, NewEventStorage(), eventResults, nil, game.NewGame()).Replay(context.TODO())
+	assert.NoError(t, err)
+
+	applyError, exists := eventResults.Get(unsignedEventId)
+	assert.True(t, exists)
+	assert.NotEmpty(t, applyError)
+}
+
+func TestBlockReplayer_ReplayRejectedEvent(t *testing.T) {
+	blockStorage := NewBlockStorage()
+	eventResults := NewEventResults(0)
+
+	privateKey, err := security.GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	rejectedEvent := &blockchainProtocol.Event{
+		Body: &blockchainProtocol.Event_Body{
+			Event: &blockchainProtocol.Event_Body_CreatePlayer{CreatePlayer: &blockchainProtocol.EventCreatePlayer{PublicKey: privateKey.PublicKey(), Name: "Rejected"}},
+		},
+		Timestamp: 2000,
+	}
+	rejectedEventId, err := blockchain.NewEventId(rejectedEvent)
+	assert.NoError(t, err)
+
+	appliedEvent := &blockchainProtocol.Event{
+		Body: &blockchainProtocol.Event_Body{
+			Event: &blockchainProtocol.Event_Body_CreatePlayer{CreatePlayer: &blockchainProtocol.EventCreatePlayer{PublicKey: privateKey.PublicKey(), Name: "Applied"}},
+		},
+		Timestamp: 2000,
+	}
+	signature, err := security.CreateSignatureFromBody(appliedEvent.Body, privateKey)
+	assert.NoError(t, err)
+	appliedEvent.Signature = signature.Bytes()
+	appliedEventId, err := blockchain.NewEventId(appliedEvent)
+	assert.NoError(t, err)
+
+	genesisBlock := createTestBlock(1000)
+	_, err = blockStorage.Add(genesisBlock)
+	assert.NoError(t, err)
+
+	firstBlock := createTestChildBlock(t, genesisBlock, 2000)
+	firstBlock.Body.Events = []*blockchainProtocol.Block_Body_BlockEvent{
+		{Id: rejectedEventId.Bytes(), Event: rejectedEvent},
+		{Id: appliedEventId.Bytes(), Event: appliedEvent},
+	}
+	_, err = blockStorage.Add(firstBlock)
+	assert.NoError(t, err)
+
+	secondBlock := createTestChildBlock(t, firstBlock, 3000)
+	_, err = blockStorage.Add(secondBlock)
+	assert.NoError(t, err)
+
+	gameInstance := game.NewGame()
+
+	// Rejected event does not stop replay, events and blocks after it are applied.
+	err = NewBlockReplayer(blockStorage, NewEventStorage(), eventResults, nil, gameInstance).Replay(context.TODO())
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, gameInstance.State().Player().Count())
+
+	_, err = gameInstance.WorldClock().SetCurrentTimestamp(2999)
+	assert.ErrorIs(t, err, world.ErrCurrentTimeLessThanLastTimeEvent)
+
+	applyError, exists := eventResults.Get(rejectedEventId)
+	assert.True(t, exists)
+	assert.NotEmpty(t, applyError)
+
+	applyError, exists = eventResults.Get(appliedEventId)
+	assert.True(t, exists)
+	assert.Empty(t, applyError)
+
+	// Replayed game matches game, to which pump applied the same blocks.
+	pumpGame := game.NewGame()
+	pumpGameLock := NewGameLock()
+	pump := NewEventPump(blockchain.NewEventEmitter(), pumpGame, pumpGameLock, NewEventResults(0), NewBlockStorage(), nil, 0)
+
+	_, err = pumpGame.WorldClock().SetCurrentTimestamp(genesisBlock.Body.Timestamp)
+	assert.NoError(t, err)
+	pumpGameLock.Lock()
+	pumpGameLock.blockApplied(0)
+	pumpGameLock.Unlock()
+
+	assert.NoError(t, pump.applyBlock(firstBlock))
+	assert.NoError(t, pump.applyBlock(secondBlock))
+
+	stateHash, err := gameInstance.State().Hash()
+	assert.NoError(t, err)
+	pumpStateHash, err := pumpGame.State().Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, stateHash, pumpStateHash)
+}
+
+func createTestChildBlock(t *testing.T, previousBlock *blockchainProtocol.Block, timestamp uint64) *blockchainProtocol.Block {
+	previousBlockId, err := blockchain.NewBlockId(previousBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := createTestBlock(timestamp)
+	block.Body.PreviousBlockId = previousBlockId.Bytes()
+
+	return block
+}
+
+func TestBlockReplayer_ReplayFromSnapshot(t *testing.T) {
+	blockStorage := NewBlockStorage()
+
+	snapshotStorage, err := NewSnapshotStorage(t.TempDir(), 2)
+	assert.NoError(t, err)
+
+	genesisBlock := createTestBlock(1000)
+	_, err = blockStorage.Add(genesisBlock)
+	assert.NoError(t, err)
+
+	firstBlock := createTestChildBlock(t, genesisBlock, 2000)
+	firstBlockId, err := blockStorage.Add(firstBlock)
+	assert.NoError(t, err)
+
+	_, err = blockStorage.Add(createTestChildBlock(t, firstBlock, 3000))
+	assert.NoError(t, err)
+
+	snapshotGame := game.NewGame()
+	_, err = snapshotGame.WorldClock().SetCurrentTimestamp(2000)
+	assert.NoError(t, err)
+	snapshotEntity := snapshotGame.State().Create(component.EntityKindUnknown)
+	assert.NoError(t, snapshotStorage.Save(1, *firstBlockId, snapshotGame))
+
+	// Snapshot of different chain at same height must be ignored.
+	assert.NoError(t, snapshotStorage.Save(2, blockchain.BlockId{}, snapshotGame))
+
+	gameInstance := game.NewGame()
+
+	err = NewBlockReplayer(blockStorage, NewEventStorage(), NewEventResults(0), snapshotStorage, gameInstance).Replay(context.TODO())
+	assert.NoError(t, err)
+
+	assert.True(t, gameInstance.State().Exists(snapshotEntity))
+
+	_, err = gameInstance.WorldClock().SetCurrentTimestamp(2999)
+	assert.ErrorIs(t, err, world.ErrCurrentTimeLessThanLastTimeEvent)
+}
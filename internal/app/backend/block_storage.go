@@ -5,26 +5,38 @@ import (
 	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
+	"sync"
 )
 
+// BlockStorage keeps blocks in memory. It is safe for concurrent use, blocks are read by gRPC handlers while
+// receiver adds and truncates them.
 type BlockStorage struct {
-	blocks     []*blockchainProtocol.Block
-	blocksById map[blockchain.BlockId]*blockchainProtocol.Block
-	lastBlock  *blockchainProtocol.Block
+	state       sync.RWMutex
+	blocks      []*blockchainProtocol.Block
+	blocksById  map[blockchain.BlockId]*blockchainProtocol.Block
+	heightsById map[blockchain.BlockId]int
+	lastBlock   *blockchainProtocol.Block
 }
 
 func NewBlockStorage() *BlockStorage {
 	return &BlockStorage{
-		blocks:     []*blockchainProtocol.Block{},
-		blocksById: map[blockchain.BlockId]*blockchainProtocol.Block{},
+		blocks:      []*blockchainProtocol.Block{},
+		blocksById:  map[blockchain.BlockId]*blockchainProtocol.Block{},
+		heightsById: map[blockchain.BlockId]int{},
 	}
 }
 
 func (s *BlockStorage) Count() int {
+	defer s.state.RUnlock()
+	s.state.RLock()
+
 	return len(s.blocks)
 }
 
 func (s *BlockStorage) Add(block *blockchainProtocol.Block) (*blockchain.BlockId, error) {
+	defer s.state.Unlock()
+	s.state.Lock()
+
 	blockId, err := blockchain.NewBlockId(block)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to add block")
@@ -34,13 +46,40 @@ func (s *BlockStorage) Add(block *blockchainProtocol.Block) (*blockchain.BlockId
 
 	s.blocks = append(s.blocks, blockCopy)
 	s.blocksById[*blockId] = blockCopy
+	s.heightsById[*blockId] = len(s.blocks) - 1
 
 	s.lastBlock = blockCopy
 
 	return blockId, nil
 }
 
+func (s *BlockStorage) Get(blockId blockchain.BlockId) (*blockchainProtocol.Block, error) {
+	defer s.state.RUnlock()
+	s.state.RLock()
+
+	block, exists := s.blocksById[blockId]
+	if !exists {
+		return nil, ErrBlockNotFoundInStorage
+	}
+
+	return block, nil
+}
+
+func (s *BlockStorage) GetByHeight(height int) (*blockchainProtocol.Block, error) {
+	defer s.state.RUnlock()
+	s.state.RLock()
+
+	if height < 0 || height >= len(s.blocks) {
+		return nil, ErrBlockNotFoundInStorage
+	}
+
+	return s.blocks[height], nil
+}
+
 func (s *BlockStorage) GetLatestBlock() (*blockchainProtocol.Block, error) {
+	defer s.state.RUnlock()
+	s.state.RLock()
+
 	if s.lastBlock == nil || len(s.blocks) == 0 {
 		return nil, ErrNoBlockInStorage
 	}
@@ -48,11 +87,52 @@ func (s *BlockStorage) GetLatestBlock() (*blockchainProtocol.Block, error) {
 	return s.lastBlock, nil
 }
 
+func (s *BlockStorage) GetHeight(blockId blockchain.BlockId) (int, error) {
+	defer s.state.RUnlock()
+	s.state.RLock()
+
+	height, exists := s.heightsById[blockId]
+	if !exists {
+		return 0, ErrBlockNotFoundInStorage
+	}
+
+	return height, nil
+}
+
+// Truncate removes blocks above given height.
+func (s *BlockStorage) Truncate(height int) error {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	if height < 0 || height >= len(s.blocks) {
+		return ErrBlockNotFoundInStorage
+	}
+
+	for _, block := range s.blocks[height+1:] {
+		blockId, err := blockchain.NewBlockId(block)
+		if err != nil {
+			return errors.Wrap(err, "unable to truncate block storage")
+		}
+
+		delete(s.blocksById, *blockId)
+		delete(s.heightsById, *blockId)
+	}
+
+	s.blocks = s.blocks[:height+1]
+	s.lastBlock = s.blocks[height]
+
+	return nil
+}
+
 func (s *BlockStorage) Exists(blockId blockchain.BlockId) bool {
+	defer s.state.RUnlock()
+	s.state.RLock()
+
 	_, exists := s.blocksById[blockId]
 	return exists
 }
 
 var (
-	ErrNoBlockInStorage = errors.New("no blocks in storage")
+	ErrNoBlockInStorage       = errors.New("no blocks in storage")
+	ErrBlockNotFoundInStorage = errors.New("block not found in storage")
 )
@@ -0,0 +1,75 @@
+package backend
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestBlockStorage_Truncate(t *testing.T) {
+	storage := NewBlockStorage()
+
+	firstBlockId, err := storage.Add(createTestBlock(1))
+	assert.NoError(t, err)
+	secondBlockId, err := storage.Add(createTestBlock(2))
+	assert.NoError(t, err)
+
+	assert.NoError(t, storage.Truncate(0))
+	assert.Equal(t, 1, storage.Count())
+	assert.True(t, storage.Exists(*firstBlockId))
+	assert.False(t, storage.Exists(*secondBlockId))
+
+	assert.ErrorIs(t, storage.Truncate(1), ErrBlockNotFoundInStorage)
+}
+
+// TestBlockStorage_Concurrent is meant to be run with -race.
+func TestBlockStorage_Concurrent(t *testing.T) {
+	storage := NewBlockStorage()
+
+	genesisBlockId, err := storage.Add(createTestBlock(1))
+	assert.NoError(t, err)
+
+	wait := sync.WaitGroup{}
+	started := sync.WaitGroup{}
+	done := make(chan struct{})
+
+	for i := 0; i < 4; i++ {
+		wait.Add(1)
+		started.Add(1)
+		go func() {
+			defer wait.Done()
+			started.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				storage.Count()
+				storage.Exists(*genesisBlockId)
+				_, _ = storage.Get(*genesisBlockId)
+				_, _ = storage.GetHeight(*genesisBlockId)
+				_, _ = storage.GetByHeight(1)
+				_, _ = storage.GetLatestBlock()
+			}
+		}()
+	}
+
+	started.Wait()
+
+	for timestamp := uint64(2); timestamp < 200; timestamp++ {
+		_, err := storage.Add(createTestBlock(timestamp))
+		assert.NoError(t, err)
+
+		if timestamp%10 == 0 {
+			assert.NoError(t, storage.Truncate(0))
+		}
+	}
+
+	close(done)
+	wait.Wait()
+
+	assert.Equal(t, 10, storage.Count())
+}
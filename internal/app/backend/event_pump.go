@@ -4,80 +4,191 @@ import (
 	"context"
 	"github.com/dominati-one/backend/internal/pkg/blockchain"
 	"github.com/dominati-one/backend/internal/pkg/game"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"sync"
 )
 
 type EventPump struct {
-	log          zerolog.Logger
-	eventEmitter *blockchain.EventEmitter
-	game         *game.Game
-	applyMutex   sync.Mutex
+	log              zerolog.Logger
+	subscription     *blockchain.EventEmitterSubscription
+	game             *game.Game
+	blockStorage     blockchain.BlockStorage
+	snapshotStorage  *SnapshotStorage
+	snapshotInterval int
+	gameLock         *GameLock
+	eventResults     *EventResults
+	failed           chan error
+	loops            sync.WaitGroup
 }
 
-func NewEventPump(eventEmitter *blockchain.EventEmitter, game *game.Game) *EventPump {
+// NewEventPump creates pump applying emitted blocks with their events to game. Snapshot of game is saved every
+// snapshotInterval blocks, when snapshot storage is set. Game lock guards game against concurrent users and tracks
+// height of applied block, which moves back, when state is rewound on chain reorganization. Outcome of every applied
+// event is recorded in event results. Pump subscribes to event emitter right away, so no block emitted before start is
+// missed.
+func NewEventPump(eventEmitter *blockchain.EventEmitter, game *game.Game, gameLock *GameLock, eventResults *EventResults, blockStorage blockchain.BlockStorage, snapshotStorage *SnapshotStorage, snapshotInterval int) *EventPump {
 	return &EventPump{
-		log:          log.With().Str("applicationComponent", "eventPump").Logger(),
-		eventEmitter: eventEmitter,
-		game:         game,
+		log: log.With().Str("applicationComponent", "eventPump").Logger(),
+		subscription: eventEmitter.Subscribe(blockchain.EventEmitterSubscriptionSettings{
+			Name:       "eventPump",
+			Policy:     blockchain.EventEmitterPolicyBlock,
+			BlocksOnly: true,
+		}),
+		game:             game,
+		gameLock:         gameLock,
+		eventResults:     eventResults,
+		blockStorage:     blockStorage,
+		snapshotStorage:  snapshotStorage,
+		snapshotInterval: snapshotInterval,
+		failed:           make(chan error, 1),
 	}
 }
 
 func (p *EventPump) Start(ctx context.Context) error {
-	go p.applyCurrentTimeLoop(ctx)
-	go p.applyEventLoop(ctx)
+	if p.blockStorage.Count() == 0 {
+		return errors.Wrap(ErrNoBlockInStorage, "unable to start event pump")
+	}
+
+	p.gameLock.Lock()
+	p.gameLock.blockApplied(p.blockStorage.Count() - 1)
+	p.gameLock.Unlock()
+
+	p.loops.Add(1)
+
+	go func() {
+		defer p.loops.Done()
+		p.applyLoop(ctx)
+	}()
 
 	p.log.Info().Msg("Started.")
 
 	return nil
 }
 
-func (p *EventPump) applyEventLoop(ctx context.Context) {
+// Failed receives error, when pump stopped, because block could not be applied. Game does not follow chain anymore,
+// so node has to be stopped.
+func (p *EventPump) Failed() <-chan error {
+	return p.failed
+}
+
+// Wait blocks until pump loop finished. Loop finishes, when context passed to Start is done. Blocks emitted, but not
+// applied yet, are already stored and are replayed on next start.
+func (p *EventPump) Wait() {
+	p.loops.Wait()
+}
+
+// applyLoop applies emitted blocks one by one. Pump stops, when block can not be applied, so game stays at last block
+// boundary instead of diverging from chain. Users of game lock waiting for further blocks are released with error and
+// failure is reported, so node can be stopped.
+func (p *EventPump) applyLoop(ctx context.Context) {
+	defer p.subscription.Unsubscribe()
+
 	for {
 		if ctx.Err() != nil {
+			p.stop(ErrEventPumpStopped)
 			return
 		}
 
-		event, err := p.eventEmitter.WaitForEvent(ctx)
+		message, err := p.subscription.Wait(ctx)
+		if ctx.Err() != nil {
+			p.stop(ErrEventPumpStopped)
+			return
+		}
+		if err == blockchain.ErrEventEmitterSubscriptionClosed {
+			p.log.Error().Err(err).Msg("Event emitter subscription closed. Blocks will not be applied.")
+			p.fail(err)
+			return
+		}
 		if err != nil {
-			p.log.Error().Err(err).Msg("Waiting for event failed.")
+			p.log.Error().Err(err).Msg("Waiting for block failed.")
 			continue
 		}
 
-		p.applyMutex.Lock()
-
-		if err = p.game.ApplyEvent(event); err != nil {
-			p.log.Panic().Err(err).Msg("Unable to apply event to game.")
+		if err := p.applyBlock(message.Block); err != nil {
+			p.log.Error().Err(err).Msg("Unable to apply block to game. Blocks will not be applied.")
+			p.fail(err)
 			return
 		}
+	}
+}
 
-		p.applyMutex.Unlock()
+// stop releases users of game lock waiting for blocks, which will not be applied.
+func (p *EventPump) stop(err error) {
+	p.gameLock.Lock()
+	p.gameLock.stop(err)
+	p.gameLock.Unlock()
+}
 
-	}
+// fail stops pump and reports failure.
+func (p *EventPump) fail(err error) {
+	p.stop(err)
+
+	p.failed <- err
 }
 
-func (p *EventPump) applyCurrentTimeLoop(ctx context.Context) {
-	for {
-		if ctx.Err() != nil {
-			return
-		}
+// applyBlock applies block timestamp and events to game as one transaction and records outcome of its events.
+func (p *EventPump) applyBlock(block *blockchainProtocol.Block) error {
+	p.gameLock.Lock()
+	defer p.gameLock.Unlock()
+
+	height := p.gameLock.height()
+
+	p.saveSnapshotIfNeeded(height)
+
+	eventErrors, err := p.game.ApplyBlock(block)
+	if err != nil {
+		return errors.Wrapf(err, "unable to apply block at height %d", height+1)
+	}
 
-		block, err := p.eventEmitter.WaitForBlock(ctx)
+	p.gameLock.blockApplied(height + 1)
+
+	// Rejected event leaves game untouched and stays in block, so it is skipped on every node.
+	for eventIndex, blockEvent := range block.Body.Events {
+		eventId, err := blockchain.NewEventId(blockEvent.Event)
 		if err != nil {
-			p.log.Error().Err(err).Msg("Waiting for block failed.")
-			continue
+			return errors.Wrap(err, "unable to calculate event id")
 		}
 
-		p.applyMutex.Lock()
-
-		if err = p.game.SetCurrentTimestamp(block.Body.Timestamp); err != nil {
-			p.log.Panic().Err(err).Msg("Unable to apply current time to game.")
-			return
+		if eventErrors[eventIndex] != nil {
+			p.log.Warn().Err(eventErrors[eventIndex]).Str("eventId", eventId.String()).Msg("Event rejected by game.")
 		}
+		p.eventResults.Record(eventId, eventErrors[eventIndex])
+	}
 
-		p.applyMutex.Unlock()
+	return nil
+}
+
+// saveSnapshotIfNeeded saves game state after block at given height. Block is already in storage, because receiver
+// stores block before next one is emitted.
+func (p *EventPump) saveSnapshotIfNeeded(height int) {
+	if p.snapshotStorage == nil || p.snapshotInterval <= 0 {
+		return
+	}
+
+	if height <= 0 || height%p.snapshotInterval != 0 {
+		return
+	}
 
+	block, err := p.blockStorage.GetByHeight(height)
+	if err != nil {
+		p.log.Warn().Err(err).Int("height", height).Msg("Unable to read block for snapshot.")
+		return
 	}
 
+	blockId, err := blockchain.NewBlockId(block)
+	if err != nil {
+		p.log.Warn().Err(err).Msg("Unable to calculate block id for snapshot.")
+		return
+	}
+
+	if err := p.snapshotStorage.Save(height, *blockId, p.game); err != nil {
+		p.log.Warn().Err(err).Int("height", height).Msg("Unable to save snapshot.")
+	}
 }
+
+var (
+	ErrEventPumpStopped = errors.New("event pump stopped")
+)
@@ -0,0 +1,82 @@
+package backend
+
+import (
+	"context"
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	"github.com/dominati-one/backend/internal/pkg/game"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEventPump_ApplyBlock(t *testing.T) {
+	gameInstance := game.NewGame()
+	gameLock := NewGameLock()
+	eventResults := NewEventResults(0)
+	pump := NewEventPump(blockchain.NewEventEmitter(), gameInstance, gameLock, eventResults, NewBlockStorage(), nil, 0)
+
+	gameLock.Lock()
+	gameLock.blockApplied(0)
+	gameLock.Unlock()
+
+	unsignedEvent := &blockchainProtocol.Event{
+		Body: &blockchainProtocol.Event_Body{
+			Event: &blockchainProtocol.Event_Body_CreatePlanet{CreatePlanet: &blockchainProtocol.EventCreatePlanet{}},
+		},
+		Timestamp: 2000,
+	}
+	unsignedEventId, err := blockchain.NewEventId(unsignedEvent)
+	assert.NoError(t, err)
+
+	block := createTestBlock(2000)
+	block.Body.Events = []*blockchainProtocol.Block_Body_BlockEvent{{Id: unsignedEventId.Bytes(), Event: unsignedEvent}}
+
+	assert.NoError(t, pump.applyBlock(createTestBlock(1000)))
+	assert.NoError(t, pump.applyBlock(block))
+	assert.Equal(t, 2, gameLock.height())
+
+	worldTime := gameInstance.WorldClock().Time()
+	assert.False(t, worldTime.IsZero())
+
+	applyError, exists := eventResults.Get(unsignedEventId)
+	assert.True(t, exists)
+	assert.NotEmpty(t, applyError)
+
+	stateHash, err := gameInstance.State().Hash()
+	assert.NoError(t, err)
+
+	// Block before current time fails as whole, game stays at previous block boundary.
+	assert.Error(t, pump.applyBlock(createTestBlock(1500)))
+	assert.Equal(t, 2, gameLock.height())
+	assert.Equal(t, worldTime, gameInstance.WorldClock().Time())
+
+	failedStateHash, err := gameInstance.State().Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, stateHash, failedStateHash)
+}
+
+func TestEventPump_Fail(t *testing.T) {
+	gameLock := NewGameLock()
+	pump := NewEventPump(blockchain.NewEventEmitter(), game.NewGame(), gameLock, NewEventResults(0), NewBlockStorage(), nil, 0)
+	applyErr := errors.New("unable to apply block")
+
+	pump.fail(applyErr)
+
+	select {
+	case err := <-pump.Failed():
+		assert.Equal(t, applyErr, err)
+	default:
+		t.Fatal("failure not reported")
+	}
+
+	// Users waiting for further blocks are not blocked forever.
+	assert.Equal(t, applyErr, errors.Cause(gameLock.LockAtHeight(context.TODO(), 0)))
+
+	blockStorage := NewBlockStorage()
+	_, err := blockStorage.Add(createTestBlock(1000))
+	assert.NoError(t, err)
+
+	_, err = NewGameStateHasher(game.NewGame(), gameLock, blockStorage).HashBlockState(context.TODO(), createTestBlock(2000))
+	assert.Equal(t, applyErr, errors.Cause(err))
+}
@@ -0,0 +1,80 @@
+package backend
+
+import (
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	"sync"
+)
+
+const (
+	DefaultEventResultsMaxCount = 100000
+)
+
+// EventResults remembers outcome of application of block events to game. Game rejects invalid event as whole, so
+// rejected event stays in block without any effect and its error is kept here for clients. Results are kept in memory
+// only and are filled again by replay after restart. Only results of maxCount most recently recorded events are kept,
+// older results are forgotten.
+type EventResults struct {
+	state    sync.Mutex
+	maxCount int
+	results  map[blockchain.EventId]string
+	order    []blockchain.EventId
+	next     int
+	changed  chan struct{}
+}
+
+func NewEventResults(maxCount int) *EventResults {
+	if maxCount <= 0 {
+		maxCount = DefaultEventResultsMaxCount
+	}
+
+	return &EventResults{
+		maxCount: maxCount,
+		results:  map[blockchain.EventId]string{},
+		changed:  make(chan struct{}),
+	}
+}
+
+// Record stores outcome of event application. Nil error means, that event was applied. Result of oldest event is
+// forgotten, when limit of results is reached.
+func (r *EventResults) Record(eventId blockchain.EventId, applyError error) {
+	defer r.state.Unlock()
+	r.state.Lock()
+
+	if _, exists := r.results[eventId]; !exists {
+		if len(r.order) < r.maxCount {
+			r.order = append(r.order, eventId)
+		} else {
+			delete(r.results, r.order[r.next])
+			r.order[r.next] = eventId
+			r.next = (r.next + 1) % r.maxCount
+		}
+	}
+
+	if applyError != nil {
+		r.results[eventId] = applyError.Error()
+	} else {
+		r.results[eventId] = ""
+	}
+
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// Get returns error, with which event was rejected, or empty string, when event was applied. False is returned, when
+// event was not applied to game yet or its result was already forgotten.
+func (r *EventResults) Get(eventId blockchain.EventId) (string, bool) {
+	defer r.state.Unlock()
+	r.state.Lock()
+
+	applyError, exists := r.results[eventId]
+
+	return applyError, exists
+}
+
+// Changed returns channel, which is closed, when next result is recorded.
+func (r *EventResults) Changed() <-chan struct{} {
+	defer r.state.Unlock()
+	r.state.Lock()
+
+	return r.changed
+}
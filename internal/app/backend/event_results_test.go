@@ -0,0 +1,68 @@
+package backend
+
+import (
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEventResults_Get(t *testing.T) {
+	eventResults := NewEventResults(0)
+	appliedEventId := blockchain.EventId{0x01}
+	rejectedEventId := blockchain.EventId{0x02}
+
+	_, exists := eventResults.Get(appliedEventId)
+	assert.False(t, exists)
+
+	eventResults.Record(appliedEventId, nil)
+	eventResults.Record(rejectedEventId, errors.New("rejected"))
+
+	applyError, exists := eventResults.Get(appliedEventId)
+	assert.True(t, exists)
+	assert.Empty(t, applyError)
+
+	applyError, exists = eventResults.Get(rejectedEventId)
+	assert.True(t, exists)
+	assert.Equal(t, "rejected", applyError)
+}
+
+func TestEventResults_MaxCount(t *testing.T) {
+	eventResults := NewEventResults(2)
+
+	eventResults.Record(blockchain.EventId{0x01}, nil)
+	eventResults.Record(blockchain.EventId{0x02}, nil)
+	eventResults.Record(blockchain.EventId{0x01}, errors.New("rejected"))
+	eventResults.Record(blockchain.EventId{0x03}, nil)
+
+	_, exists := eventResults.Get(blockchain.EventId{0x01})
+	assert.False(t, exists)
+
+	_, exists = eventResults.Get(blockchain.EventId{0x02})
+	assert.True(t, exists)
+
+	_, exists = eventResults.Get(blockchain.EventId{0x03})
+	assert.True(t, exists)
+}
+
+func TestEventResults_Changed(t *testing.T) {
+	eventResults := NewEventResults(0)
+
+	changed := eventResults.Changed()
+
+	select {
+	case <-changed:
+		t.Fatal("changed before result was recorded")
+	default:
+	}
+
+	eventResults.Record(blockchain.EventId{0x01}, nil)
+
+	select {
+	case <-changed:
+	default:
+		t.Fatal("not changed after result was recorded")
+	}
+
+	assert.NotEqual(t, changed, eventResults.Changed())
+}
@@ -1,22 +1,115 @@
 package backend
 
 import (
+	"crypto/ed25519"
 	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	"github.com/dominati-one/backend/internal/pkg/security"
 	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"sync"
 )
 
+type eventStorageNonce struct {
+	publicKey string
+	nonce     uint64
+}
+
 type EventStorage struct {
-	events map[blockchain.EventId]*blockchainProtocol.Event
+	state    sync.Mutex
+	events   map[blockchain.EventId]*blockchainProtocol.Event
+	blockIds map[blockchain.EventId]blockchain.BlockId
+	nonces   map[eventStorageNonce]blockchain.EventId
 }
 
 func NewEventStorage() *EventStorage {
 	return &EventStorage{
-		events: map[blockchain.EventId]*blockchainProtocol.Event{},
+		events:   map[blockchain.EventId]*blockchainProtocol.Event{},
+		blockIds: map[blockchain.EventId]blockchain.BlockId{},
+		nonces:   map[eventStorageNonce]blockchain.EventId{},
 	}
 }
 
+func (s *EventStorage) Add(event *blockchainProtocol.Event, blockId blockchain.BlockId) (blockchain.EventId, error) {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	eventId, err := blockchain.NewEventId(event)
+	if err != nil {
+		return blockchain.EmptyEventId, errors.Wrap(err, "unable to add event")
+	}
+
+	if _, exists := s.events[eventId]; exists {
+		return blockchain.EmptyEventId, ErrEventAlreadyInStorage
+	}
+
+	s.events[eventId] = proto.Clone(event).(*blockchainProtocol.Event)
+	s.blockIds[eventId] = blockId
+
+	if nonce, err := newEventStorageNonce(event); err == nil {
+		s.nonces[*nonce] = eventId
+	}
+
+	return eventId, nil
+}
+
 func (s *EventStorage) Exists(eventId blockchain.EventId) bool {
+	defer s.state.Unlock()
+	s.state.Lock()
+
 	_, exists := s.events[eventId]
 
 	return exists
 }
+
+func (s *EventStorage) Remove(eventId blockchain.EventId) {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	if event, exists := s.events[eventId]; exists {
+		if nonce, err := newEventStorageNonce(event); err == nil && s.nonces[*nonce] == eventId {
+			delete(s.nonces, *nonce)
+		}
+	}
+
+	delete(s.events, eventId)
+	delete(s.blockIds, eventId)
+}
+
+func (s *EventStorage) GetBlockId(eventId blockchain.EventId) (blockchain.BlockId, error) {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	blockId, exists := s.blockIds[eventId]
+	if !exists {
+		return blockchain.BlockId{}, ErrEventNotFoundInStorage
+	}
+
+	return blockId, nil
+}
+
+func (s *EventStorage) NonceUsed(publicKey ed25519.PublicKey, nonce uint64) bool {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	_, exists := s.nonces[eventStorageNonce{publicKey: string(publicKey), nonce: nonce}]
+
+	return exists
+}
+
+func newEventStorageNonce(event *blockchainProtocol.Event) (*eventStorageNonce, error) {
+	signature, err := security.NewSignature(event.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eventStorageNonce{
+		publicKey: string(signature.PublicKey()),
+		nonce:     event.Body.GetNonce(),
+	}, nil
+}
+
+var (
+	ErrEventAlreadyInStorage  = errors.New("event already in storage")
+	ErrEventNotFoundInStorage = errors.New("event not found in storage")
+)
@@ -0,0 +1,370 @@
+package backend
+
+import (
+	"bufio"
+	"encoding/binary"
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	fileBlockStorageFileName = "blocks.log"
+
+	// fileBlockStorageRecordHeaderSize is size of record header: payload length and payload CRC32 checksum.
+	fileBlockStorageRecordHeaderSize = 8
+)
+
+type FileBlockStorageSyncPolicy uint8
+
+const (
+	// FileBlockStorageSyncAlways calls fsync after every added block.
+	FileBlockStorageSyncAlways FileBlockStorageSyncPolicy = iota
+	// FileBlockStorageSyncInterval calls fsync on add, when SyncInterval passed since previous fsync.
+	FileBlockStorageSyncInterval
+	// FileBlockStorageSyncNever leaves flushing to operating system.
+	FileBlockStorageSyncNever
+)
+
+type FileBlockStorageOptions struct {
+	SyncPolicy   FileBlockStorageSyncPolicy
+	SyncInterval time.Duration
+}
+
+// FileBlockStorage keeps blocks in append-only log of length-prefixed protobuf records. Offsets of records are
+// indexed in memory by height and by block id, block bodies are read from disk.
+type FileBlockStorage struct {
+	log     zerolog.Logger
+	options FileBlockStorageOptions
+
+	state       sync.Mutex
+	file        *os.File
+	size        int64
+	lastSync    time.Time
+	offsets     []int64
+	heightsById map[blockchain.BlockId]int
+	lastBlock   *blockchainProtocol.Block
+}
+
+func NewFileBlockStorage(dataDirectory string, options FileBlockStorageOptions) (*FileBlockStorage, error) {
+	if err := os.MkdirAll(dataDirectory, 0755); err != nil {
+		return nil, errors.Wrap(err, "unable to create data directory")
+	}
+
+	path := filepath.Join(dataDirectory, fileBlockStorageFileName)
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open block log")
+	}
+
+	s := &FileBlockStorage{
+		log:         log.With().Str("applicationComponent", "fileBlockStorage").Str("path", path).Logger(),
+		options:     options,
+		file:        file,
+		lastSync:    time.Now(),
+		offsets:     []int64{},
+		heightsById: map[blockchain.BlockId]int{},
+	}
+
+	if err := s.recover(); err != nil {
+		_ = file.Close()
+		return nil, errors.Wrap(err, "unable to recover block log")
+	}
+
+	s.log.Info().Int("blocksCount", len(s.offsets)).Msg("Block log opened.")
+
+	return s, nil
+}
+
+func (s *FileBlockStorage) Count() int {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	return len(s.offsets)
+}
+
+func (s *FileBlockStorage) Add(block *blockchainProtocol.Block) (*blockchain.BlockId, error) {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	blockId, err := blockchain.NewBlockId(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to add block")
+	}
+
+	if _, exists := s.heightsById[*blockId]; exists {
+		return nil, ErrBlockAlreadyInStorage
+	}
+
+	payload, err := proto.Marshal(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to marshal block")
+	}
+
+	record := make([]byte, fileBlockStorageRecordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(payload))
+	copy(record[fileBlockStorageRecordHeaderSize:], payload)
+
+	if _, err := s.file.WriteAt(record, s.size); err != nil {
+		return nil, errors.Wrap(err, "unable to write block record")
+	}
+
+	if err := s.syncIfNeeded(); err != nil {
+		return nil, errors.Wrap(err, "unable to sync block log")
+	}
+
+	s.heightsById[*blockId] = len(s.offsets)
+	s.offsets = append(s.offsets, s.size)
+	s.size += int64(len(record))
+	s.lastBlock = proto.Clone(block).(*blockchainProtocol.Block)
+
+	return blockId, nil
+}
+
+func (s *FileBlockStorage) Get(blockId blockchain.BlockId) (*blockchainProtocol.Block, error) {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	height, exists := s.heightsById[blockId]
+	if !exists {
+		return nil, ErrBlockNotFoundInStorage
+	}
+
+	return s.readAt(s.offsets[height])
+}
+
+func (s *FileBlockStorage) GetByHeight(height int) (*blockchainProtocol.Block, error) {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	if height < 0 || height >= len(s.offsets) {
+		return nil, ErrBlockNotFoundInStorage
+	}
+
+	return s.readAt(s.offsets[height])
+}
+
+func (s *FileBlockStorage) GetLatestBlock() (*blockchainProtocol.Block, error) {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	if s.lastBlock == nil {
+		return nil, ErrNoBlockInStorage
+	}
+
+	return proto.Clone(s.lastBlock).(*blockchainProtocol.Block), nil
+}
+
+func (s *FileBlockStorage) GetHeight(blockId blockchain.BlockId) (int, error) {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	height, exists := s.heightsById[blockId]
+	if !exists {
+		return 0, ErrBlockNotFoundInStorage
+	}
+
+	return height, nil
+}
+
+// Truncate cuts block log after record of block at given height. Log is synced regardless of sync policy, so removed
+// blocks do not come back after crash.
+func (s *FileBlockStorage) Truncate(height int) error {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	if height < 0 || height >= len(s.offsets) {
+		return ErrBlockNotFoundInStorage
+	}
+
+	if height == len(s.offsets)-1 {
+		return nil
+	}
+
+	lastBlock, err := s.readAt(s.offsets[height])
+	if err != nil {
+		return errors.Wrap(err, "unable to read new latest block")
+	}
+
+	size := s.offsets[height+1]
+
+	if err := s.file.Truncate(size); err != nil {
+		return errors.Wrap(err, "unable to truncate block log")
+	}
+
+	if err := s.file.Sync(); err != nil {
+		return errors.Wrap(err, "unable to sync block log")
+	}
+
+	for blockId, blockHeight := range s.heightsById {
+		if blockHeight > height {
+			delete(s.heightsById, blockId)
+		}
+	}
+
+	s.offsets = s.offsets[:height+1]
+	s.size = size
+	s.lastBlock = lastBlock
+
+	return nil
+}
+
+func (s *FileBlockStorage) Exists(blockId blockchain.BlockId) bool {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	_, exists := s.heightsById[blockId]
+
+	return exists
+}
+
+// Close flushes block log to disk and closes it.
+func (s *FileBlockStorage) Close() error {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	if err := s.file.Sync(); err != nil {
+		return errors.Wrap(err, "unable to sync block log")
+	}
+
+	return s.file.Close()
+}
+
+func (s *FileBlockStorage) syncIfNeeded() error {
+	switch s.options.SyncPolicy {
+	case FileBlockStorageSyncAlways:
+	case FileBlockStorageSyncInterval:
+		if time.Since(s.lastSync) < s.options.SyncInterval {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+
+	s.lastSync = time.Now()
+
+	return nil
+}
+
+func (s *FileBlockStorage) readAt(offset int64) (*blockchainProtocol.Block, error) {
+	header := make([]byte, fileBlockStorageRecordHeaderSize)
+	if _, err := s.file.ReadAt(header, offset); err != nil {
+		return nil, errors.Wrap(err, "unable to read block record header")
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint32(header[0:4]))
+	if _, err := s.file.ReadAt(payload, offset+fileBlockStorageRecordHeaderSize); err != nil {
+		return nil, errors.Wrap(err, "unable to read block record")
+	}
+
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+		return nil, ErrBlockStorageCorrupted
+	}
+
+	block := &blockchainProtocol.Block{}
+	if err := proto.Unmarshal(payload, block); err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal block record")
+	}
+
+	return block, nil
+}
+
+// recover rebuilds in-memory indexes from block log. Trailing record torn by crash is truncated, any other damaged
+// record is reported as corruption.
+func (s *FileBlockStorage) recover() error {
+	fileInfo, err := s.file.Stat()
+	if err != nil {
+		return errors.Wrap(err, "unable to stat block log")
+	}
+	fileSize := fileInfo.Size()
+
+	reader := bufio.NewReader(io.NewSectionReader(s.file, 0, fileSize))
+	header := make([]byte, fileBlockStorageRecordHeaderSize)
+
+	var offset int64
+
+	for offset < fileSize {
+		if fileSize-offset < fileBlockStorageRecordHeaderSize {
+			return s.truncate(offset, fileSize)
+		}
+
+		if _, err := io.ReadFull(reader, header); err != nil {
+			return errors.Wrap(err, "unable to read block record header")
+		}
+
+		recordSize := fileBlockStorageRecordHeaderSize + int64(binary.BigEndian.Uint32(header[0:4]))
+		if offset+recordSize > fileSize {
+			return s.truncate(offset, fileSize)
+		}
+
+		payload := make([]byte, recordSize-fileBlockStorageRecordHeaderSize)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			return errors.Wrap(err, "unable to read block record")
+		}
+
+		block := &blockchainProtocol.Block{}
+		validRecord := crc32.ChecksumIEEE(payload) == binary.BigEndian.Uint32(header[4:8]) &&
+			proto.Unmarshal(payload, block) == nil
+
+		if !validRecord {
+			if offset+recordSize == fileSize {
+				return s.truncate(offset, fileSize)
+			}
+			return errors.Wrapf(ErrBlockStorageCorrupted, "damaged block record at offset %d", offset)
+		}
+
+		blockId, err := blockchain.NewBlockId(block)
+		if err != nil {
+			return errors.Wrap(err, "unable to calculate block id")
+		}
+
+		s.heightsById[*blockId] = len(s.offsets)
+		s.offsets = append(s.offsets, offset)
+		s.lastBlock = block
+
+		offset += recordSize
+	}
+
+	s.size = offset
+
+	return nil
+}
+
+func (s *FileBlockStorage) truncate(offset int64, fileSize int64) error {
+	s.log.Warn().
+		Int64("offset", offset).
+		Int64("droppedBytes", fileSize-offset).
+		Msg("Torn trailing block record found. Truncating block log.")
+
+	if err := s.file.Truncate(offset); err != nil {
+		return errors.Wrap(err, "unable to truncate block log")
+	}
+
+	if err := s.file.Sync(); err != nil {
+		return errors.Wrap(err, "unable to sync block log")
+	}
+
+	s.size = offset
+
+	return nil
+}
+
+var (
+	ErrBlockAlreadyInStorage = errors.New("block already in storage")
+	ErrBlockStorageCorrupted = errors.New("block storage corrupted")
+)
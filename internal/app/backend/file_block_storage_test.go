@@ -0,0 +1,173 @@
+package backend
+
+import (
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileBlockStorage_Add(t *testing.T) {
+	dataDirectory := t.TempDir()
+
+	storage, err := NewFileBlockStorage(dataDirectory, FileBlockStorageOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, storage.Count())
+
+	_, err = storage.GetLatestBlock()
+	assert.ErrorIs(t, err, ErrNoBlockInStorage)
+
+	firstBlock := createTestBlock(1)
+	secondBlock := createTestBlock(2)
+
+	firstBlockId, err := storage.Add(firstBlock)
+	assert.NoError(t, err)
+	secondBlockId, err := storage.Add(secondBlock)
+	assert.NoError(t, err)
+
+	_, err = storage.Add(firstBlock)
+	assert.ErrorIs(t, err, ErrBlockAlreadyInStorage)
+
+	assert.Equal(t, 2, storage.Count())
+	assert.True(t, storage.Exists(*firstBlockId))
+	assert.True(t, storage.Exists(*secondBlockId))
+
+	block, err := storage.GetByHeight(0)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(firstBlock, block))
+
+	block, err = storage.Get(*secondBlockId)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(secondBlock, block))
+
+	block, err = storage.GetLatestBlock()
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(secondBlock, block))
+
+	_, err = storage.GetByHeight(2)
+	assert.ErrorIs(t, err, ErrBlockNotFoundInStorage)
+
+	assert.NoError(t, storage.Close())
+
+	storage, err = NewFileBlockStorage(dataDirectory, FileBlockStorageOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, storage.Count())
+	assert.True(t, storage.Exists(*firstBlockId))
+
+	block, err = storage.GetLatestBlock()
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(secondBlock, block))
+	assert.NoError(t, storage.Close())
+}
+
+func TestFileBlockStorage_Truncate(t *testing.T) {
+	dataDirectory := t.TempDir()
+
+	storage, err := NewFileBlockStorage(dataDirectory, FileBlockStorageOptions{})
+	assert.NoError(t, err)
+
+	firstBlock := createTestBlock(1)
+	firstBlockId, err := storage.Add(firstBlock)
+	assert.NoError(t, err)
+	secondBlockId, err := storage.Add(createTestBlock(2))
+	assert.NoError(t, err)
+	_, err = storage.Add(createTestBlock(3))
+	assert.NoError(t, err)
+
+	height, err := storage.GetHeight(*secondBlockId)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, height)
+
+	assert.ErrorIs(t, storage.Truncate(3), ErrBlockNotFoundInStorage)
+	assert.NoError(t, storage.Truncate(0))
+	assert.Equal(t, 1, storage.Count())
+	assert.False(t, storage.Exists(*secondBlockId))
+
+	_, err = storage.GetHeight(*secondBlockId)
+	assert.ErrorIs(t, err, ErrBlockNotFoundInStorage)
+
+	block, err := storage.GetLatestBlock()
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(firstBlock, block))
+
+	thirdBlockId, err := storage.Add(createTestBlock(4))
+	assert.NoError(t, err)
+	assert.NoError(t, storage.Close())
+
+	storage, err = NewFileBlockStorage(dataDirectory, FileBlockStorageOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, storage.Count())
+	assert.True(t, storage.Exists(*firstBlockId))
+	assert.True(t, storage.Exists(*thirdBlockId))
+	assert.False(t, storage.Exists(*secondBlockId))
+	assert.NoError(t, storage.Close())
+}
+
+func TestFileBlockStorage_RecoverTornRecord(t *testing.T) {
+	dataDirectory := t.TempDir()
+
+	storage, err := NewFileBlockStorage(dataDirectory, FileBlockStorageOptions{})
+	assert.NoError(t, err)
+
+	firstBlockId, err := storage.Add(createTestBlock(1))
+	assert.NoError(t, err)
+	_, err = storage.Add(createTestBlock(2))
+	assert.NoError(t, err)
+	assert.NoError(t, storage.Close())
+
+	path := filepath.Join(dataDirectory, fileBlockStorageFileName)
+	fileInfo, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Truncate(path, fileInfo.Size()-3))
+
+	storage, err = NewFileBlockStorage(dataDirectory, FileBlockStorageOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, storage.Count())
+	assert.True(t, storage.Exists(*firstBlockId))
+
+	thirdBlockId, err := storage.Add(createTestBlock(3))
+	assert.NoError(t, err)
+	assert.NoError(t, storage.Close())
+
+	storage, err = NewFileBlockStorage(dataDirectory, FileBlockStorageOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, storage.Count())
+	assert.True(t, storage.Exists(*thirdBlockId))
+	assert.NoError(t, storage.Close())
+}
+
+func TestFileBlockStorage_RecoverCorruptedRecord(t *testing.T) {
+	dataDirectory := t.TempDir()
+
+	storage, err := NewFileBlockStorage(dataDirectory, FileBlockStorageOptions{})
+	assert.NoError(t, err)
+
+	_, err = storage.Add(createTestBlock(1))
+	assert.NoError(t, err)
+	_, err = storage.Add(createTestBlock(2))
+	assert.NoError(t, err)
+	assert.NoError(t, storage.Close())
+
+	path := filepath.Join(dataDirectory, fileBlockStorageFileName)
+	file, err := os.OpenFile(path, os.O_RDWR, 0644)
+	assert.NoError(t, err)
+	_, err = file.WriteAt([]byte{0xff}, fileBlockStorageRecordHeaderSize+1)
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	_, err = NewFileBlockStorage(dataDirectory, FileBlockStorageOptions{})
+	assert.Error(t, err)
+}
+
+func createTestBlock(timestamp uint64) *blockchainProtocol.Block {
+	return &blockchainProtocol.Block{
+		Body: &blockchainProtocol.Block_Body{
+			PreviousBlockId: []byte{0x00},
+			Timestamp:       timestamp,
+			Events:          []*blockchainProtocol.Block_Body_BlockEvent{},
+		},
+		Checksum: []byte{0x00},
+	}
+}
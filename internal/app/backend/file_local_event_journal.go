@@ -0,0 +1,262 @@
+package backend
+
+import (
+	"bufio"
+	"encoding/binary"
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	fileLocalEventJournalFileName = "local-events.log"
+
+	// fileLocalEventJournalRecordHeaderSize is size of record header: payload length and payload CRC32 checksum.
+	fileLocalEventJournalRecordHeaderSize = 8
+	// fileLocalEventJournalEntryHeaderSize is size of operation and event id at start of record payload.
+	fileLocalEventJournalEntryHeaderSize = 1 + 32
+)
+
+// FileLocalEventJournal keeps transitions of local event backlog in append-only log of length-prefixed records.
+// Every record is synced to disk before Append returns.
+type FileLocalEventJournal struct {
+	log       zerolog.Logger
+	directory string
+	path      string
+
+	state sync.Mutex
+	file  *os.File
+	size  int64
+}
+
+func NewFileLocalEventJournal(dataDirectory string) (*FileLocalEventJournal, error) {
+	if err := os.MkdirAll(dataDirectory, 0755); err != nil {
+		return nil, errors.Wrap(err, "unable to create data directory")
+	}
+
+	path := filepath.Join(dataDirectory, fileLocalEventJournalFileName)
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open local event journal")
+	}
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, errors.Wrap(err, "unable to stat local event journal")
+	}
+
+	return &FileLocalEventJournal{
+		log:       log.With().Str("applicationComponent", "fileLocalEventJournal").Str("path", path).Logger(),
+		directory: dataDirectory,
+		path:      path,
+		file:      file,
+		size:      fileInfo.Size(),
+	}, nil
+}
+
+func (j *FileLocalEventJournal) Append(entry blockchain.LocalEventJournalEntry) error {
+	defer j.state.Unlock()
+	j.state.Lock()
+
+	record, err := encodeLocalEventJournalRecord(entry)
+	if err != nil {
+		return err
+	}
+
+	if _, err := j.file.WriteAt(record, j.size); err != nil {
+		return errors.Wrap(err, "unable to write journal record")
+	}
+
+	if err := j.file.Sync(); err != nil {
+		return errors.Wrap(err, "unable to sync local event journal")
+	}
+
+	j.size += int64(len(record))
+
+	return nil
+}
+
+// Load reads all entries. Trailing record torn by crash is truncated, any other damaged record is reported as
+// corruption.
+func (j *FileLocalEventJournal) Load() ([]blockchain.LocalEventJournalEntry, error) {
+	defer j.state.Unlock()
+	j.state.Lock()
+
+	entries := []blockchain.LocalEventJournalEntry{}
+
+	reader := bufio.NewReader(io.NewSectionReader(j.file, 0, j.size))
+	header := make([]byte, fileLocalEventJournalRecordHeaderSize)
+
+	var offset int64
+
+	for offset < j.size {
+		if j.size-offset < fileLocalEventJournalRecordHeaderSize {
+			return entries, j.truncate(offset)
+		}
+
+		if _, err := io.ReadFull(reader, header); err != nil {
+			return nil, errors.Wrap(err, "unable to read journal record header")
+		}
+
+		recordSize := fileLocalEventJournalRecordHeaderSize + int64(binary.BigEndian.Uint32(header[0:4]))
+		if offset+recordSize > j.size {
+			return entries, j.truncate(offset)
+		}
+
+		payload := make([]byte, recordSize-fileLocalEventJournalRecordHeaderSize)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			return nil, errors.Wrap(err, "unable to read journal record")
+		}
+
+		entry, err := decodeLocalEventJournalPayload(payload)
+		if err != nil || crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+			if offset+recordSize == j.size {
+				return entries, j.truncate(offset)
+			}
+			return nil, errors.Wrapf(ErrLocalEventJournalCorrupted, "damaged journal record at offset %d", offset)
+		}
+
+		entries = append(entries, *entry)
+		offset += recordSize
+	}
+
+	return entries, nil
+}
+
+// Compact writes entries to new journal file and atomically replaces current one with it.
+func (j *FileLocalEventJournal) Compact(entries []blockchain.LocalEventJournalEntry) error {
+	defer j.state.Unlock()
+	j.state.Lock()
+
+	buffer := []byte{}
+	for _, entry := range entries {
+		record, err := encodeLocalEventJournalRecord(entry)
+		if err != nil {
+			return err
+		}
+		buffer = append(buffer, record...)
+	}
+
+	file, err := ioutil.TempFile(j.directory, "tmp-local-events-")
+	if err != nil {
+		return errors.Wrap(err, "unable to create temporary journal")
+	}
+	temporaryPath := file.Name()
+
+	_, err = file.Write(buffer)
+	if err == nil {
+		err = file.Sync()
+	}
+	if err == nil {
+		err = os.Rename(temporaryPath, j.path)
+	}
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(temporaryPath)
+		return errors.Wrap(err, "unable to replace journal")
+	}
+
+	if err := syncDirectory(j.directory); err != nil {
+		_ = file.Close()
+		return errors.Wrap(err, "unable to sync data directory")
+	}
+
+	_ = j.file.Close()
+	j.file = file
+	j.size = int64(len(buffer))
+
+	return nil
+}
+
+// Close closes journal file.
+func (j *FileLocalEventJournal) Close() error {
+	defer j.state.Unlock()
+	j.state.Lock()
+
+	return j.file.Close()
+}
+
+func (j *FileLocalEventJournal) truncate(offset int64) error {
+	j.log.Warn().
+		Int64("offset", offset).
+		Int64("droppedBytes", j.size-offset).
+		Msg("Torn trailing journal record found. Truncating local event journal.")
+
+	if err := j.file.Truncate(offset); err != nil {
+		return errors.Wrap(err, "unable to truncate local event journal")
+	}
+
+	if err := j.file.Sync(); err != nil {
+		return errors.Wrap(err, "unable to sync local event journal")
+	}
+
+	j.size = offset
+
+	return nil
+}
+
+func encodeLocalEventJournalRecord(entry blockchain.LocalEventJournalEntry) ([]byte, error) {
+	payload := make([]byte, fileLocalEventJournalEntryHeaderSize)
+	payload[0] = byte(entry.Operation)
+	copy(payload[1:], entry.EventId.Bytes())
+
+	if entry.Event != nil {
+		eventBytes, err := proto.Marshal(entry.Event)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to marshal journal event")
+		}
+		payload = append(payload, eventBytes...)
+	}
+
+	record := make([]byte, fileLocalEventJournalRecordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(payload))
+	copy(record[fileLocalEventJournalRecordHeaderSize:], payload)
+
+	return record, nil
+}
+
+func decodeLocalEventJournalPayload(payload []byte) (*blockchain.LocalEventJournalEntry, error) {
+	if len(payload) < fileLocalEventJournalEntryHeaderSize {
+		return nil, ErrLocalEventJournalCorrupted
+	}
+
+	entry := &blockchain.LocalEventJournalEntry{
+		Operation: blockchain.LocalEventJournalOperation(payload[0]),
+	}
+	copy(entry.EventId[:], payload[1:fileLocalEventJournalEntryHeaderSize])
+
+	if entry.Operation == blockchain.LocalEventJournalOperationAdd {
+		entry.Event = &blockchainProtocol.Event{}
+		if err := proto.Unmarshal(payload[fileLocalEventJournalEntryHeaderSize:], entry.Event); err != nil {
+			return nil, ErrLocalEventJournalCorrupted
+		}
+	}
+
+	return entry, nil
+}
+
+func syncDirectory(path string) error {
+	directory, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer directory.Close()
+
+	return directory.Sync()
+}
+
+var (
+	ErrLocalEventJournalCorrupted = errors.New("local event journal corrupted")
+)
@@ -0,0 +1,91 @@
+package backend
+
+import (
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileLocalEventJournal_Append(t *testing.T) {
+	dataDirectory := t.TempDir()
+
+	journal, err := NewFileLocalEventJournal(dataDirectory)
+	assert.NoError(t, err)
+
+	event := &blockchainProtocol.Event{Timestamp: 1000, Body: &blockchainProtocol.Event_Body{}}
+	eventId := *blockchain.MustEventId(event)
+
+	assert.NoError(t, journal.Append(blockchain.LocalEventJournalEntry{
+		Operation: blockchain.LocalEventJournalOperationAdd,
+		EventId:   eventId,
+		Event:     event,
+	}))
+	assert.NoError(t, journal.Append(blockchain.LocalEventJournalEntry{
+		Operation: blockchain.LocalEventJournalOperationSent,
+		EventId:   eventId,
+	}))
+	assert.NoError(t, journal.Close())
+
+	journal, err = NewFileLocalEventJournal(dataDirectory)
+	assert.NoError(t, err)
+
+	entries, err := journal.Load()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, blockchain.LocalEventJournalOperationAdd, entries[0].Operation)
+	assert.Equal(t, eventId, entries[0].EventId)
+	assert.True(t, proto.Equal(event, entries[0].Event))
+	assert.Equal(t, blockchain.LocalEventJournalOperationSent, entries[1].Operation)
+	assert.Nil(t, entries[1].Event)
+
+	assert.NoError(t, journal.Compact(entries[:1]))
+	assert.NoError(t, journal.Append(blockchain.LocalEventJournalEntry{
+		Operation: blockchain.LocalEventJournalOperationConfirmed,
+		EventId:   eventId,
+	}))
+	assert.NoError(t, journal.Close())
+
+	journal, err = NewFileLocalEventJournal(dataDirectory)
+	assert.NoError(t, err)
+
+	entries, err = journal.Load()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, blockchain.LocalEventJournalOperationConfirmed, entries[1].Operation)
+	assert.NoError(t, journal.Close())
+}
+
+func TestFileLocalEventJournal_LoadTornRecord(t *testing.T) {
+	dataDirectory := t.TempDir()
+
+	journal, err := NewFileLocalEventJournal(dataDirectory)
+	assert.NoError(t, err)
+
+	for _, operation := range []blockchain.LocalEventJournalOperation{blockchain.LocalEventJournalOperationSent, blockchain.LocalEventJournalOperationReceived} {
+		assert.NoError(t, journal.Append(blockchain.LocalEventJournalEntry{Operation: operation}))
+	}
+	assert.NoError(t, journal.Close())
+
+	path := filepath.Join(dataDirectory, fileLocalEventJournalFileName)
+	fileInfo, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Truncate(path, fileInfo.Size()-3))
+
+	journal, err = NewFileLocalEventJournal(dataDirectory)
+	assert.NoError(t, err)
+
+	entries, err := journal.Load()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	assert.NoError(t, journal.Append(blockchain.LocalEventJournalEntry{Operation: blockchain.LocalEventJournalOperationConfirmed}))
+
+	entries, err = journal.Load()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.NoError(t, journal.Close())
+}
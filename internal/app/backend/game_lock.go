@@ -0,0 +1,87 @@
+package backend
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+// GameLock serializes access to game between event pump and other users of game state. It tracks how far pump got
+// with application of blocks, so users can wait until block already accepted into block storage is applied.
+type GameLock struct {
+	mutex         sync.Mutex
+	appliedHeight int
+	// applied is closed and replaced, whenever applied height changes or pump stops.
+	applied chan struct{}
+	stopErr error
+}
+
+func NewGameLock() *GameLock {
+	return &GameLock{
+		appliedHeight: -1,
+		applied:       make(chan struct{}),
+	}
+}
+
+func (l *GameLock) Lock() {
+	l.mutex.Lock()
+}
+
+func (l *GameLock) Unlock() {
+	l.mutex.Unlock()
+}
+
+// LockAtHeight acquires lock, once block at given height was applied to game. Error is returned without lock held,
+// when context is done or pump stopped before block was applied.
+func (l *GameLock) LockAtHeight(ctx context.Context, height int) error {
+	for {
+		l.mutex.Lock()
+
+		if l.appliedHeight >= height {
+			return nil
+		}
+
+		if l.stopErr != nil {
+			l.mutex.Unlock()
+			return errors.Wrapf(l.stopErr, "block at height %d will not be applied", height)
+		}
+
+		applied := l.applied
+		l.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ErrCanceledGameLockWait
+		case <-applied:
+		}
+	}
+}
+
+// height returns height of block applied to game. Lock must be held.
+func (l *GameLock) height() int {
+	return l.appliedHeight
+}
+
+// blockApplied records block at given height as applied to game. Lock must be held.
+func (l *GameLock) blockApplied(height int) {
+	l.appliedHeight = height
+
+	l.notify()
+}
+
+// stop records, that pump stopped with given error, so nobody waits for blocks, which will never be applied. Lock
+// must be held.
+func (l *GameLock) stop(err error) {
+	l.stopErr = err
+
+	l.notify()
+}
+
+func (l *GameLock) notify() {
+	close(l.applied)
+	l.applied = make(chan struct{})
+}
+
+var (
+	ErrCanceledGameLockWait = errors.New("canceled game lock wait")
+)
@@ -0,0 +1,74 @@
+package backend
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGameLock_LockAtHeight(t *testing.T) {
+	gameLock := NewGameLock()
+
+	gameLock.Lock()
+	gameLock.blockApplied(1)
+	gameLock.Unlock()
+
+	assert.NoError(t, gameLock.LockAtHeight(context.TODO(), 1))
+	gameLock.Unlock()
+
+	locked := make(chan error)
+	go func() {
+		locked <- gameLock.LockAtHeight(context.TODO(), 2)
+	}()
+
+	gameLock.Lock()
+	gameLock.blockApplied(2)
+	gameLock.Unlock()
+
+	select {
+	case err := <-locked:
+		assert.NoError(t, err)
+		assert.Equal(t, 2, gameLock.height())
+		gameLock.Unlock()
+	case <-time.After(time.Second):
+		t.Fatal("lock not acquired after block was applied")
+	}
+}
+
+func TestGameLock_LockAtHeightCanceled(t *testing.T) {
+	gameLock := NewGameLock()
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, gameLock.LockAtHeight(ctx, 0), ErrCanceledGameLockWait)
+
+	// Lock is not held after failed wait.
+	gameLock.Lock()
+	gameLock.Unlock()
+}
+
+func TestGameLock_LockAtHeightStopped(t *testing.T) {
+	gameLock := NewGameLock()
+	stopErr := errors.New("stopped")
+
+	locked := make(chan error)
+	go func() {
+		locked <- gameLock.LockAtHeight(context.TODO(), 0)
+	}()
+
+	gameLock.Lock()
+	gameLock.stop(stopErr)
+	gameLock.Unlock()
+
+	select {
+	case err := <-locked:
+		assert.Equal(t, stopErr, errors.Cause(err))
+	case <-time.After(time.Second):
+		t.Fatal("wait not released after pump stopped")
+	}
+
+	assert.Equal(t, stopErr, errors.Cause(gameLock.LockAtHeight(context.TODO(), 0)))
+}
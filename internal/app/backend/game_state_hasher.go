@@ -0,0 +1,35 @@
+package backend
+
+import (
+	"context"
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	"github.com/dominati-one/backend/internal/pkg/game"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/pkg/errors"
+)
+
+// GameStateHasher calculates block state hashes on game shared with event pump. Blocks are always built on top of
+// latest stored block, so hasher waits until pump applied it, before game is cloned. Hashing fails, when pump stopped
+// before it applied latest block or context is done first.
+type GameStateHasher struct {
+	game         *game.Game
+	gameLock     *GameLock
+	blockStorage blockchain.BlockStorage
+}
+
+func NewGameStateHasher(game *game.Game, gameLock *GameLock, blockStorage blockchain.BlockStorage) *GameStateHasher {
+	return &GameStateHasher{
+		game:         game,
+		gameLock:     gameLock,
+		blockStorage: blockStorage,
+	}
+}
+
+func (h *GameStateHasher) HashBlockState(ctx context.Context, block *blockchainProtocol.Block) ([]byte, error) {
+	if err := h.gameLock.LockAtHeight(ctx, h.blockStorage.Count()-1); err != nil {
+		return nil, errors.Wrap(err, "unable to wait for latest block to be applied")
+	}
+	defer h.gameLock.Unlock()
+
+	return h.game.HashBlockState(block)
+}
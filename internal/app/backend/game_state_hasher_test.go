@@ -0,0 +1,81 @@
+package backend
+
+import (
+	"context"
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	"github.com/dominati-one/backend/internal/pkg/game"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGameStateHasher_HashBlockState(t *testing.T) {
+	blockStorage := NewBlockStorage()
+	gameInstance := game.NewGame()
+	gameLock := NewGameLock()
+	pump := NewEventPump(blockchain.NewEventEmitter(), gameInstance, gameLock, NewEventResults(0), blockStorage, nil, 0)
+
+	genesisBlock := createTestBlock(1000)
+	_, err := blockStorage.Add(genesisBlock)
+	assert.NoError(t, err)
+
+	_, err = gameInstance.WorldClock().SetCurrentTimestamp(genesisBlock.Body.Timestamp)
+	assert.NoError(t, err)
+	gameLock.Lock()
+	gameLock.blockApplied(0)
+	gameLock.Unlock()
+
+	firstBlock := createTestChildBlock(t, genesisBlock, 2000)
+	_, err = blockStorage.Add(firstBlock)
+	assert.NoError(t, err)
+
+	hasher := NewGameStateHasher(gameInstance, gameLock, blockStorage)
+
+	// Waiting for latest block is abandoned, when context is done.
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = hasher.HashBlockState(canceledCtx, createTestChildBlock(t, firstBlock, 3000))
+	assert.Equal(t, ErrCanceledGameLockWait, errors.Cause(err))
+
+	// Block on top of latest stored block is hashed only after pump applied latest block.
+	hashed := make(chan []byte)
+	go func() {
+		stateHash, err := hasher.HashBlockState(context.TODO(), createTestChildBlock(t, firstBlock, 3000))
+		assert.NoError(t, err)
+		hashed <- stateHash
+	}()
+
+	select {
+	case <-hashed:
+		t.Fatal("block hashed before latest block was applied")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.NoError(t, pump.applyBlock(firstBlock))
+
+	var stateHash []byte
+	select {
+	case stateHash = <-hashed:
+	case <-time.After(time.Second):
+		t.Fatal("block not hashed after latest block was applied")
+	}
+
+	// Rejected event leaves state untouched, so it does not fail hashing and does not change hash.
+	unsignedEvent := &blockchainProtocol.Event{
+		Body: &blockchainProtocol.Event_Body{
+			Event: &blockchainProtocol.Event_Body_CreatePlanet{CreatePlanet: &blockchainProtocol.EventCreatePlanet{}},
+		},
+		Timestamp: 3000,
+	}
+	unsignedEventId, err := blockchain.NewEventId(unsignedEvent)
+	assert.NoError(t, err)
+
+	block := createTestChildBlock(t, firstBlock, 3000)
+	block.Body.Events = []*blockchainProtocol.Block_Body_BlockEvent{{Id: unsignedEventId.Bytes(), Event: unsignedEvent}}
+
+	rejectedEventStateHash, err := hasher.HashBlockState(context.TODO(), block)
+	assert.NoError(t, err)
+	assert.Equal(t, stateHash, rejectedEventStateHash)
+}
@@ -0,0 +1,44 @@
+package backend
+
+import (
+	"context"
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	"github.com/dominati-one/backend/internal/pkg/game"
+	"github.com/pkg/errors"
+)
+
+// GameStateRewinder rebuilds game shared with event pump, when chain is reorganized. New game is replayed from
+// snapshot and stored blocks up to common ancestor of branches and swapped in, so pump continues from that height.
+type GameStateRewinder struct {
+	game          *game.Game
+	gameLock      *GameLock
+	blockStorage  blockchain.BlockStorage
+	blockReplayer *BlockReplayer
+}
+
+func NewGameStateRewinder(game *game.Game, gameLock *GameLock, blockStorage blockchain.BlockStorage, blockReplayer *BlockReplayer) *GameStateRewinder {
+	return &GameStateRewinder{
+		game:          game,
+		gameLock:      gameLock,
+		blockStorage:  blockStorage,
+		blockReplayer: blockReplayer,
+	}
+}
+
+func (r *GameStateRewinder) RewindState(ctx context.Context, height int) error {
+	if err := r.gameLock.LockAtHeight(ctx, r.blockStorage.Count()-1); err != nil {
+		return errors.Wrap(err, "unable to wait for latest block to be applied")
+	}
+	defer r.gameLock.Unlock()
+
+	rewoundGame := game.NewGame()
+
+	if err := r.blockReplayer.ReplayInto(ctx, rewoundGame, height); err != nil {
+		return errors.Wrapf(err, "unable to replay game up to height %d", height)
+	}
+
+	r.game.Replace(rewoundGame)
+	r.gameLock.blockApplied(height)
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package backend
+
+import (
+	"context"
+	"github.com/dominati-one/backend/internal/pkg/game"
+	"github.com/dominati-one/backend/internal/pkg/game/world"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGameStateRewinder_RewindState(t *testing.T) {
+	blockStorage := NewBlockStorage()
+	gameInstance := game.NewGame()
+	gameLock := NewGameLock()
+
+	genesisBlock := createTestBlock(1000)
+	_, err := blockStorage.Add(genesisBlock)
+	assert.NoError(t, err)
+
+	firstBlock := createTestChildBlock(t, genesisBlock, 2000)
+	_, err = blockStorage.Add(firstBlock)
+	assert.NoError(t, err)
+
+	_, err = blockStorage.Add(createTestChildBlock(t, firstBlock, 3000))
+	assert.NoError(t, err)
+
+	blockReplayer := NewBlockReplayer(blockStorage, NewEventStorage(), NewEventResults(0), nil, gameInstance)
+	assert.NoError(t, blockReplayer.Replay(context.TODO()))
+
+	gameLock.Lock()
+	gameLock.blockApplied(2)
+	gameLock.Unlock()
+
+	rewinder := NewGameStateRewinder(gameInstance, gameLock, blockStorage, blockReplayer)
+	assert.NoError(t, rewinder.RewindState(context.TODO(), 1))
+	gameLock.Lock()
+	assert.Equal(t, 1, gameLock.height())
+	gameLock.Unlock()
+
+	_, err = gameInstance.WorldClock().SetCurrentTimestamp(1999)
+	assert.ErrorIs(t, err, world.ErrCurrentTimeLessThanLastTimeEvent)
+
+	_, err = gameInstance.WorldClock().SetCurrentTimestamp(2500)
+	assert.NoError(t, err)
+
+	assert.NoError(t, blockStorage.Truncate(1))
+	assert.Error(t, rewinder.RewindState(context.TODO(), 2))
+}
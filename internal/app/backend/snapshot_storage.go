@@ -0,0 +1,219 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	"github.com/dominati-one/backend/internal/pkg/game"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	snapshotStorageDirectoryName = "snapshots"
+	snapshotStorageFilePrefix    = "snapshot-"
+	snapshotStorageFileSuffix    = ".bin"
+
+	snapshotStorageMagic   uint32 = 0x444f534e // "DOSN"
+	snapshotStorageVersion uint32 = 1
+
+	// snapshotStorageHeaderSize is size of magic, version, height, block id, payload length and payload CRC32.
+	snapshotStorageHeaderSize = 4 + 4 + 8 + 32 + 8 + 4
+
+	DefaultSnapshotStorageKeepCount = 2
+)
+
+// SnapshotStorage keeps game snapshots taken after block at given height. Each snapshot is separate file written
+// atomically, only newest snapshots are kept.
+type SnapshotStorage struct {
+	log       zerolog.Logger
+	directory string
+	keepCount int
+	state     sync.Mutex
+}
+
+func NewSnapshotStorage(dataDirectory string, keepCount int) (*SnapshotStorage, error) {
+	directory := filepath.Join(dataDirectory, snapshotStorageDirectoryName)
+
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, errors.Wrap(err, "unable to create snapshot directory")
+	}
+
+	if keepCount < 1 {
+		keepCount = DefaultSnapshotStorageKeepCount
+	}
+
+	return &SnapshotStorage{
+		log:       log.With().Str("applicationComponent", "snapshotStorage").Str("path", directory).Logger(),
+		directory: directory,
+		keepCount: keepCount,
+	}, nil
+}
+
+// Save stores snapshot of game state after block with given id and height was applied.
+func (s *SnapshotStorage) Save(height int, blockId blockchain.BlockId, game *game.Game) error {
+	payload := &bytes.Buffer{}
+	if err := game.WriteSnapshot(payload); err != nil {
+		return errors.Wrap(err, "unable to write game snapshot")
+	}
+
+	header := make([]byte, snapshotStorageHeaderSize)
+	binary.BigEndian.PutUint32(header[0:4], snapshotStorageMagic)
+	binary.BigEndian.PutUint32(header[4:8], snapshotStorageVersion)
+	binary.BigEndian.PutUint64(header[8:16], uint64(height))
+	copy(header[16:48], blockId.Bytes())
+	binary.BigEndian.PutUint64(header[48:56], uint64(payload.Len()))
+	binary.BigEndian.PutUint32(header[56:60], crc32.ChecksumIEEE(payload.Bytes()))
+
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	if err := s.writeAtomically(s.path(height), header, payload.Bytes()); err != nil {
+		return errors.Wrap(err, "unable to write snapshot file")
+	}
+
+	s.log.Info().Int("height", height).Str("blockId", blockId.String()).Int("size", payload.Len()).Msg("Snapshot saved.")
+
+	return s.prune()
+}
+
+// Heights returns heights of stored snapshots, newest first.
+func (s *SnapshotStorage) Heights() ([]int, error) {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	return s.heights()
+}
+
+// Load restores game from snapshot at given height. Snapshot is rejected, when it was not taken after block with
+// given id, so snapshots left from different chain are never used.
+func (s *SnapshotStorage) Load(height int, blockId blockchain.BlockId, game *game.Game) error {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	content, err := ioutil.ReadFile(s.path(height))
+	if err != nil {
+		return errors.Wrap(err, "unable to read snapshot file")
+	}
+
+	if len(content) < snapshotStorageHeaderSize ||
+		binary.BigEndian.Uint32(content[0:4]) != snapshotStorageMagic {
+		return ErrSnapshotStorageCorrupted
+	}
+
+	if binary.BigEndian.Uint32(content[4:8]) != snapshotStorageVersion {
+		return ErrSnapshotStorageUnsupportedVersion
+	}
+
+	payload := content[snapshotStorageHeaderSize:]
+	if binary.BigEndian.Uint64(content[8:16]) != uint64(height) ||
+		binary.BigEndian.Uint64(content[48:56]) != uint64(len(payload)) ||
+		binary.BigEndian.Uint32(content[56:60]) != crc32.ChecksumIEEE(payload) {
+		return ErrSnapshotStorageCorrupted
+	}
+
+	if !bytes.Equal(content[16:48], blockId.Bytes()) {
+		return ErrSnapshotStorageBlockMismatch
+	}
+
+	if err := game.ReadSnapshot(bytes.NewReader(payload)); err != nil {
+		return errors.Wrap(err, "unable to read game snapshot")
+	}
+
+	return nil
+}
+
+func (s *SnapshotStorage) path(height int) string {
+	return filepath.Join(s.directory, fmt.Sprintf("%s%012d%s", snapshotStorageFilePrefix, height, snapshotStorageFileSuffix))
+}
+
+func (s *SnapshotStorage) heights() ([]int, error) {
+	files, err := ioutil.ReadDir(s.directory)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list snapshot directory")
+	}
+
+	heights := []int{}
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasPrefix(name, snapshotStorageFilePrefix) || !strings.HasSuffix(name, snapshotStorageFileSuffix) {
+			continue
+		}
+
+		height, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, snapshotStorageFilePrefix), snapshotStorageFileSuffix))
+		if err != nil {
+			continue
+		}
+
+		heights = append(heights, height)
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(heights)))
+
+	return heights, nil
+}
+
+// writeAtomically writes file under temporary name and renames it, so crash never leaves partially written snapshot.
+func (s *SnapshotStorage) writeAtomically(path string, header []byte, payload []byte) error {
+	file, err := ioutil.TempFile(s.directory, "tmp-")
+	if err != nil {
+		return err
+	}
+	temporaryPath := file.Name()
+
+	_, err = file.Write(header)
+	if err == nil {
+		_, err = file.Write(payload)
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temporaryPath, path)
+	}
+
+	if err != nil {
+		_ = os.Remove(temporaryPath)
+		return err
+	}
+
+	return s.syncDirectory()
+}
+
+func (s *SnapshotStorage) syncDirectory() error {
+	return syncDirectory(s.directory)
+}
+
+func (s *SnapshotStorage) prune() error {
+	heights, err := s.heights()
+	if err != nil {
+		return err
+	}
+
+	for index := s.keepCount; index < len(heights); index++ {
+		if err := os.Remove(s.path(heights[index])); err != nil {
+			return errors.Wrap(err, "unable to remove old snapshot")
+		}
+	}
+
+	return nil
+}
+
+var (
+	ErrSnapshotStorageCorrupted          = errors.New("snapshot storage corrupted")
+	ErrSnapshotStorageUnsupportedVersion = errors.New("snapshot storage unsupported version")
+	ErrSnapshotStorageBlockMismatch      = errors.New("snapshot storage block mismatch")
+)
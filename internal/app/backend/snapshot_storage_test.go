@@ -0,0 +1,66 @@
+package backend
+
+import (
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	"github.com/dominati-one/backend/internal/pkg/game"
+	"github.com/dominati-one/backend/internal/pkg/game/world"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestSnapshotStorage_Save(t *testing.T) {
+	dataDirectory := t.TempDir()
+
+	storage, err := NewSnapshotStorage(dataDirectory, 2)
+	assert.NoError(t, err)
+
+	gameInstance := game.NewGame()
+	_, err = gameInstance.WorldClock().SetCurrentTimestamp(5000)
+	assert.NoError(t, err)
+
+	blockId := blockchain.BlockId{1}
+
+	assert.NoError(t, storage.Save(10, blockchain.BlockId{}, gameInstance))
+	assert.NoError(t, storage.Save(20, blockchain.BlockId{}, gameInstance))
+	assert.NoError(t, storage.Save(30, blockId, gameInstance))
+
+	heights, err := storage.Heights()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{30, 20}, heights)
+
+	restoredGame := game.NewGame()
+
+	err = storage.Load(30, blockchain.BlockId{2}, restoredGame)
+	assert.ErrorIs(t, err, ErrSnapshotStorageBlockMismatch)
+
+	err = storage.Load(30, blockId, restoredGame)
+	assert.NoError(t, err)
+
+	_, err = restoredGame.WorldClock().SetCurrentTimestamp(4999)
+	assert.ErrorIs(t, err, world.ErrCurrentTimeLessThanLastTimeEvent)
+}
+
+func TestSnapshotStorage_LoadCorrupted(t *testing.T) {
+	dataDirectory := t.TempDir()
+
+	storage, err := NewSnapshotStorage(dataDirectory, 2)
+	assert.NoError(t, err)
+
+	assert.NoError(t, storage.Save(10, blockchain.BlockId{}, game.NewGame()))
+
+	content, err := ioutil.ReadFile(storage.path(10))
+	assert.NoError(t, err)
+
+	content[len(content)-1] ^= 0xff
+	assert.NoError(t, ioutil.WriteFile(storage.path(10), content, 0644))
+
+	err = storage.Load(10, blockchain.BlockId{}, game.NewGame())
+	assert.ErrorIs(t, err, ErrSnapshotStorageCorrupted)
+
+	assert.NoError(t, os.Truncate(storage.path(10), 10))
+
+	err = storage.Load(10, blockchain.BlockId{}, game.NewGame())
+	assert.ErrorIs(t, err, ErrSnapshotStorageCorrupted)
+}
@@ -1,17 +1,22 @@
 package blockchain
 
 import (
+	"bytes"
 	"context"
 	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"sync"
 	"time"
 )
 
 type blockBlockchainBacklogReceiverDependencies struct {
 	connector           Connector
 	blockStorage        BlockStorage
+	forkStorage         *ForkStorage
+	eventStorage        EventStorage
+	stateRewinder       StateRewinder
 	blockValidator      *BlockValidator
 	localBlockBacklog   *LocalBlockBacklog
 	localEventBacklog   *LocalEventBacklog
@@ -21,27 +26,72 @@ type blockBlockchainBacklogReceiverDependencies struct {
 
 type BlockBlockchainBacklogReceiver struct {
 	blockBlockchainBacklogReceiverDependencies
-	log zerolog.Logger
+	log               zerolog.Logger
+	chainSynchronizer *ChainSynchronizer
+	synchronized      chan struct{}
+	loops             sync.WaitGroup
 }
 
 func NewBlockBlockchainBacklogReceiver(dependencies blockBlockchainBacklogReceiverDependencies) *BlockBlockchainBacklogReceiver {
-	return &BlockBlockchainBacklogReceiver{
+	r := &BlockBlockchainBacklogReceiver{
 		log: log.With().Str("applicationComponent", "blockchain").Str("blockchainComponent", "blockBlockchainBacklogReceiver").Logger(),
 		blockBlockchainBacklogReceiverDependencies: dependencies,
+		synchronized: make(chan struct{}),
 	}
+
+	if syncConnector, ok := dependencies.connector.(SyncConnector); ok {
+		r.chainSynchronizer = NewChainSynchronizer(syncConnector, dependencies.blockStorage, r.processBlock)
+	}
+
+	return r
 }
 
+// Start synchronizes chain with peers, when connector supports it, and then switches to live backlog processing.
 func (r *BlockBlockchainBacklogReceiver) Start(ctx context.Context) error {
+	r.loops.Add(1)
+
 	go func() {
-		for range time.NewTicker(time.Millisecond * 100).C {
+		defer r.loops.Done()
+
+		r.synchronize(ctx)
+		close(r.synchronized)
+
+		ticker := time.NewTicker(time.Millisecond * 100)
+		defer ticker.Stop()
+
+		for waitForTick(ctx, ticker) {
 			r.loop(ctx)
 		}
 	}()
+
 	return nil
 }
 
+// Wait blocks until receiver loop finished. Loop finishes, when context passed to Start is done.
+func (r *BlockBlockchainBacklogReceiver) Wait() {
+	r.loops.Wait()
+}
+
+// Synchronized is closed, when initial chain synchronization finished.
+func (r *BlockBlockchainBacklogReceiver) Synchronized() <-chan struct{} {
+	return r.synchronized
+}
+
+func (r *BlockBlockchainBacklogReceiver) synchronize(ctx context.Context) {
+	if r.chainSynchronizer == nil {
+		return
+	}
+
+	if err := r.chainSynchronizer.Sync(ctx); err != nil {
+		r.log.Warn().Err(err).Msg("Chain synchronization failed. Continuing with live backlog.")
+	}
+}
+
 func (r *BlockBlockchainBacklogReceiver) loop(ctx context.Context) {
 	blockchainBlock, err := r.connector.GetBacklogBlock(ctx)
+	if ctx.Err() != nil {
+		return
+	}
 	if err != nil {
 		log.Warn().Err(err).Msg("Unable to read block from blockchain backlog.")
 		return
@@ -59,49 +109,339 @@ func (r *BlockBlockchainBacklogReceiver) loop(ctx context.Context) {
 		return
 	}
 
+	// Unknown parent means node fell behind, missing blocks are downloaded before block is processed.
+	if r.chainSynchronizer != nil && !r.knownBlock(previousBlockId(blockchainBlock)) {
+		log.Info().Msg("Parent of block received from blockchain backlog is unknown. Synchronizing chain.")
+		r.synchronize(ctx)
+
+		if r.knownBlock(*blockId) {
+			return
+		}
+	}
+
+	if err := r.processBlock(ctx, blockchainBlock); err != nil {
+		log.Warn().Err(err).Msg("Unable to process block from blockchain backlog.")
+		return
+	}
+
+	log.Debug().Msg("Block received from blockchain backlog processed.")
+}
+
+func (r *BlockBlockchainBacklogReceiver) knownBlock(blockId BlockId) bool {
+	return r.blockStorage.Exists(blockId) || r.forkStorage.Exists(blockId)
+}
+
+// processBlock extends main chain with block or keeps it in side branch. Chain is reorganized, when side branch
+// becomes preferred over main chain.
+func (r *BlockBlockchainBacklogReceiver) processBlock(ctx context.Context, blockchainBlock *blockchainProtocol.Block) error {
+	blockId, err := NewBlockId(blockchainBlock)
+	if err != nil {
+		return errors.Wrap(err, "unable to generate block id")
+	}
+
+	if r.knownBlock(*blockId) {
+		return nil
+	}
+
 	latestBlock, err := r.blockStorage.GetLatestBlock()
 	if err != nil {
-		log.Warn().Err(err).Msg("Unable to read latest block from storage.")
-		return
+		return errors.Wrap(err, "unable to read latest block from storage")
 	}
 
-	err = r.blockValidator.Validate(latestBlock, blockchainBlock)
+	latestBlockId, err := NewBlockId(latestBlock)
 	if err != nil {
-		log.Warn().Err(err).Msg("Unable to validate block from blockchain backlog.")
-		return
+		return errors.Wrap(err, "unable to generate latest block id")
+	}
+
+	defer func() {
+		r.forkStorage.Prune(r.blockStorage.Count() - 1 - DefaultForkMaxDepth)
+	}()
+
+	parentBlockId := previousBlockId(blockchainBlock)
+	if parentBlockId == *latestBlockId {
+		return r.acceptBlock(ctx, blockchainBlock)
+	}
+
+	parentBlock, parentHeight, err := r.getBlock(parentBlockId)
+	if err != nil {
+		return err
+	}
+
+	if err := r.blockValidator.ValidateStructure(parentBlock, blockchainBlock); err != nil {
+		return errors.Wrap(err, "unable to validate side branch block")
+	}
+
+	height := parentHeight + 1
+	r.forkStorage.Add(*blockId, blockchainBlock, height)
+
+	if !preferredBranch(height, *blockId, r.blockStorage.Count()-1, *latestBlockId) {
+		r.log.Debug().Str("blockId", blockId.String()).Int("height", height).Msg("Block added to side branch.")
+		return nil
+	}
+
+	return r.reorganize(ctx, blockchainBlock)
+}
+
+// preferredBranch implements fork choice rule. Longest chain wins, tie is broken by lower tip block id, so all nodes
+// pick the same branch regardless of order in which blocks arrived.
+func preferredBranch(height int, blockId BlockId, tipHeight int, tipBlockId BlockId) bool {
+	if height != tipHeight {
+		return height > tipHeight
+	}
+
+	return bytes.Compare(blockId.Bytes(), tipBlockId.Bytes()) < 0
+}
+
+// getBlock returns block with its height from main chain or from side branch.
+func (r *BlockBlockchainBacklogReceiver) getBlock(blockId BlockId) (*blockchainProtocol.Block, int, error) {
+	if block, height, exists := r.forkStorage.Get(blockId); exists {
+		return block, height, nil
+	}
+
+	if !r.blockStorage.Exists(blockId) {
+		return nil, 0, ErrBlockReceiverUnknownParent
+	}
+
+	block, err := r.blockStorage.Get(blockId)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "unable to read block from storage")
+	}
+
+	height, err := r.blockStorage.GetHeight(blockId)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "unable to read block height from storage")
+	}
+
+	return block, height, nil
+}
+
+// reorganize switches main chain to side branch ending with given block. Blocks of main chain above common ancestor
+// are moved to side branch and their events, which are not part of new branch, are returned to network backlog.
+// Previous main chain is restored, when new branch can not be applied.
+func (r *BlockBlockchainBacklogReceiver) reorganize(ctx context.Context, tipBlock *blockchainProtocol.Block) error {
+	branch := []*blockchainProtocol.Block{tipBlock}
+	ancestorBlockId := previousBlockId(tipBlock)
+
+	for !r.blockStorage.Exists(ancestorBlockId) {
+		block, _, exists := r.forkStorage.Get(ancestorBlockId)
+		if !exists {
+			return ErrBlockReceiverUnknownParent
+		}
+
+		branch = append([]*blockchainProtocol.Block{block}, branch...)
+		ancestorBlockId = previousBlockId(block)
+	}
+
+	ancestorHeight, err := r.blockStorage.GetHeight(ancestorBlockId)
+	if err != nil {
+		return errors.Wrap(err, "unable to read common ancestor height")
+	}
+
+	log := r.log.With().Str("ancestorBlockId", ancestorBlockId.String()).Int("ancestorHeight", ancestorHeight).Logger()
+
+	orphanedBlocks, err := r.detachBlocks(ctx, ancestorHeight)
+	if err != nil {
+		r.log.Panic().Err(err).Msg("Unable to detach blocks from main chain. Inconsistency detected.")
+	}
+
+	if err := r.attachBlocks(ctx, branch); err != nil {
+		log.Warn().Err(err).Msg("Unable to switch to side branch. Restoring previous main chain.")
+
+		if _, err := r.detachBlocks(ctx, ancestorHeight); err != nil {
+			r.log.Panic().Err(err).Msg("Unable to detach blocks of side branch. Inconsistency detected.")
+		}
+
+		for _, block := range branch {
+			if blockId, err := NewBlockId(block); err == nil {
+				r.forkStorage.Remove(*blockId)
+			}
+		}
+
+		if err := r.attachBlocks(ctx, orphanedBlocks); err != nil {
+			r.log.Panic().Err(err).Msg("Unable to restore previous main chain. Inconsistency detected.")
+		}
+
+		return errors.Wrap(err, "unable to switch to side branch")
+	}
+
+	if err := r.requeueOrphanedEvents(orphanedBlocks, branch); err != nil {
+		return errors.Wrap(err, "unable to requeue orphaned events")
+	}
+
+	log.Info().
+		Int("orphanedBlocksCount", len(orphanedBlocks)).
+		Int("branchBlocksCount", len(branch)).
+		Int("height", r.blockStorage.Count()-1).
+		Msg("Chain reorganized.")
+
+	return nil
+}
+
+// detachBlocks rewinds state and removes blocks above given height from main chain. Removed blocks are kept in side
+// branch and returned in order.
+func (r *BlockBlockchainBacklogReceiver) detachBlocks(ctx context.Context, height int) ([]*blockchainProtocol.Block, error) {
+	blocks := []*blockchainProtocol.Block{}
+
+	for blockHeight := height + 1; blockHeight < r.blockStorage.Count(); blockHeight++ {
+		block, err := r.blockStorage.GetByHeight(blockHeight)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read block at height %d", blockHeight)
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	if r.stateRewinder != nil {
+		if err := r.stateRewinder.RewindState(ctx, height); err != nil {
+			return nil, errors.Wrap(err, "unable to rewind state")
+		}
+	}
+
+	if err := r.blockStorage.Truncate(height); err != nil {
+		return nil, errors.Wrap(err, "unable to truncate block storage")
+	}
+
+	for index, block := range blocks {
+		blockId, err := NewBlockId(block)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to generate block id")
+		}
+
+		r.forkStorage.Add(*blockId, block, height+1+index)
+
+		for _, blockEvent := range block.Body.Events {
+			eventId, err := NewEventId(blockEvent.Event)
+			if err != nil {
+				return nil, errors.Wrap(err, "unable to generate event id")
+			}
+
+			r.eventStorage.Remove(eventId)
+		}
+	}
+
+	return blocks, nil
+}
+
+// attachBlocks moves blocks from side branch to main chain in order.
+func (r *BlockBlockchainBacklogReceiver) attachBlocks(ctx context.Context, blocks []*blockchainProtocol.Block) error {
+	for _, block := range blocks {
+		blockId, err := NewBlockId(block)
+		if err != nil {
+			return errors.Wrap(err, "unable to generate block id")
+		}
+
+		r.forkStorage.Remove(*blockId)
+
+		if err := r.acceptBlock(ctx, block); err != nil {
+			return errors.Wrapf(err, "unable to accept block %s", blockId.String())
+		}
+	}
+
+	return nil
+}
+
+func (r *BlockBlockchainBacklogReceiver) requeueOrphanedEvents(orphanedBlocks []*blockchainProtocol.Block, branch []*blockchainProtocol.Block) error {
+	branchEventIds := map[EventId]struct{}{}
+
+	for _, block := range branch {
+		for _, blockEvent := range block.Body.Events {
+			eventId, err := NewEventId(blockEvent.Event)
+			if err != nil {
+				return errors.Wrap(err, "unable to generate event id")
+			}
+
+			branchEventIds[eventId] = struct{}{}
+		}
+	}
+
+	for _, block := range orphanedBlocks {
+		for _, blockEvent := range block.Body.Events {
+			eventId, err := NewEventId(blockEvent.Event)
+			if err != nil {
+				return errors.Wrap(err, "unable to generate event id")
+			}
+
+			if _, exists := branchEventIds[eventId]; exists {
+				continue
+			}
+
+			r.networkEventBacklog.Requeue(eventId, blockEvent.Event)
+		}
+	}
+
+	return nil
+}
+
+// acceptBlock validates block against latest block in storage, emits it with its events and stores it.
+func (r *BlockBlockchainBacklogReceiver) acceptBlock(ctx context.Context, blockchainBlock *blockchainProtocol.Block) error {
+	blockId, err := NewBlockId(blockchainBlock)
+	if err != nil {
+		return errors.Wrap(err, "unable to generate block id")
+	}
+
+	latestBlock, err := r.blockStorage.GetLatestBlock()
+	if err != nil {
+		return errors.Wrap(err, "unable to read latest block from storage")
+	}
+
+	err = r.blockValidator.Validate(ctx, latestBlock, blockchainBlock)
+	if err != nil {
+		return errors.Wrap(err, "unable to validate block")
 	}
 
 	err = r.eventEmitter.emitBlock(blockchainBlock)
 	if err != nil {
-		log.Warn().Err(err).Msg("Unable to emit block.")
-		return
+		return errors.Wrap(err, "unable to emit block")
 	}
 
 	for _, blockEvent := range blockchainBlock.Body.Events {
-		if err := r.processEvent(blockEvent.Event); err != nil {
-			log.Panic().Err(err).Msg("Unable to process event. Inconsistency detected.")
-			return
+		if err := r.processEvent(*blockId, blockEvent.Event); err != nil {
+			r.log.Panic().Err(err).Str("blockId", blockId.String()).Msg("Unable to process event. Inconsistency detected.")
 		}
 	}
 
 	_, err = r.blockStorage.Add(blockchainBlock)
 	if err != nil {
-		log.Warn().Err(err).Msg("Unable to add block from blockchain backlog to storage.")
-		return
+		return errors.Wrap(err, "unable to add block to storage")
 	}
 
 	if r.localBlockBacklog.Exists(*blockId) {
 		if err := r.localBlockBacklog.MarkAsConfirmed(*blockId); err != nil {
-			log.Warn().Err(err).Msg("Unable to mark block from blockchain backlog as received in local backlog.")
-			return
+			return errors.Wrap(err, "unable to mark block as confirmed in local backlog")
 		}
 	}
 
-	log.Debug().Msg("Block received from blockchain backlog and add added to storage. Events processed without errors.")
+	r.pruneBacklogs(r.blockStorage.Count()-1, CreateBlockTimestampFromUnixMilliseconds(blockchainBlock.Body.Timestamp))
 
+	return nil
 }
 
-func (r *BlockBlockchainBacklogReceiver) processEvent(blockEvent *blockchainProtocol.Event) error {
+// pruneBacklogs removes items confirmed deep enough below given height and events expired before given block timestamp
+// from all backlogs.
+func (r *BlockBlockchainBacklogReceiver) pruneBacklogs(height int, blockTimestamp BlockTimestamp) {
+	r.localBlockBacklog.Prune(height)
+	r.networkEventBacklog.Prune(height)
+	r.networkEventBacklog.RemoveExpired(blockTimestamp)
+
+	if err := r.localEventBacklog.Prune(height); err != nil {
+		r.log.Warn().Err(err).Int("height", height).Msg("Unable to prune local event backlog.")
+	}
+
+	if err := r.localEventBacklog.RemoveExpired(blockTimestamp); err != nil {
+		r.log.Warn().Err(err).Int("height", height).Msg("Unable to remove expired events from local event backlog.")
+	}
+}
+
+func previousBlockId(block *blockchainProtocol.Block) BlockId {
+	blockId := BlockId{}
+	if block.Body != nil {
+		copy(blockId[:], block.Body.PreviousBlockId)
+	}
+
+	return blockId
+}
+
+func (r *BlockBlockchainBacklogReceiver) processEvent(blockId BlockId, blockEvent *blockchainProtocol.Event) error {
 	eventId, err := NewEventId(blockEvent)
 	if err != nil {
 		return errors.Wrap(err, "unable to generate event id")
@@ -119,9 +459,17 @@ func (r *BlockBlockchainBacklogReceiver) processEvent(blockEvent *blockchainProt
 		}
 	}
 
+	if _, err := r.eventStorage.Add(blockEvent, blockId); err != nil {
+		return errors.Wrap(err, "unable to add event to storage")
+	}
+
 	if err := r.eventEmitter.emitEvent(blockEvent); err != nil {
 		return errors.Wrap(err, "error while event emission")
 	}
 
 	return nil
 }
+
+var (
+	ErrBlockReceiverUnknownParent = errors.New("block receiver unknown parent")
+)
@@ -0,0 +1,114 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"github.com/dominati-one/backend/internal/pkg/security"
+	"github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type testStateRewinder struct {
+	heights []int
+}
+
+func (r *testStateRewinder) RewindState(ctx context.Context, height int) error {
+	r.heights = append(r.heights, height)
+
+	return nil
+}
+
+func TestBlockBlockchainBacklogReceiver_Reorganize(t *testing.T) {
+	authorityKey := testPrivateKey(t)
+	eventValidator := NewEventValidator(nil, NetworkSettings{})
+	eventStorage := newTestEventStorage()
+	blockStorage := &testChainBlockStorage{blocks: []*blockchain.Block{testGenesisBlock()}}
+	stateRewinder := &testStateRewinder{}
+	networkEventBacklog := NewNetworkEventBacklog(eventValidator, NetworkSettings{})
+	eventEmitter := NewEventEmitter()
+
+	blockValidator := NewBlockValidator(eventValidator, eventStorage, nil, NetworkSettings{
+		AuthorityPublicKeys: security.NewPublicKeysBag([]crypto.PublicKey{authorityKey.PublicKey()}),
+	})
+
+	receiver := NewBlockBlockchainBacklogReceiver(blockBlockchainBacklogReceiverDependencies{
+		blockStorage:        blockStorage,
+		forkStorage:         NewForkStorage(),
+		eventStorage:        eventStorage,
+		stateRewinder:       stateRewinder,
+		blockValidator:      blockValidator,
+		localBlockBacklog:   NewLocalBlockBacklog(blockValidator, NetworkSettings{}),
+		localEventBacklog:   NewLocalEventBacklog(eventValidator, authorityKey, nil, NetworkSettings{}),
+		networkEventBacklog: networkEventBacklog,
+		eventEmitter:        eventEmitter,
+	})
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	genesisBlock := blockStorage.blocks[0]
+	genesisTimestamp := CreateBlockTimestampFromUnixMilliseconds(genesisBlock.Body.Timestamp)
+
+	orphanedEvent := createSignedEventAt(t, testPrivateKey(t), &blockchain.EventCreatePlanet{}, genesisTimestamp)
+	sharedEvent := createSignedEventAt(t, testPrivateKey(t), &blockchain.EventCreatePlanet{}, genesisTimestamp)
+
+	mainBlock := buildTestBlockAt(t, genesisBlock, []*blockchain.Event{orphanedEvent, sharedEvent}, authorityKey, time.Second)
+	assert.NoError(t, receiver.processBlock(ctx, mainBlock))
+	assert.Equal(t, 2, blockStorage.Count())
+
+	sideBlock := buildTestBlockAt(t, genesisBlock, []*blockchain.Event{sharedEvent}, authorityKey, 2*time.Second)
+	sideBlockId := testBlockId(t, sideBlock)
+	mainBlockId := testBlockId(t, mainBlock)
+
+	// Equal height, lower block id wins.
+	assert.NoError(t, receiver.processBlock(ctx, sideBlock))
+	if bytes.Compare(sideBlockId.Bytes(), mainBlockId.Bytes()) < 0 {
+		assert.True(t, blockStorage.Exists(*sideBlockId))
+		assert.True(t, receiver.forkStorage.Exists(*mainBlockId))
+	} else {
+		assert.True(t, blockStorage.Exists(*mainBlockId))
+		assert.True(t, receiver.forkStorage.Exists(*sideBlockId))
+	}
+
+	// Longer side branch wins.
+	nextSideBlock := buildTestBlockAt(t, sideBlock, []*blockchain.Event{}, authorityKey, time.Second)
+	assert.NoError(t, receiver.processBlock(ctx, nextSideBlock))
+	assert.Equal(t, 3, blockStorage.Count())
+	assert.True(t, blockStorage.Exists(*sideBlockId))
+	assert.True(t, blockStorage.Exists(*testBlockId(t, nextSideBlock)))
+	assert.True(t, receiver.forkStorage.Exists(*mainBlockId))
+	assert.Contains(t, stateRewinder.heights, 0)
+
+	assert.True(t, eventStorage.Exists(*MustEventId(sharedEvent)))
+	assert.False(t, eventStorage.Exists(*MustEventId(orphanedEvent)))
+
+	unconfirmedEvents := networkEventBacklog.Unconfirmed()
+	assert.Len(t, unconfirmedEvents, 1)
+	assert.Contains(t, unconfirmedEvents, *MustEventId(orphanedEvent))
+
+	unknownParentBlock := buildTestBlockAt(t, buildTestBlockAt(t, mainBlock, []*blockchain.Event{}, authorityKey, time.Second), []*blockchain.Event{}, authorityKey, time.Second)
+	assert.ErrorIs(t, receiver.processBlock(ctx, unknownParentBlock), ErrBlockReceiverUnknownParent)
+}
+
+func buildTestBlockAt(t *testing.T, previousBlock *blockchain.Block, events []*blockchain.Event, privateKey *security.PrivateKey, after time.Duration) *blockchain.Block {
+	blockTimestamp := CreateBlockTimestampFromUnixMilliseconds(previousBlock.Body.Timestamp).Add(after)
+
+	block, err := NewBlockBuilder(previousBlock, events, nil, nil, privateKey, NetworkSettings{}).Build(context.TODO(), blockTimestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return block
+}
+
+func testBlockId(t *testing.T, block *blockchain.Block) *BlockId {
+	blockId, err := NewBlockId(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return blockId
+}
@@ -1,31 +1,56 @@
 package blockchain
 
 import (
+	"bytes"
+	"context"
 	"crypto/sha256"
+	"github.com/dominati-one/backend/internal/pkg/security"
 	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
+	"sort"
+)
+
+const (
+	DefaultBlockMaxEvents     = 1000
+	DefaultBlockMaxEventsSize = 1024 * 1024
 )
 
 type BlockBuilder struct {
-	previousBlock *blockchainProtocol.Block
-	events        []*blockchainProtocol.Event
+	previousBlock  *blockchainProtocol.Block
+	events         []*blockchainProtocol.Event
+	eventValidator *EventValidator
+	stateHasher    StateHasher
+	privateKey     *security.PrivateKey
+	maxEvents      int
+	maxEventsSize  int
 }
 
-func NewBlockBuilder(previousBlock *blockchainProtocol.Block, events []*blockchainProtocol.Event) *BlockBuilder {
+// NewBlockBuilder creates builder. Events reusing nonce of stored event are not dropped, when event validator is nil.
+// State hash is not committed in block, when state hasher is nil.
+func NewBlockBuilder(previousBlock *blockchainProtocol.Block, events []*blockchainProtocol.Event, eventValidator *EventValidator, stateHasher StateHasher, privateKey *security.PrivateKey, settings NetworkSettings) *BlockBuilder {
 	return &BlockBuilder{
-		previousBlock: previousBlock,
-		events:        events,
+		previousBlock:  previousBlock,
+		events:         events,
+		eventValidator: eventValidator,
+		stateHasher:    stateHasher,
+		privateKey:     privateKey,
+		maxEvents:      settings.blockMaxEvents(),
+		maxEventsSize:  settings.blockMaxEventsSize(),
 	}
 }
 
-func (b *BlockBuilder) Build(blockTimestamp BlockTimestamp) (*blockchainProtocol.Block, error) {
+// Build creates block on top of previous block, commits resulting state hash and seals it with authority private key.
+// Events are placed in block in order of start of validity window and event id, until block is full. Events, which do
+// not fit, are left for next block. Event reusing nonce of earlier event in block or of stored event is dropped,
+// because block validator rejects block with replayed event.
+func (b *BlockBuilder) Build(ctx context.Context, blockTimestamp BlockTimestamp) (*blockchainProtocol.Block, error) {
 	previousBlockId, err := NewBlockId(b.previousBlock)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to calculate previous block id")
 	}
 
-	blockEvents := []*blockchainProtocol.Block_Body_BlockEvent{}
+	orderedEvents := []*blockchainProtocol.Block_Body_BlockEvent{}
 
 	for _, event := range b.events {
 		eventId, err := NewEventId(event)
@@ -33,33 +58,96 @@ func (b *BlockBuilder) Build(blockTimestamp BlockTimestamp) (*blockchainProtocol
 			return nil, errors.Wrap(err, "unable to calculate event id")
 		}
 
-		blockEvents = append(blockEvents, &blockchainProtocol.Block_Body_BlockEvent{
+		orderedEvents = append(orderedEvents, &blockchainProtocol.Block_Body_BlockEvent{
 			Id:    eventId.Bytes(),
 			Event: event,
 		})
 	}
 
+	sort.Slice(orderedEvents, func(i, j int) bool {
+		return blockEventLess(orderedEvents[i], orderedEvents[j])
+	})
+
+	blockEvents := []*blockchainProtocol.Block_Body_BlockEvent{}
+	blockNonces := map[eventNonce]struct{}{}
+	blockEventsSize := 0
+
+	for _, blockEvent := range orderedEvents {
+		if len(blockEvents) >= b.maxEvents {
+			break
+		}
+
+		blockEventSize := proto.Size(blockEvent)
+		if blockEventsSize+blockEventSize > b.maxEventsSize {
+			continue
+		}
+
+		// Event without valid signature has no nonce to track, block validator rejects it on its own.
+		nonce, err := newEventNonce(blockEvent.Event)
+		if _, exists := blockNonces[nonce]; err == nil && exists {
+			continue
+		}
+
+		if b.eventValidator != nil && b.eventValidator.ValidateNotReplayed(blockEvent.Event) != nil {
+			continue
+		}
+
+		blockEvents = append(blockEvents, blockEvent)
+		blockEventsSize += blockEventSize
+		if err == nil {
+			blockNonces[nonce] = struct{}{}
+		}
+	}
+
 	blockBody := &blockchainProtocol.Block_Body{
 		PreviousBlockId: previousBlockId.Bytes(),
 		Timestamp:       blockTimestamp.UnixMilliseconds(),
 		Events:          blockEvents,
 	}
+	blockBody.EventsRoot = NewBlockEventsRoot(blockBody)
+
+	if b.stateHasher != nil {
+		stateHash, err := b.stateHasher.HashBlockState(ctx, &blockchainProtocol.Block{Body: blockBody})
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to calculate state hash")
+		}
+		blockBody.StateHash = stateHash
+	}
+
+	blockHeader := NewBlockHeader(blockBody)
 
-	blockBodyBytes, err := proto.Marshal(blockBody)
+	blockHeaderBytes, err := proto.Marshal(blockHeader)
 	if err != nil {
 		return nil, ErrInvalidBlockBodyBytes
 	}
 
-	blockBodyHash := sha256.Sum256(blockBodyBytes)
+	blockHeaderHash := sha256.Sum256(blockHeaderBytes)
+
+	signature, err := security.CreateSignatureFromBlockBody(blockHeader, b.privateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to seal block")
+	}
 
 	block := &blockchainProtocol.Block{
-		Body:     blockBody,
-		Checksum: blockBodyHash[:],
+		Body:      blockBody,
+		Checksum:  blockHeaderHash[:],
+		Signature: signature.Bytes(),
 	}
 
 	return block, nil
 }
 
+// blockEventLess reports whether event a precedes event b in block. Events are ordered by start of validity window,
+// then by event id. Start of window is signed, so unlike event timestamp it can not be changed by relaying node to move
+// event ahead of others.
+func blockEventLess(a *blockchainProtocol.Block_Body_BlockEvent, b *blockchainProtocol.Block_Body_BlockEvent) bool {
+	if aValidFrom, bValidFrom := a.Event.Body.GetValidFrom(), b.Event.Body.GetValidFrom(); aValidFrom != bValidFrom {
+		return aValidFrom < bValidFrom
+	}
+
+	return bytes.Compare(a.Id, b.Id) < 0
+}
+
 var (
 	ErrInvalidBlockBodyBytes = errors.New("invalid block body bytes")
 )
@@ -0,0 +1,87 @@
+package blockchain
+
+import (
+	"context"
+	"github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBlockBuilder_Build(t *testing.T) {
+	authorityKey := testPrivateKey(t)
+	genesisBlock := testGenesisBlock()
+	now := CreateBlockTimestampFromNow()
+
+	firstEvent := createSignedEventAt(t, testPrivateKey(t), &blockchain.EventCreatePlanet{}, now.Add(-2*time.Second))
+	secondEvent := createSignedEventAt(t, testPrivateKey(t), &blockchain.EventCreatePlanet{}, now.Add(-time.Second))
+	thirdEvent := createSignedEventAt(t, testPrivateKey(t), &blockchain.EventCreatePlanet{}, now)
+	events := []*blockchain.Event{thirdEvent, firstEvent, secondEvent}
+
+	block, err := NewBlockBuilder(genesisBlock, events, nil, nil, authorityKey, NetworkSettings{}).Build(context.TODO(), now)
+	assert.NoError(t, err)
+	assert.Len(t, block.Body.Events, 3)
+	assert.Equal(t, MustEventId(firstEvent).Bytes(), block.Body.Events[0].Id)
+	assert.Equal(t, MustEventId(secondEvent).Bytes(), block.Body.Events[1].Id)
+	assert.Equal(t, MustEventId(thirdEvent).Bytes(), block.Body.Events[2].Id)
+
+	block, err = NewBlockBuilder(genesisBlock, events, nil, nil, authorityKey, NetworkSettings{BlockMaxEvents: 2}).Build(context.TODO(), now)
+	assert.NoError(t, err)
+	assert.Len(t, block.Body.Events, 2)
+	assert.Equal(t, MustEventId(secondEvent).Bytes(), block.Body.Events[1].Id)
+
+	maxEventsSize := proto.Size(block.Body.Events[0]) + proto.Size(block.Body.Events[1])
+	block, err = NewBlockBuilder(genesisBlock, events, nil, nil, authorityKey, NetworkSettings{BlockMaxEventsSize: maxEventsSize}).Build(context.TODO(), now)
+	assert.NoError(t, err)
+	assert.Len(t, block.Body.Events, 2)
+	assert.Equal(t, MustEventId(firstEvent).Bytes(), block.Body.Events[0].Id)
+}
+
+func TestBlockBuilder_BuildOrdersBySignedValidity(t *testing.T) {
+	authorityKey := testPrivateKey(t)
+	genesisBlock := testGenesisBlock()
+	now := CreateBlockTimestampFromNow()
+
+	firstEvent := createSignedEventAt(t, testPrivateKey(t), &blockchain.EventCreatePlanet{}, now.Add(-time.Second))
+	secondEvent := createSignedEventAt(t, testPrivateKey(t), &blockchain.EventCreatePlanet{}, now)
+	// Unsigned timestamp changed by relaying node does not move event ahead.
+	secondEvent.Timestamp = 0
+
+	block, err := NewBlockBuilder(genesisBlock, []*blockchain.Event{secondEvent, firstEvent}, nil, nil, authorityKey, NetworkSettings{}).Build(context.TODO(), now)
+	assert.NoError(t, err)
+	assert.Len(t, block.Body.Events, 2)
+	assert.Equal(t, MustEventId(firstEvent).Bytes(), block.Body.Events[0].Id)
+	assert.Equal(t, MustEventId(secondEvent).Bytes(), block.Body.Events[1].Id)
+}
+
+func TestBlockBuilder_BuildDropsReplayedEvents(t *testing.T) {
+	authorityKey := testPrivateKey(t)
+	genesisBlock := testGenesisBlock()
+	now := CreateBlockTimestampFromNow()
+	eventStorage := newTestEventStorage()
+	eventValidator := NewEventValidator(eventStorage, NetworkSettings{})
+
+	eventKey := testPrivateKey(t)
+	event := createSignedEventAt(t, eventKey, &blockchain.EventCreatePlanet{}, now.Add(-time.Second))
+	sameNonceEvent := proto.Clone(event).(*blockchain.Event)
+	sameNonceEvent.Body.GetCreatePlanet().Seed = 1
+	sameNonceEvent.Body.ValidFrom = now.UnixMilliseconds()
+	sameNonceEvent.Timestamp = now.UnixMilliseconds()
+	signTestEvent(t, eventKey, sameNonceEvent)
+	otherEvent := createSignedEventAt(t, eventKey, &blockchain.EventCreatePlanet{}, now)
+
+	block, err := NewBlockBuilder(genesisBlock, []*blockchain.Event{sameNonceEvent, otherEvent, event}, eventValidator, nil, authorityKey, NetworkSettings{}).Build(context.TODO(), now)
+	assert.NoError(t, err)
+	assert.Len(t, block.Body.Events, 2)
+	assert.Equal(t, MustEventId(event).Bytes(), block.Body.Events[0].Id)
+	assert.Equal(t, MustEventId(otherEvent).Bytes(), block.Body.Events[1].Id)
+
+	_, err = eventStorage.Add(event, BlockId{})
+	assert.NoError(t, err)
+
+	block, err = NewBlockBuilder(genesisBlock, []*blockchain.Event{sameNonceEvent, otherEvent}, eventValidator, nil, authorityKey, NetworkSettings{}).Build(context.TODO(), now)
+	assert.NoError(t, err)
+	assert.Len(t, block.Body.Events, 1)
+	assert.Equal(t, MustEventId(otherEvent).Bytes(), block.Body.Events[0].Id)
+}
@@ -5,6 +5,13 @@ import blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockcha
 type BlockStorage interface {
 	Count() int
 	Add(block *blockchainProtocol.Block) (*BlockId, error)
+	Get(blockId BlockId) (*blockchainProtocol.Block, error)
+	// GetByHeight returns block at given height. Genesis block has height 0.
+	GetByHeight(height int) (*blockchainProtocol.Block, error)
 	GetLatestBlock() (*blockchainProtocol.Block, error)
+	// GetHeight returns height of stored block.
+	GetHeight(blockId BlockId) (int, error)
 	Exists(blockId BlockId) bool
+	// Truncate removes all blocks above given height, so block at that height becomes latest block.
+	Truncate(height int) error
 }
@@ -6,6 +6,13 @@ import (
 	"time"
 )
 
+const (
+	// blockTickerSlotMargin keeps block timestamps away from slot boundaries, so millisecond precision of timestamp
+	// does not move block to neighbouring slot.
+	blockTickerSlotMargin   = 50 * time.Millisecond
+	blockTickerPollInterval = 100 * time.Millisecond
+)
+
 type BlockTicker struct {
 	settings NetworkSettings
 }
@@ -31,6 +38,41 @@ func (t *BlockTicker) WaitForNext(ctx context.Context, previousBlockTimestamp Bl
 	return &nextBlockTimestamp, nil
 }
 
+// WaitForSlot waits until slot starts and returns block timestamp in slot. Waiting is cut short, when tip of chain
+// changes, because schedule of slots changes with it, nil timestamp is returned in such case. Nil timestamp is
+// returned also, when slot ends before block can be built.
+func (t *BlockTicker) WaitForSlot(ctx context.Context, slotStart BlockTimestamp, slotEnd BlockTimestamp, tipChanged func() bool) (*BlockTimestamp, error) {
+	slotStart = slotStart.Add(blockTickerSlotMargin)
+
+	for {
+		waitDuration := slotStart.Sub(time.Now())
+		if waitDuration <= 0 {
+			break
+		}
+
+		if waitDuration > blockTickerPollInterval {
+			waitDuration = blockTickerPollInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ErrCanceledBlockTimestampWait
+		case <-time.NewTimer(waitDuration).C:
+		}
+
+		if tipChanged() {
+			return nil, nil
+		}
+	}
+
+	blockTimestamp := CreateBlockTimestampFromNow()
+	if !blockTimestamp.Before(slotEnd.Add(-blockTickerSlotMargin).Time) {
+		return nil, nil
+	}
+
+	return &blockTimestamp, nil
+}
+
 func (t *BlockTicker) getNext(previousBlockTimestamp BlockTimestamp) BlockTimestamp {
 	nextBlockTimestamp := previousBlockTimestamp.Add(t.settings.BlockInterval)
 
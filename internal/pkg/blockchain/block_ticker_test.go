@@ -13,7 +13,8 @@ func TestBlockTicker_WaitForNext(t *testing.T) {
 
 	blockTicker := NewBlockTicker(NetworkSettings{BlockInterval: time.Second * 2})
 
-	timeoutCtx, _ := context.WithTimeout(context.TODO(), time.Millisecond)
+	timeoutCtx, cancel := context.WithTimeout(context.TODO(), time.Millisecond)
+	defer cancel()
 
 	blockTimestamp, err = blockTicker.WaitForNext(timeoutCtx, CreateBlockTimestampFromNow())
 	assert.ErrorIs(t, err, ErrCanceledBlockTimestampWait)
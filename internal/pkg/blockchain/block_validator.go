@@ -1,17 +1,332 @@
 package blockchain
 
-import blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"github.com/dominati-one/backend/internal/pkg/security"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	DefaultBlockMaxClockDrift = 5 * time.Second
+)
 
 type BlockValidator struct {
-	eventValidator *EventValidator
+	eventValidator      *EventValidator
+	eventStorage        EventStorage
+	stateHasher         StateHasher
+	authorityPublicKeys *security.PublicKeysBag
+	proposerSchedule    *ProposerSchedule
+	maxClockDrift       time.Duration
+	maxEvents           int
+	maxEventsSize       int
 }
 
-func NewBlockValidator(eventValidator *EventValidator) *BlockValidator {
+// NewBlockValidator creates validator. Committed state hash is not checked, when state hasher is nil.
+func NewBlockValidator(eventValidator *EventValidator, eventStorage EventStorage, stateHasher StateHasher, settings NetworkSettings) *BlockValidator {
+	maxClockDrift := settings.BlockMaxClockDrift
+	if maxClockDrift == 0 {
+		maxClockDrift = DefaultBlockMaxClockDrift
+	}
+
 	return &BlockValidator{
-		eventValidator: eventValidator,
+		eventValidator:      eventValidator,
+		eventStorage:        eventStorage,
+		stateHasher:         stateHasher,
+		authorityPublicKeys: settings.AuthorityPublicKeys,
+		proposerSchedule:    NewProposerSchedule(settings),
+		maxClockDrift:       maxClockDrift,
+		maxEvents:           settings.blockMaxEvents(),
+		maxEventsSize:       settings.blockMaxEventsSize(),
+	}
+}
+
+// Validate checks if current block is valid successor of previous block, which should be latest block in storage.
+func (v *BlockValidator) Validate(ctx context.Context, previousBlock *blockchainProtocol.Block, currentBlock *blockchainProtocol.Block) error {
+	if err := v.ValidateStructure(previousBlock, currentBlock); err != nil {
+		return err
+	}
+
+	if err := v.validateEventsNotStored(currentBlock); err != nil {
+		return err
+	}
+
+	if err := v.validateStateHash(ctx, currentBlock); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidateStructure checks if current block is valid successor of previous block without looking at event storage
+// and game state, so blocks of side branches, which are not applied yet, can be validated.
+func (v *BlockValidator) ValidateStructure(previousBlock *blockchainProtocol.Block, currentBlock *blockchainProtocol.Block) error {
+	if previousBlock.Body == nil || currentBlock.Body == nil {
+		return ErrBlockValidatorEmptyBody
+	}
+
+	if err := v.validateSeal(currentBlock); err != nil {
+		return err
+	}
+
+	if err := v.validatePreviousBlockId(previousBlock, currentBlock); err != nil {
+		return err
+	}
+
+	if err := v.validateProposer(previousBlock, currentBlock); err != nil {
+		return err
+	}
+
+	if err := v.validateChecksum(currentBlock); err != nil {
+		return err
+	}
+
+	if err := v.validateTimestamp(previousBlock, currentBlock); err != nil {
+		return err
+	}
+
+	if err := v.validateEvents(currentBlock); err != nil {
+		return err
+	}
+
+	if err := v.validateEventsRoot(currentBlock); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidateIntegrity checks parts of block, which do not depend on previous block, so block received from peer can be
+// checked before it is forwarded. Block is valid successor only after ValidateStructure passes as well.
+func (v *BlockValidator) ValidateIntegrity(block *blockchainProtocol.Block) error {
+	if block.Body == nil {
+		return ErrBlockValidatorEmptyBody
+	}
+
+	if err := v.validateSeal(block); err != nil {
+		return err
+	}
+
+	if err := v.validateChecksum(block); err != nil {
+		return err
+	}
+
+	if err := v.validateEvents(block); err != nil {
+		return err
+	}
+
+	if err := v.validateEventsRoot(block); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateSeal checks if block header was signed by one of network authorities.
+func (v *BlockValidator) validateSeal(block *blockchainProtocol.Block) error {
+	signature, err := security.NewSignature(block.Signature)
+	if err != nil {
+		return ErrBlockValidatorInvalidSignature
+	}
+
+	if !v.authorityPublicKeys.VerifySignature(*signature) {
+		return ErrBlockValidatorUnknownAuthority
+	}
+
+	if err := signature.VerifyBlockBody(NewBlockHeader(block.Body)); err != nil {
+		return ErrBlockValidatorInvalidSignature
+	}
+
+	return nil
+}
+
+func (v *BlockValidator) validatePreviousBlockId(previousBlock *blockchainProtocol.Block, currentBlock *blockchainProtocol.Block) error {
+	previousBlockId, err := NewBlockId(previousBlock)
+	if err != nil {
+		return errors.Wrap(err, "unable to calculate previous block id")
+	}
+
+	if !bytes.Equal(previousBlockId.Bytes(), currentBlock.Body.PreviousBlockId) {
+		return ErrBlockValidatorPreviousBlockIdMismatch
+	}
+
+	return nil
+}
+
+// validateProposer checks if block was signed by authority scheduled for slot of block timestamp.
+func (v *BlockValidator) validateProposer(previousBlock *blockchainProtocol.Block, currentBlock *blockchainProtocol.Block) error {
+	signature, err := security.NewSignature(currentBlock.Signature)
+	if err != nil {
+		return ErrBlockValidatorInvalidSignature
+	}
+
+	proposer, err := v.proposerSchedule.Proposer(previousBlock, CreateBlockTimestampFromUnixMilliseconds(currentBlock.Body.Timestamp))
+	if err != nil {
+		return errors.Wrap(err, "unable to get scheduled proposer")
+	}
+
+	if !proposer.Equal(signature.PublicKey()) {
+		return ErrBlockValidatorUnexpectedProposer
+	}
+
+	return nil
+}
+
+func (v *BlockValidator) validateChecksum(block *blockchainProtocol.Block) error {
+	blockHeaderBytes, err := proto.Marshal(NewBlockHeader(block.Body))
+	if err != nil {
+		return ErrInvalidBlockBodyBytes
+	}
+
+	blockHeaderHash := sha256.Sum256(blockHeaderBytes)
+
+	if !bytes.Equal(blockHeaderHash[:], block.Checksum) {
+		return ErrBlockValidatorChecksumMismatch
+	}
+
+	return nil
+}
+
+// validateEventsRoot checks if events root committed in block header matches events of block. Ids of events must be
+// validated already.
+func (v *BlockValidator) validateEventsRoot(block *blockchainProtocol.Block) error {
+	if !bytes.Equal(NewBlockEventsRoot(block.Body), block.Body.EventsRoot) {
+		return ErrBlockValidatorEventsRootMismatch
+	}
+
+	return nil
+}
+
+func (v *BlockValidator) validateTimestamp(previousBlock *blockchainProtocol.Block, currentBlock *blockchainProtocol.Block) error {
+	if currentBlock.Body.Timestamp <= previousBlock.Body.Timestamp {
+		return ErrBlockValidatorTimestampNotAfterPrevious
+	}
+
+	maxTimestamp := CreateBlockTimestampFromNow().Add(v.maxClockDrift)
+
+	if currentBlock.Body.Timestamp > maxTimestamp.UnixMilliseconds() {
+		return ErrBlockValidatorTimestampInFuture
+	}
+
+	return nil
+}
+
+func (v *BlockValidator) validateEvents(block *blockchainProtocol.Block) error {
+	if len(block.Body.Events) > v.maxEvents {
+		return ErrBlockValidatorTooManyEvents
+	}
+
+	blockTimestamp := CreateBlockTimestampFromUnixMilliseconds(block.Body.Timestamp)
+	blockEventIds := map[EventId]struct{}{}
+	blockNonces := map[eventNonce]struct{}{}
+	blockEventsSize := 0
+
+	for index, blockEvent := range block.Body.Events {
+		if blockEvent.Event == nil {
+			return ErrBlockValidatorInvalidEvent
+		}
+
+		blockEventsSize += proto.Size(blockEvent)
+		if blockEventsSize > v.maxEventsSize {
+			return ErrBlockValidatorEventsTooLarge
+		}
+
+		eventId, err := NewEventId(blockEvent.Event)
+		if err != nil {
+			return errors.Wrap(err, "unable to calculate event id")
+		}
+
+		if !bytes.Equal(eventId.Bytes(), blockEvent.Id) {
+			return ErrBlockValidatorEventIdMismatch
+		}
+
+		if _, exists := blockEventIds[eventId]; exists {
+			return ErrBlockValidatorEventDuplicated
+		}
+		blockEventIds[eventId] = struct{}{}
+
+		if index > 0 && !blockEventLess(block.Body.Events[index-1], blockEvent) {
+			return ErrBlockValidatorEventsNotOrdered
+		}
+
+		if err := v.eventValidator.ValidateAt(blockEvent.Event, blockTimestamp); err != nil {
+			return ErrBlockValidatorInvalidEvent
+		}
+
+		nonce, err := newEventNonce(blockEvent.Event)
+		if err != nil {
+			return ErrBlockValidatorInvalidEvent
+		}
+
+		if _, exists := blockNonces[nonce]; exists {
+			return ErrBlockValidatorEventReplayed
+		}
+		blockNonces[nonce] = struct{}{}
+	}
+
+	return nil
+}
+
+func (v *BlockValidator) validateEventsNotStored(block *blockchainProtocol.Block) error {
+	for _, blockEvent := range block.Body.Events {
+		eventId, err := NewEventId(blockEvent.Event)
+		if err != nil {
+			return errors.Wrap(err, "unable to calculate event id")
+		}
+
+		if v.eventStorage.Exists(eventId) {
+			return ErrBlockValidatorEventAlreadyStored
+		}
+
+		if err := v.eventValidator.ValidateNotReplayed(blockEvent.Event); err != nil {
+			return ErrBlockValidatorEventReplayed
+		}
 	}
+
+	return nil
 }
 
-func (v *BlockValidator) Validate(previousBlock *blockchainProtocol.Block, currentBlock *blockchainProtocol.Block) error {
+// validateStateHash checks if state committed in block matches state calculated locally, so divergence of game state
+// between nodes is detected before block is accepted.
+func (v *BlockValidator) validateStateHash(ctx context.Context, block *blockchainProtocol.Block) error {
+	if v.stateHasher == nil {
+		return nil
+	}
+
+	stateHash, err := v.stateHasher.HashBlockState(ctx, block)
+	if err != nil {
+		return ErrBlockValidatorInvalidState
+	}
+
+	if !bytes.Equal(stateHash, block.Body.StateHash) {
+		return ErrBlockValidatorStateHashMismatch
+	}
+
 	return nil
 }
+
+var (
+	ErrBlockValidatorEmptyBody                 = errors.New("block validator empty body")
+	ErrBlockValidatorInvalidSignature          = errors.New("block validator invalid signature")
+	ErrBlockValidatorUnknownAuthority          = errors.New("block validator unknown authority")
+	ErrBlockValidatorPreviousBlockIdMismatch   = errors.New("block validator previous block id mismatch")
+	ErrBlockValidatorUnexpectedProposer        = errors.New("block validator unexpected proposer")
+	ErrBlockValidatorChecksumMismatch          = errors.New("block validator checksum mismatch")
+	ErrBlockValidatorEventsRootMismatch        = errors.New("block validator events root mismatch")
+	ErrBlockValidatorTimestampNotAfterPrevious = errors.New("block validator timestamp not after previous block")
+	ErrBlockValidatorTimestampInFuture         = errors.New("block validator timestamp in future")
+	ErrBlockValidatorEventIdMismatch           = errors.New("block validator event id mismatch")
+	ErrBlockValidatorEventDuplicated           = errors.New("block validator event duplicated")
+	ErrBlockValidatorEventAlreadyStored        = errors.New("block validator event already stored")
+	ErrBlockValidatorEventReplayed             = errors.New("block validator event replayed")
+	ErrBlockValidatorEventsNotOrdered          = errors.New("block validator events not ordered")
+	ErrBlockValidatorTooManyEvents             = errors.New("block validator too many events")
+	ErrBlockValidatorEventsTooLarge            = errors.New("block validator events too large")
+	ErrBlockValidatorInvalidEvent              = errors.New("block validator invalid event")
+	ErrBlockValidatorInvalidState              = errors.New("block validator invalid state")
+	ErrBlockValidatorStateHashMismatch         = errors.New("block validator state hash mismatch")
+)
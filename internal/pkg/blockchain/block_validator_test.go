@@ -0,0 +1,323 @@
+package blockchain
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"github.com/dominati-one/backend/internal/pkg/security"
+	"github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"testing"
+	"time"
+)
+
+type testEventStorage struct {
+	events   map[EventId]*blockchain.Event
+	blockIds map[EventId]BlockId
+}
+
+func newTestEventStorage() *testEventStorage {
+	return &testEventStorage{
+		events:   map[EventId]*blockchain.Event{},
+		blockIds: map[EventId]BlockId{},
+	}
+}
+
+func (s *testEventStorage) NonceUsed(publicKey ed25519.PublicKey, nonce uint64) bool {
+	for _, event := range s.events {
+		signature, err := security.NewSignature(event.Signature)
+		if err == nil && signature.PublicKey().Equal(publicKey) && event.Body.GetNonce() == nonce {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *testEventStorage) Add(event *blockchain.Event, blockId BlockId) (EventId, error) {
+	eventId, err := NewEventId(event)
+	if err != nil {
+		return EmptyEventId, err
+	}
+
+	s.events[eventId] = event
+	s.blockIds[eventId] = blockId
+
+	return eventId, nil
+}
+
+func (s *testEventStorage) Exists(eventId EventId) bool {
+	_, exists := s.events[eventId]
+
+	return exists
+}
+
+func (s *testEventStorage) Remove(eventId EventId) {
+	delete(s.events, eventId)
+	delete(s.blockIds, eventId)
+}
+
+func (s *testEventStorage) GetBlockId(eventId EventId) (BlockId, error) {
+	blockId, exists := s.blockIds[eventId]
+	if !exists {
+		return BlockId{}, errors.New("event not found")
+	}
+
+	return blockId, nil
+}
+
+type testStateHasher struct {
+	stateHash []byte
+	err       error
+}
+
+func (h *testStateHasher) HashBlockState(ctx context.Context, block *blockchain.Block) ([]byte, error) {
+	return h.stateHash, h.err
+}
+
+func TestBlockValidator_Validate(t *testing.T) {
+	authorityKey := testPrivateKey(t)
+	otherKey := testPrivateKey(t)
+
+	blockValidator := NewBlockValidator(NewEventValidator(nil, NetworkSettings{}), newTestEventStorage(), nil, NetworkSettings{
+		AuthorityPublicKeys: security.NewPublicKeysBag([]crypto.PublicKey{authorityKey.PublicKey()}),
+	})
+
+	genesisBlock := testGenesisBlock()
+
+	block := buildTestBlock(t, genesisBlock, []*blockchain.Event{}, authorityKey)
+	assert.NoError(t, blockValidator.Validate(context.TODO(), genesisBlock, block))
+
+	block = buildTestBlock(t, genesisBlock, []*blockchain.Event{}, otherKey)
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorUnknownAuthority)
+
+	block = buildTestBlock(t, genesisBlock, []*blockchain.Event{}, authorityKey)
+	block.Body.Timestamp++
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorInvalidSignature)
+
+	block.Signature = nil
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorInvalidSignature)
+
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, &blockchain.Block{}), ErrBlockValidatorEmptyBody)
+}
+
+func TestBlockValidator_ValidateIntegrity(t *testing.T) {
+	authorityKey := testPrivateKey(t)
+
+	blockValidator := NewBlockValidator(NewEventValidator(nil, NetworkSettings{}), nil, nil, NetworkSettings{
+		AuthorityPublicKeys: security.NewPublicKeysBag([]crypto.PublicKey{authorityKey.PublicKey()}),
+	})
+
+	// Previous block is not known, so its id is not checked.
+	otherGenesisBlock := testGenesisBlock()
+	otherGenesisBlock.Body.Timestamp--
+
+	event := createSignedEvent(t, &blockchain.EventCreatePlanet{})
+
+	block := buildTestBlock(t, otherGenesisBlock, []*blockchain.Event{event}, authorityKey)
+	assert.NoError(t, blockValidator.ValidateIntegrity(block))
+
+	block = buildTestBlock(t, otherGenesisBlock, []*blockchain.Event{event}, testPrivateKey(t))
+	assert.ErrorIs(t, blockValidator.ValidateIntegrity(block), ErrBlockValidatorUnknownAuthority)
+
+	block = buildTestBlock(t, otherGenesisBlock, []*blockchain.Event{event}, authorityKey)
+	block.Checksum = []byte{0x00}
+	assert.ErrorIs(t, blockValidator.ValidateIntegrity(block), ErrBlockValidatorChecksumMismatch)
+
+	unsignedEvent := proto.Clone(event).(*blockchain.Event)
+	unsignedEvent.Signature = nil
+	block = buildTestBlock(t, otherGenesisBlock, []*blockchain.Event{unsignedEvent}, authorityKey)
+	assert.ErrorIs(t, blockValidator.ValidateIntegrity(block), ErrBlockValidatorInvalidEvent)
+
+	block = buildTestBlock(t, otherGenesisBlock, []*blockchain.Event{event}, authorityKey)
+	block.Body.EventsRoot = NewEventsRoot(nil)
+	sealTestBlock(t, block, authorityKey)
+	assert.ErrorIs(t, blockValidator.ValidateIntegrity(block), ErrBlockValidatorEventsRootMismatch)
+
+	assert.ErrorIs(t, blockValidator.ValidateIntegrity(&blockchain.Block{}), ErrBlockValidatorEmptyBody)
+}
+
+func TestBlockValidator_ValidateStructure(t *testing.T) {
+	authorityKey := testPrivateKey(t)
+	eventStorage := newTestEventStorage()
+
+	blockValidator := NewBlockValidator(NewEventValidator(eventStorage, NetworkSettings{}), eventStorage, nil, NetworkSettings{
+		AuthorityPublicKeys: security.NewPublicKeysBag([]crypto.PublicKey{authorityKey.PublicKey()}),
+	})
+
+	genesisBlock := testGenesisBlock()
+	otherGenesisBlock := testGenesisBlock()
+	otherGenesisBlock.Body.Timestamp--
+
+	block := buildTestBlock(t, otherGenesisBlock, []*blockchain.Event{}, authorityKey)
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorPreviousBlockIdMismatch)
+
+	block = buildTestBlock(t, genesisBlock, []*blockchain.Event{}, authorityKey)
+	block.Checksum = []byte{0x00}
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorChecksumMismatch)
+
+	block = buildTestBlock(t, genesisBlock, []*blockchain.Event{}, authorityKey)
+	block.Body.Timestamp = genesisBlock.Body.Timestamp
+	sealTestBlock(t, block, authorityKey)
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorTimestampNotAfterPrevious)
+
+	block.Body.Timestamp = CreateBlockTimestampFromNow().Add(time.Hour).UnixMilliseconds()
+	sealTestBlock(t, block, authorityKey)
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorTimestampInFuture)
+
+	event := createSignedEvent(t, &blockchain.EventCreatePlanet{})
+
+	block = buildTestBlock(t, genesisBlock, []*blockchain.Event{event}, authorityKey)
+	assert.NoError(t, blockValidator.Validate(context.TODO(), genesisBlock, block))
+
+	block.Body.Events[0].Id = EmptyEventId.Bytes()
+	sealTestBlock(t, block, authorityKey)
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorEventIdMismatch)
+
+	block = buildTestBlock(t, genesisBlock, []*blockchain.Event{event}, authorityKey)
+	block.Body.EventsRoot = NewEventsRoot(nil)
+	sealTestBlock(t, block, authorityKey)
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorEventsRootMismatch)
+
+	block = assembleTestBlock(t, genesisBlock, []*blockchain.Event{event, event}, authorityKey)
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorEventDuplicated)
+
+	unsignedEvent := proto.Clone(event).(*blockchain.Event)
+	unsignedEvent.Signature = nil
+	block = buildTestBlock(t, genesisBlock, []*blockchain.Event{unsignedEvent}, authorityKey)
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorInvalidEvent)
+
+	expiredEvent := createSignedEventAt(t, testPrivateKey(t), &blockchain.EventCreatePlanet{}, CreateBlockTimestampFromUnixMilliseconds(genesisBlock.Body.Timestamp))
+	block = buildTestBlock(t, genesisBlock, []*blockchain.Event{expiredEvent}, authorityKey)
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorInvalidEvent)
+
+	eventKey := testPrivateKey(t)
+	event = createSignedEventWithKey(t, eventKey, &blockchain.EventCreatePlanet{})
+	replayedEvent := proto.Clone(event).(*blockchain.Event)
+	replayedEvent.Body.GetCreatePlanet().Seed = 1
+	signTestEvent(t, eventKey, replayedEvent)
+	block = assembleTestBlock(t, genesisBlock, []*blockchain.Event{event, replayedEvent}, authorityKey)
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorEventReplayed)
+
+	_, err := eventStorage.Add(event, BlockId{})
+	assert.NoError(t, err)
+	block = buildTestBlock(t, genesisBlock, []*blockchain.Event{event}, authorityKey)
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorEventAlreadyStored)
+
+	block = buildTestBlock(t, genesisBlock, []*blockchain.Event{replayedEvent}, authorityKey)
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorEventReplayed)
+}
+
+func TestBlockValidator_ValidateEventLimits(t *testing.T) {
+	authorityKey := testPrivateKey(t)
+	settings := NetworkSettings{
+		AuthorityPublicKeys: security.NewPublicKeysBag([]crypto.PublicKey{authorityKey.PublicKey()}),
+	}
+
+	genesisBlock := testGenesisBlock()
+	now := CreateBlockTimestampFromNow()
+	events := []*blockchain.Event{
+		createSignedEventAt(t, testPrivateKey(t), &blockchain.EventCreatePlanet{}, now.Add(-time.Second)),
+		createSignedEventAt(t, testPrivateKey(t), &blockchain.EventCreatePlanet{}, now),
+	}
+
+	block := buildTestBlock(t, genesisBlock, events, authorityKey)
+	blockValidator := NewBlockValidator(NewEventValidator(nil, settings), newTestEventStorage(), nil, settings)
+	assert.NoError(t, blockValidator.Validate(context.TODO(), genesisBlock, block))
+
+	settings.BlockMaxEvents = 1
+	blockValidator = NewBlockValidator(NewEventValidator(nil, settings), newTestEventStorage(), nil, settings)
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorTooManyEvents)
+
+	settings.BlockMaxEvents = 0
+	settings.BlockMaxEventsSize = proto.Size(block.Body.Events[0])
+	blockValidator = NewBlockValidator(NewEventValidator(nil, settings), newTestEventStorage(), nil, settings)
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorEventsTooLarge)
+
+	settings.BlockMaxEventsSize = 0
+	blockValidator = NewBlockValidator(NewEventValidator(nil, settings), newTestEventStorage(), nil, settings)
+	block.Body.Events[0], block.Body.Events[1] = block.Body.Events[1], block.Body.Events[0]
+	sealTestBlock(t, block, authorityKey)
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorEventsNotOrdered)
+}
+
+func TestBlockValidator_ValidateStateHash(t *testing.T) {
+	authorityKey := testPrivateKey(t)
+	stateHasher := &testStateHasher{stateHash: []byte{0x01}}
+
+	blockValidator := NewBlockValidator(NewEventValidator(nil, NetworkSettings{}), newTestEventStorage(), stateHasher, NetworkSettings{
+		AuthorityPublicKeys: security.NewPublicKeysBag([]crypto.PublicKey{authorityKey.PublicKey()}),
+	})
+
+	genesisBlock := testGenesisBlock()
+
+	block, err := NewBlockBuilder(genesisBlock, []*blockchain.Event{}, nil, stateHasher, authorityKey, NetworkSettings{}).Build(context.TODO(), CreateBlockTimestampFromNow())
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x01}, block.Body.StateHash)
+	assert.NoError(t, blockValidator.Validate(context.TODO(), genesisBlock, block))
+
+	stateHasher.stateHash = []byte{0x02}
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorStateHashMismatch)
+
+	stateHasher.err = ErrBlockValidatorInvalidEvent
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorInvalidState)
+}
+
+func testGenesisBlock() *blockchain.Block {
+	return &blockchain.Block{
+		Body: &blockchain.Block_Body{
+			PreviousBlockId: []byte{0x00},
+			Timestamp:       CreateBlockTimestampFromNow().Add(-time.Hour).UnixMilliseconds(),
+			Events:          []*blockchain.Block_Body_BlockEvent{},
+		},
+		Checksum: []byte{0x00},
+	}
+}
+
+func buildTestBlock(t *testing.T, previousBlock *blockchain.Block, events []*blockchain.Event, privateKey *security.PrivateKey) *blockchain.Block {
+	block, err := NewBlockBuilder(previousBlock, events, nil, nil, privateKey, NetworkSettings{}).Build(context.TODO(), CreateBlockTimestampFromNow())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return block
+}
+
+// assembleTestBlock creates block with all given events, including those, which block builder would drop.
+func assembleTestBlock(t *testing.T, previousBlock *blockchain.Block, events []*blockchain.Event, privateKey *security.PrivateKey) *blockchain.Block {
+	block := buildTestBlock(t, previousBlock, nil, privateKey)
+
+	for _, event := range events {
+		block.Body.Events = append(block.Body.Events, &blockchain.Block_Body_BlockEvent{Id: MustEventId(event).Bytes(), Event: event})
+	}
+	sort.SliceStable(block.Body.Events, func(i, j int) bool {
+		return blockEventLess(block.Body.Events[i], block.Body.Events[j])
+	})
+	block.Body.EventsRoot = NewBlockEventsRoot(block.Body)
+	sealTestBlock(t, block, privateKey)
+
+	return block
+}
+
+// sealTestBlock recalculates checksum and signature after block body modification.
+func sealTestBlock(t *testing.T, block *blockchain.Block, privateKey *security.PrivateKey) {
+	blockHeader := NewBlockHeader(block.Body)
+
+	blockHeaderBytes, err := proto.Marshal(blockHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockHeaderHash := sha256.Sum256(blockHeaderBytes)
+
+	signature, err := security.CreateSignatureFromBlockBody(blockHeader, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block.Checksum = blockHeaderHash[:]
+	block.Signature = signature.Bytes()
+}
@@ -0,0 +1,147 @@
+package blockchain
+
+import (
+	"context"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+const (
+	DefaultChainSyncBatchSize = 100
+	chainSyncRequestTimeout   = 10 * time.Second
+)
+
+// ChainSynchronizer downloads blocks from peer with highest tip and processes them in order, until node reaches that
+// tip. When peer is on another branch, blocks are downloaded again from lower heights, until common ancestor is found.
+type ChainSynchronizer struct {
+	log          zerolog.Logger
+	connector    SyncConnector
+	blockStorage BlockStorage
+	processBlock func(ctx context.Context, block *blockchainProtocol.Block) error
+	batchSize    int
+}
+
+// NewChainSynchronizer creates synchronizer. Process block function returns ErrBlockReceiverUnknownParent, when
+// parent of block is neither in main chain nor in side branch.
+func NewChainSynchronizer(connector SyncConnector, blockStorage BlockStorage, processBlock func(ctx context.Context, block *blockchainProtocol.Block) error) *ChainSynchronizer {
+	return &ChainSynchronizer{
+		log:          log.With().Str("applicationComponent", "blockchain").Str("blockchainComponent", "chainSynchronizer").Logger(),
+		connector:    connector,
+		blockStorage: blockStorage,
+		processBlock: processBlock,
+		batchSize:    DefaultChainSyncBatchSize,
+	}
+}
+
+// Sync downloads missing blocks. Tips are asked again after each round, because peers keep producing blocks while
+// node is catching up.
+func (s *ChainSynchronizer) Sync(ctx context.Context) error {
+	startTime := time.Now()
+	startHeight := s.blockStorage.Count() - 1
+
+	for {
+		if ctx.Err() != nil {
+			return ErrCanceledChainSync
+		}
+
+		localHeight := s.blockStorage.Count() - 1
+
+		tip, err := s.bestTip(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to get peer tips")
+		}
+
+		if tip == nil || tip.Height <= localHeight {
+			s.log.Info().
+				Int("height", localHeight).
+				Int("syncedBlocksCount", localHeight-startHeight).
+				Dur("syncDuration", time.Since(startTime)).
+				Msg("Chain synchronized.")
+			return nil
+		}
+
+		s.log.Info().
+			Str("peer", tip.Peer).
+			Int("height", localHeight).
+			Int("tipHeight", tip.Height).
+			Msg("Chain synchronization in progress.")
+
+		if err := s.syncRange(ctx, tip.Peer, localHeight+1, tip.Height); err != nil {
+			return errors.Wrapf(err, "unable to sync blocks from peer %s", tip.Peer)
+		}
+	}
+}
+
+func (s *ChainSynchronizer) bestTip(ctx context.Context) (*PeerTip, error) {
+	requestCtx, cancel := context.WithTimeout(ctx, chainSyncRequestTimeout)
+	defer cancel()
+
+	tips, err := s.connector.GetPeerTips(requestCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	var bestTip *PeerTip
+	for index := range tips {
+		if bestTip == nil || tips[index].Height > bestTip.Height {
+			bestTip = &tips[index]
+		}
+	}
+
+	return bestTip, nil
+}
+
+func (s *ChainSynchronizer) syncRange(ctx context.Context, peer string, fromHeight int, toHeight int) error {
+	for height := fromHeight; height <= toHeight; {
+		count := toHeight - height + 1
+		if count > s.batchSize {
+			count = s.batchSize
+		}
+
+		requestCtx, cancel := context.WithTimeout(ctx, chainSyncRequestTimeout)
+		blocks, err := s.connector.GetBlocks(requestCtx, peer, height, count)
+		cancel()
+		if err != nil {
+			return errors.Wrapf(err, "unable to download blocks from height %d", height)
+		}
+
+		if len(blocks) == 0 {
+			return errors.Wrapf(ErrChainSyncNoBlocks, "from height %d", height)
+		}
+
+		batchHeight := height
+
+		for _, block := range blocks {
+			err := s.processBlock(ctx, block)
+
+			if errors.Cause(err) == ErrBlockReceiverUnknownParent && batchHeight > 1 {
+				height = batchHeight - s.batchSize
+				if height < 1 {
+					height = 1
+				}
+
+				s.log.Debug().Int("height", height).Msg("Peer is on another branch. Looking for common ancestor.")
+				break
+			}
+
+			if err != nil {
+				return errors.Wrapf(err, "unable to process block at height %d", height)
+			}
+			height++
+		}
+
+		if height > batchHeight {
+			s.log.Debug().Int("height", height-1).Int("tipHeight", toHeight).Msg("Blocks range synchronized.")
+		}
+	}
+
+	return nil
+}
+
+var (
+	ErrCanceledChainSync = errors.New("canceled chain sync")
+	ErrChainSyncNoBlocks = errors.New("chain sync no blocks")
+)
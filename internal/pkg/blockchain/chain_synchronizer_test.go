@@ -0,0 +1,114 @@
+package blockchain
+
+import (
+	"context"
+	"github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testSyncConnector struct {
+	peers map[string][]*blockchain.Block
+}
+
+func (c *testSyncConnector) GetPeerTips(ctx context.Context) ([]PeerTip, error) {
+	tips := []PeerTip{}
+	for peer, blocks := range c.peers {
+		tips = append(tips, PeerTip{Peer: peer, Height: len(blocks) - 1})
+	}
+
+	return tips, nil
+}
+
+func (c *testSyncConnector) GetBlocks(ctx context.Context, peer string, fromHeight int, count int) ([]*blockchain.Block, error) {
+	blocks := c.peers[peer]
+	if fromHeight+count > len(blocks) {
+		count = len(blocks) - fromHeight
+	}
+
+	return blocks[fromHeight : fromHeight+count], nil
+}
+
+type testChainBlockStorage struct {
+	blocks []*blockchain.Block
+}
+
+func (s *testChainBlockStorage) Count() int {
+	return len(s.blocks)
+}
+
+func (s *testChainBlockStorage) Add(block *blockchain.Block) (*BlockId, error) {
+	s.blocks = append(s.blocks, block)
+
+	return NewBlockId(block)
+}
+
+func (s *testChainBlockStorage) Get(blockId BlockId) (*blockchain.Block, error) {
+	height, err := s.GetHeight(blockId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.blocks[height], nil
+}
+
+func (s *testChainBlockStorage) GetByHeight(height int) (*blockchain.Block, error) {
+	return s.blocks[height], nil
+}
+
+func (s *testChainBlockStorage) GetLatestBlock() (*blockchain.Block, error) {
+	return s.blocks[len(s.blocks)-1], nil
+}
+
+func (s *testChainBlockStorage) GetHeight(blockId BlockId) (int, error) {
+	for height, block := range s.blocks {
+		if id, _ := NewBlockId(block); *id == blockId {
+			return height, nil
+		}
+	}
+
+	return 0, ErrBlockReceiverUnknownParent
+}
+
+func (s *testChainBlockStorage) Exists(blockId BlockId) bool {
+	_, err := s.GetHeight(blockId)
+
+	return err == nil
+}
+
+func (s *testChainBlockStorage) Truncate(height int) error {
+	s.blocks = s.blocks[:height+1]
+
+	return nil
+}
+
+func TestChainSynchronizer_Sync(t *testing.T) {
+	chain := []*blockchain.Block{testGenesisBlock()}
+	for len(chain) < 8 {
+		chain = append(chain, &blockchain.Block{Body: &blockchain.Block_Body{Timestamp: uint64(len(chain))}})
+	}
+
+	connector := &testSyncConnector{peers: map[string][]*blockchain.Block{
+		"behind":  chain[:3],
+		"longest": chain,
+	}}
+	blockStorage := &testChainBlockStorage{blocks: chain[:2]}
+
+	synchronizer := NewChainSynchronizer(connector, blockStorage, func(ctx context.Context, block *blockchain.Block) error {
+		_, err := blockStorage.Add(block)
+		return err
+	})
+	synchronizer.batchSize = 4
+
+	assert.NoError(t, synchronizer.Sync(context.TODO()))
+	assert.Equal(t, len(chain), blockStorage.Count())
+
+	for height, block := range chain {
+		assert.True(t, proto.Equal(block, blockStorage.blocks[height]))
+	}
+
+	canceledCtx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	assert.ErrorIs(t, synchronizer.Sync(canceledCtx), ErrCanceledChainSync)
+}
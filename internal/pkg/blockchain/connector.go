@@ -6,11 +6,11 @@ import (
 )
 
 type Connector interface {
-	// SendEventToBacklog send event to network backlog
-	SendEventToBacklog(backlogEvent *blockchain.Event) error
+	// SendEventToBacklog send event to network backlog, waits while backlog is full until context is done
+	SendEventToBacklog(ctx context.Context, backlogEvent *blockchain.Event) error
 
-	// SendBlockToBacklog send block proposal to network backlog
-	SendBlockToBacklog(backlogBlock *blockchain.Block) error
+	// SendBlockToBacklog send block proposal to network backlog, waits while backlog is full until context is done
+	SendBlockToBacklog(ctx context.Context, backlogBlock *blockchain.Block) error
 
 	GetBacklogBlock(ctx context.Context) (*blockchain.Block, error)
 	GetBacklogEvent(ctx context.Context) (*blockchain.Event, error)
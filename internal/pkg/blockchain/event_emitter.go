@@ -7,20 +7,82 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"sync"
+	"sync/atomic"
 )
 
+const (
+	DefaultEventEmitterBufferSize = 100
+)
+
+// EventEmitterPolicy decides, what happens with message for subscriber, whose buffer is full.
+type EventEmitterPolicy int
+
+const (
+	// EventEmitterPolicyBlock makes emitter wait, until subscriber reads message. Slow subscriber stalls all others.
+	EventEmitterPolicyBlock EventEmitterPolicy = iota
+	// EventEmitterPolicyDrop skips message for subscriber. Dropped messages are counted in subscription.
+	EventEmitterPolicyDrop
+	// EventEmitterPolicyDisconnect closes subscription, so subscriber never sees incomplete sequence of messages.
+	EventEmitterPolicyDisconnect
+)
+
+type EventEmitterSubscriptionSettings struct {
+	Name       string
+	BufferSize int
+	Policy     EventEmitterPolicy
+	// BlocksOnly subscription gets blocks only, events are read from block body.
+	BlocksOnly bool
+}
+
+func (s EventEmitterSubscriptionSettings) bufferSize() int {
+	if s.BufferSize <= 0 {
+		return DefaultEventEmitterBufferSize
+	}
+
+	return s.BufferSize
+}
+
+// EventEmitterMessage carries either block or one of its events. Block is always emitted before its events.
+type EventEmitterMessage struct {
+	Block *blockchainProtocol.Block
+	Event *blockchainProtocol.Event
+}
+
+// EventEmitter fans accepted blocks and their events out to subscribers. Every subscriber gets messages in order of
+// emission into its own buffer.
 type EventEmitter struct {
-	log         zerolog.Logger
-	eventsQueue chan *blockchainProtocol.Event
-	blocksQueue chan *blockchainProtocol.Block
+	log zerolog.Logger
+
+	state         sync.Mutex
+	subscriptions map[*EventEmitterSubscription]struct{}
 }
 
 func NewEventEmitter() *EventEmitter {
 	return &EventEmitter{
-		log:         log.With().Str("applicationComponent", "blockchain").Str("blockchainComponent", "eventEmitter").Logger(),
-		eventsQueue: make(chan *blockchainProtocol.Event, 0),
-		blocksQueue: make(chan *blockchainProtocol.Block, 0),
+		log:           log.With().Str("applicationComponent", "blockchain").Str("blockchainComponent", "eventEmitter").Logger(),
+		subscriptions: map[*EventEmitterSubscription]struct{}{},
+	}
+}
+
+// Subscribe creates subscription receiving every block and event emitted from now on.
+func (e *EventEmitter) Subscribe(settings EventEmitterSubscriptionSettings) *EventEmitterSubscription {
+	defer e.state.Unlock()
+	e.state.Lock()
+
+	subscription := &EventEmitterSubscription{
+		log:        e.log.With().Str("subscription", settings.Name).Logger(),
+		emitter:    e,
+		policy:     settings.Policy,
+		blocksOnly: settings.BlocksOnly,
+		messages:   make(chan *EventEmitterMessage, settings.bufferSize()),
+		closed:     make(chan struct{}),
 	}
+	e.subscriptions[subscription] = struct{}{}
+
+	subscription.log.Debug().Msg("Subscribed.")
+
+	return subscription
 }
 
 func (e *EventEmitter) emitEvent(event *blockchainProtocol.Event) error {
@@ -32,9 +94,15 @@ func (e *EventEmitter) emitEvent(event *blockchainProtocol.Event) error {
 	}
 	log = log.With().Str("eventId", eventId.String()).Logger()
 
-	e.eventsQueue <- proto.Clone(event).(*blockchainProtocol.Event)
+	for _, subscription := range e.activeSubscriptions() {
+		if subscription.blocksOnly {
+			continue
+		}
+
+		subscription.deliver(&EventEmitterMessage{Event: proto.Clone(event).(*blockchainProtocol.Event)})
+	}
 
-	log.Trace().Msg("Event added to queue.")
+	log.Trace().Msg("Event emitted.")
 
 	return nil
 }
@@ -48,31 +116,121 @@ func (e *EventEmitter) emitBlock(block *blockchainProtocol.Block) error {
 	}
 	log = log.With().Str("blockId", blockId.String()).Logger()
 
-	e.blocksQueue <- proto.Clone(block).(*blockchainProtocol.Block)
+	for _, subscription := range e.activeSubscriptions() {
+		subscription.deliver(&EventEmitterMessage{Block: proto.Clone(block).(*blockchainProtocol.Block)})
+	}
 
-	log.Trace().Msg("Block added to queue.")
+	log.Trace().Msg("Block emitted.")
 
 	return nil
 }
 
-func (e *EventEmitter) WaitForEvent(ctx context.Context) (*blockchainProtocol.Event, error) {
-	select {
-	case <-ctx.Done():
-		return nil, ErrCanceledEventEmitterWait
-	case event := <-e.eventsQueue:
-		return event, nil
+func (e *EventEmitter) activeSubscriptions() []*EventEmitterSubscription {
+	defer e.state.Unlock()
+	e.state.Lock()
+
+	subscriptions := make([]*EventEmitterSubscription, 0, len(e.subscriptions))
+	for subscription := range e.subscriptions {
+		subscriptions = append(subscriptions, subscription)
 	}
+
+	return subscriptions
+}
+
+func (e *EventEmitter) unsubscribe(subscription *EventEmitterSubscription) {
+	defer e.state.Unlock()
+	e.state.Lock()
+
+	delete(e.subscriptions, subscription)
 }
 
-func (e *EventEmitter) WaitForBlock(ctx context.Context) (*blockchainProtocol.Block, error) {
+type EventEmitterSubscription struct {
+	log        zerolog.Logger
+	emitter    *EventEmitter
+	policy     EventEmitterPolicy
+	blocksOnly bool
+	messages   chan *EventEmitterMessage
+	closed     chan struct{}
+	close      sync.Once
+	dropped    uint64
+}
+
+// Wait returns next message. Messages buffered before subscription was closed are still returned, then
+// ErrEventEmitterSubscriptionClosed is returned.
+func (s *EventEmitterSubscription) Wait(ctx context.Context) (*EventEmitterMessage, error) {
+	select {
+	case message := <-s.messages:
+		return message, nil
+	default:
+	}
+
 	select {
 	case <-ctx.Done():
 		return nil, ErrCanceledEventEmitterWait
-	case block := <-e.blocksQueue:
-		return block, nil
+	case message := <-s.messages:
+		return message, nil
+	case <-s.closed:
+		select {
+		case message := <-s.messages:
+			return message, nil
+		default:
+			return nil, ErrEventEmitterSubscriptionClosed
+		}
+	}
+}
+
+// Unsubscribe stops delivery of messages to subscription.
+func (s *EventEmitterSubscription) Unsubscribe() {
+	s.close.Do(func() {
+		s.emitter.unsubscribe(s)
+		close(s.closed)
+
+		s.log.Debug().Msg("Unsubscribed.")
+	})
+}
+
+// Messages returns channel of delivered messages, so subscription can be waited for together with other channels.
+func (s *EventEmitterSubscription) Messages() <-chan *EventEmitterMessage {
+	return s.messages
+}
+
+// Closed is closed, when subscription is unsubscribed or disconnected by emitter.
+func (s *EventEmitterSubscription) Closed() <-chan struct{} {
+	return s.closed
+}
+
+// Dropped returns number of messages dropped, because buffer of subscription was full.
+func (s *EventEmitterSubscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+func (s *EventEmitterSubscription) deliver(message *EventEmitterMessage) {
+	switch s.policy {
+	case EventEmitterPolicyDrop:
+		select {
+		case s.messages <- message:
+		case <-s.closed:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+			s.log.Warn().Msg("Subscription buffer is full. Message dropped.")
+		}
+	case EventEmitterPolicyDisconnect:
+		select {
+		case s.messages <- message:
+		case <-s.closed:
+		default:
+			s.log.Warn().Msg("Subscription buffer is full. Disconnecting subscription.")
+			s.Unsubscribe()
+		}
+	default:
+		select {
+		case s.messages <- message:
+		case <-s.closed:
+		}
 	}
 }
 
 var (
-	ErrCanceledEventEmitterWait = errors.New("canceled event emitter wait")
+	ErrCanceledEventEmitterWait       = errors.New("canceled event emitter wait")
+	ErrEventEmitterSubscriptionClosed = errors.New("event emitter subscription closed")
 )
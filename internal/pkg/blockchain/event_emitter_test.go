@@ -0,0 +1,88 @@
+package blockchain
+
+import (
+	"context"
+	"github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEventEmitter_Subscribe(t *testing.T) {
+	eventEmitter := NewEventEmitter()
+	firstSubscription := eventEmitter.Subscribe(EventEmitterSubscriptionSettings{Name: "first"})
+	secondSubscription := eventEmitter.Subscribe(EventEmitterSubscriptionSettings{Name: "second"})
+
+	block := testGenesisBlock()
+	event := createSignedEvent(t, &blockchain.EventCreatePlanet{})
+
+	assert.NoError(t, eventEmitter.emitBlock(block))
+	assert.NoError(t, eventEmitter.emitEvent(event))
+
+	for _, subscription := range []*EventEmitterSubscription{firstSubscription, secondSubscription} {
+		message, err := subscription.Wait(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, block.Body.Timestamp, message.Block.Body.Timestamp)
+		assert.Nil(t, message.Event)
+
+		message, err = subscription.Wait(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, event.Signature, message.Event.Signature)
+		assert.Nil(t, message.Block)
+	}
+
+	secondSubscription.Unsubscribe()
+	assert.NoError(t, eventEmitter.emitBlock(block))
+
+	_, err := firstSubscription.Wait(context.TODO())
+	assert.NoError(t, err)
+
+	_, err = secondSubscription.Wait(context.TODO())
+	assert.ErrorIs(t, err, ErrEventEmitterSubscriptionClosed)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	_, err = firstSubscription.Wait(ctx)
+	assert.ErrorIs(t, err, ErrCanceledEventEmitterWait)
+}
+
+func TestEventEmitter_SlowSubscriberPolicy(t *testing.T) {
+	eventEmitter := NewEventEmitter()
+	block := testGenesisBlock()
+
+	dropSubscription := eventEmitter.Subscribe(EventEmitterSubscriptionSettings{BufferSize: 1, Policy: EventEmitterPolicyDrop})
+	disconnectSubscription := eventEmitter.Subscribe(EventEmitterSubscriptionSettings{BufferSize: 1, Policy: EventEmitterPolicyDisconnect})
+
+	assert.NoError(t, eventEmitter.emitBlock(block))
+	assert.NoError(t, eventEmitter.emitBlock(block))
+
+	assert.EqualValues(t, 1, dropSubscription.Dropped())
+	_, err := dropSubscription.Wait(context.TODO())
+	assert.NoError(t, err)
+
+	<-disconnectSubscription.Closed()
+	_, err = disconnectSubscription.Wait(context.TODO())
+	assert.NoError(t, err)
+	_, err = disconnectSubscription.Wait(context.TODO())
+	assert.ErrorIs(t, err, ErrEventEmitterSubscriptionClosed)
+
+	blockSubscription := eventEmitter.Subscribe(EventEmitterSubscriptionSettings{BufferSize: 1, Policy: EventEmitterPolicyBlock})
+	assert.NoError(t, eventEmitter.emitBlock(block))
+	_, _ = dropSubscription.Wait(context.TODO())
+
+	emitted := make(chan struct{})
+	go func() {
+		_ = eventEmitter.emitBlock(block)
+		close(emitted)
+	}()
+
+	select {
+	case <-emitted:
+		t.Fatal("emitter did not wait for subscriber with full buffer")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	_, err = blockSubscription.Wait(context.TODO())
+	assert.NoError(t, err)
+	<-emitted
+}
@@ -1,5 +1,18 @@
 package blockchain
 
+import (
+	"crypto/ed25519"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+)
+
 type EventStorage interface {
+	// Add stores event included in block with given id.
+	Add(event *blockchainProtocol.Event, blockId BlockId) (EventId, error)
 	Exists(eventId EventId) bool
+	// GetBlockId returns id of block, in which stored event is included.
+	GetBlockId(eventId EventId) (BlockId, error)
+	// NonceUsed checks if stored event of given signer uses given nonce.
+	NonceUsed(publicKey ed25519.PublicKey, nonce uint64) bool
+	// Remove deletes event, so it can be included in another block after reorganization of chain.
+	Remove(eventId EventId)
 }
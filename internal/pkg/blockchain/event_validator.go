@@ -1,14 +1,145 @@
 package blockchain
 
-import "github.com/dominati-one/backend/pkg/protocol/blockchain"
+import (
+	"github.com/dominati-one/backend/internal/pkg/security"
+	"github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	DefaultEventMaxValidity = 10 * time.Minute
+)
+
+// eventNonce identifies nonce of signer, so events reusing it can be detected.
+type eventNonce struct {
+	publicKey string
+	nonce     uint64
+}
+
+func newEventNonce(event *blockchain.Event) (eventNonce, error) {
+	signature, err := security.NewSignature(event.Signature)
+	if err != nil {
+		return eventNonce{}, err
+	}
+
+	return eventNonce{publicKey: string(signature.PublicKey()), nonce: event.Body.GetNonce()}, nil
+}
 
 type EventValidator struct {
+	eventStorage  EventStorage
+	maxValidity   time.Duration
+	maxClockDrift time.Duration
 }
 
-func NewEventValidator() *EventValidator {
-	return &EventValidator{}
+// NewEventValidator creates validator. Replayed events are not detected, when event storage is nil.
+func NewEventValidator(eventStorage EventStorage, settings NetworkSettings) *EventValidator {
+	maxClockDrift := settings.BlockMaxClockDrift
+	if maxClockDrift == 0 {
+		maxClockDrift = DefaultBlockMaxClockDrift
+	}
+
+	return &EventValidator{
+		eventStorage:  eventStorage,
+		maxValidity:   settings.eventMaxValidity(),
+		maxClockDrift: maxClockDrift,
+	}
 }
 
+// Validate checks if event can be included in block now and signer did not use its nonce in stored event.
 func (v *EventValidator) Validate(event *blockchain.Event) error {
+	if err := v.ValidateAt(event, CreateBlockTimestampFromNow()); err != nil {
+		return err
+	}
+
+	return v.ValidateNotReplayed(event)
+}
+
+// ValidateAt checks event and its validity window against given time, which is block timestamp for events in block.
+func (v *EventValidator) ValidateAt(event *blockchain.Event, timestamp BlockTimestamp) error {
+	if event.Body == nil {
+		return ErrEventValidatorEmptyBody
+	}
+
+	if event.Body.Event == nil {
+		return ErrEventValidatorUnsupportedEvent
+	}
+
+	signature, err := security.NewSignature(event.Signature)
+	if err != nil {
+		return ErrEventValidatorInvalidSignature
+	}
+
+	if err := signature.Verify(event.Body); err != nil {
+		return ErrEventValidatorInvalidSignature
+	}
+
+	if err := v.validateValidity(event.Body, timestamp); err != nil {
+		return err
+	}
+
+	if createPlayerEvent := event.Body.GetCreatePlayer(); createPlayerEvent != nil {
+		publicKey, err := security.NewPublicKey(createPlayerEvent.PublicKey)
+		if err != nil {
+			return ErrEventValidatorInvalidPublicKey
+		}
+
+		if !publicKey.Equal(signature.PublicKey()) {
+			return ErrEventValidatorSignerMismatch
+		}
+	}
+
+	return nil
+}
+
+// ValidateNotReplayed checks if signer did not use nonce of event in any stored event.
+func (v *EventValidator) ValidateNotReplayed(event *blockchain.Event) error {
+	if v.eventStorage == nil {
+		return nil
+	}
+
+	signature, err := security.NewSignature(event.Signature)
+	if err != nil {
+		return ErrEventValidatorInvalidSignature
+	}
+
+	if v.eventStorage.NonceUsed(signature.PublicKey(), event.Body.GetNonce()) {
+		return ErrEventValidatorReplayedEvent
+	}
+
 	return nil
 }
+
+// validateValidity checks validity window of event. Window must not be longer than max validity, so signed event can
+// not be included in block long after it was signed, and start of window may be ahead of timestamp by clock drift at
+// most. Nonces are remembered as long as their events are stored, so expiry of window does not allow replay.
+func (v *EventValidator) validateValidity(eventBody *blockchain.Event_Body, timestamp BlockTimestamp) error {
+	validFrom := CreateBlockTimestampFromUnixMilliseconds(eventBody.ValidFrom)
+	validUntil := CreateBlockTimestampFromUnixMilliseconds(eventBody.ValidUntil)
+
+	if eventBody.ValidUntil <= eventBody.ValidFrom || validUntil.Sub(validFrom.Time) > v.maxValidity {
+		return ErrEventValidatorInvalidValidity
+	}
+
+	if validFrom.After(timestamp.Add(v.maxClockDrift).Time) {
+		return ErrEventValidatorFutureEvent
+	}
+
+	if timestamp.After(validUntil.Time) {
+		return ErrEventValidatorExpiredEvent
+	}
+
+	return nil
+}
+
+var (
+	ErrEventValidatorEmptyBody        = errors.New("event validator empty body")
+	ErrEventValidatorUnsupportedEvent = errors.New("event validator unsupported event")
+	ErrEventValidatorInvalidSignature = errors.New("event validator invalid signature")
+	ErrEventValidatorInvalidPublicKey = errors.New("event validator invalid public key")
+	ErrEventValidatorSignerMismatch   = errors.New("event validator signer mismatch")
+	ErrEventValidatorInvalidValidity  = errors.New("event validator invalid validity")
+	ErrEventValidatorFutureEvent      = errors.New("event validator future event")
+	ErrEventValidatorExpiredEvent     = errors.New("event validator expired event")
+	ErrEventValidatorReplayedEvent    = errors.New("event validator replayed event")
+)
@@ -0,0 +1,136 @@
+package blockchain
+
+import (
+	"github.com/dominati-one/backend/internal/pkg/security"
+	"github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEventValidator_Validate(t *testing.T) {
+	eventValidator := NewEventValidator(nil, NetworkSettings{})
+	privateKey := testPrivateKey(t)
+
+	err := eventValidator.Validate(&blockchain.Event{})
+	assert.ErrorIs(t, err, ErrEventValidatorEmptyBody)
+
+	err = eventValidator.Validate(&blockchain.Event{Body: &blockchain.Event_Body{}})
+	assert.ErrorIs(t, err, ErrEventValidatorUnsupportedEvent)
+
+	unsignedEvent := &blockchain.Event{
+		Body: &blockchain.Event_Body{
+			Event: &blockchain.Event_Body_CreatePlanet{CreatePlanet: &blockchain.EventCreatePlanet{}},
+		},
+	}
+	err = eventValidator.Validate(unsignedEvent)
+	assert.ErrorIs(t, err, ErrEventValidatorInvalidSignature)
+
+	signedEvent := createSignedEvent(t, &blockchain.EventCreatePlanet{Seed: 1})
+	err = eventValidator.Validate(signedEvent)
+	assert.NoError(t, err)
+
+	signedEvent.Body.GetCreatePlanet().Seed = 2
+	err = eventValidator.Validate(signedEvent)
+	assert.ErrorIs(t, err, ErrEventValidatorInvalidSignature)
+
+	err = eventValidator.Validate(createSignedEventWithKey(t, privateKey, &blockchain.EventCreatePlayer{
+		PublicKey: []byte{0x01},
+	}))
+	assert.ErrorIs(t, err, ErrEventValidatorInvalidPublicKey)
+
+	err = eventValidator.Validate(createSignedEventWithKey(t, privateKey, &blockchain.EventCreatePlayer{
+		PublicKey: testPrivateKey(t).PublicKey(),
+	}))
+	assert.ErrorIs(t, err, ErrEventValidatorSignerMismatch)
+
+	err = eventValidator.Validate(createSignedEventWithKey(t, privateKey, &blockchain.EventCreatePlayer{
+		PublicKey: privateKey.PublicKey(),
+	}))
+	assert.NoError(t, err)
+}
+
+var testEventNonce uint64
+
+func TestEventValidator_ValidateValidity(t *testing.T) {
+	eventStorage := newTestEventStorage()
+	eventValidator := NewEventValidator(eventStorage, NetworkSettings{})
+	privateKey := testPrivateKey(t)
+	now := CreateBlockTimestampFromNow()
+
+	event := createSignedEventAt(t, privateKey, &blockchain.EventCreatePlanet{}, now)
+	assert.NoError(t, eventValidator.ValidateAt(event, now))
+	assert.ErrorIs(t, eventValidator.ValidateAt(event, now.Add(-time.Minute)), ErrEventValidatorFutureEvent)
+	assert.ErrorIs(t, eventValidator.ValidateAt(event, now.Add(DefaultEventMaxValidity+time.Second)), ErrEventValidatorExpiredEvent)
+
+	event.Body.ValidUntil = event.Body.ValidFrom
+	assert.ErrorIs(t, eventValidator.ValidateAt(signTestEvent(t, privateKey, event), now), ErrEventValidatorInvalidValidity)
+
+	event.Body.ValidUntil = now.Add(DefaultEventMaxValidity + time.Second).UnixMilliseconds()
+	assert.ErrorIs(t, eventValidator.ValidateAt(signTestEvent(t, privateKey, event), now), ErrEventValidatorInvalidValidity)
+
+	event = createSignedEventAt(t, privateKey, &blockchain.EventCreatePlanet{}, now)
+	assert.NoError(t, eventValidator.Validate(event))
+
+	_, err := eventStorage.Add(event, BlockId{})
+	assert.NoError(t, err)
+
+	replayedEvent := proto.Clone(event).(*blockchain.Event)
+	replayedEvent.Body.GetCreatePlanet().Seed = 1
+	assert.ErrorIs(t, eventValidator.Validate(signTestEvent(t, privateKey, replayedEvent)), ErrEventValidatorReplayedEvent)
+
+	otherSignerEvent := proto.Clone(event).(*blockchain.Event)
+	assert.NoError(t, eventValidator.Validate(signTestEvent(t, testPrivateKey(t), otherSignerEvent)))
+}
+
+func signTestEvent(t *testing.T, privateKey *security.PrivateKey, event *blockchain.Event) *blockchain.Event {
+	signature, err := security.CreateSignatureFromBody(event.Body, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event.Signature = signature.Bytes()
+
+	return event
+}
+
+func testPrivateKey(t *testing.T) *security.PrivateKey {
+	privateKey, err := security.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return privateKey
+}
+
+func createSignedEvent(t *testing.T, event interface{}) *blockchain.Event {
+	return createSignedEventWithKey(t, testPrivateKey(t), event)
+}
+
+func createSignedEventWithKey(t *testing.T, privateKey *security.PrivateKey, event interface{}) *blockchain.Event {
+	return createSignedEventAt(t, privateKey, event, CreateBlockTimestampFromNow())
+}
+
+// createSignedEventAt creates event valid from given time for default max validity with unique nonce.
+func createSignedEventAt(t *testing.T, privateKey *security.PrivateKey, event interface{}, validFrom BlockTimestamp) *blockchain.Event {
+	signedEvent := &blockchain.Event{
+		Body: &blockchain.Event_Body{
+			ValidFrom:  validFrom.UnixMilliseconds(),
+			ValidUntil: validFrom.Add(DefaultEventMaxValidity).UnixMilliseconds(),
+			Nonce:      atomic.AddUint64(&testEventNonce, 1),
+		},
+		Timestamp: validFrom.UnixMilliseconds(),
+	}
+
+	switch resolvedEvent := event.(type) {
+	case *blockchain.EventCreatePlanet:
+		signedEvent.Body.Event = &blockchain.Event_Body_CreatePlanet{CreatePlanet: resolvedEvent}
+	case *blockchain.EventCreatePlayer:
+		signedEvent.Body.Event = &blockchain.Event_Body_CreatePlayer{CreatePlayer: resolvedEvent}
+	default:
+		t.Fatalf("unsupported event %T", event)
+	}
+
+	return signTestEvent(t, privateKey, signedEvent)
+}
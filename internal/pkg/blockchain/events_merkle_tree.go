@@ -0,0 +1,153 @@
+package blockchain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+const (
+	// eventsMerkleTreeLeafPrefix and eventsMerkleTreeNodePrefix separate hashes of leaves and inner nodes, so inner node
+	// can not be passed off as event id.
+	eventsMerkleTreeLeafPrefix = 0x00
+	eventsMerkleTreeNodePrefix = 0x01
+)
+
+// NewEventsRoot calculates Merkle root of event ids in given order. Node without sibling is promoted to upper level
+// unchanged. Root of empty block is hash of empty input.
+func NewEventsRoot(eventIds []EventId) []byte {
+	if len(eventIds) == 0 {
+		hash := sha256.Sum256(nil)
+		return hash[:]
+	}
+
+	level := eventsMerkleTreeLeaves(eventIds)
+	for len(level) > 1 {
+		level = eventsMerkleTreeLevel(level)
+	}
+
+	return level[0]
+}
+
+// NewBlockEventsRoot calculates Merkle root of events of block.
+func NewBlockEventsRoot(blockBody *blockchainProtocol.Block_Body) []byte {
+	return NewEventsRoot(blockEventIds(blockBody))
+}
+
+// NewEventInclusionProof creates proof, that event is included in events root of block.
+func NewEventInclusionProof(blockBody *blockchainProtocol.Block_Body, eventId EventId) (*blockchainProtocol.EventInclusionProof, error) {
+	eventIds := blockEventIds(blockBody)
+
+	index := -1
+	for eventIndex, blockEventId := range eventIds {
+		if blockEventId == eventId {
+			index = eventIndex
+		}
+	}
+
+	if index < 0 {
+		return nil, ErrEventInclusionProofEventNotInBlock
+	}
+
+	proof := &blockchainProtocol.EventInclusionProof{
+		EventId: eventId.Bytes(),
+		Steps:   []*blockchainProtocol.EventInclusionProof_Step{},
+	}
+
+	level := eventsMerkleTreeLeaves(eventIds)
+	for len(level) > 1 {
+		siblingIndex := index ^ 1
+		if siblingIndex < len(level) {
+			proof.Steps = append(proof.Steps, &blockchainProtocol.EventInclusionProof_Step{
+				Hash: level[siblingIndex],
+				Left: siblingIndex < index,
+			})
+		}
+
+		level = eventsMerkleTreeLevel(level)
+		index /= 2
+	}
+
+	return proof, nil
+}
+
+// VerifyEventInclusionProof checks, that proof leads from its event id to given events root.
+func VerifyEventInclusionProof(eventsRoot []byte, proof *blockchainProtocol.EventInclusionProof) error {
+	if len(proof.EventId) != len(EmptyEventId) {
+		return ErrEventInclusionProofInvalid
+	}
+
+	hash := eventsMerkleTreeHash(eventsMerkleTreeLeafPrefix, proof.EventId)
+
+	for _, step := range proof.Steps {
+		if step.Left {
+			hash = eventsMerkleTreeHash(eventsMerkleTreeNodePrefix, step.Hash, hash)
+		} else {
+			hash = eventsMerkleTreeHash(eventsMerkleTreeNodePrefix, hash, step.Hash)
+		}
+	}
+
+	if !bytes.Equal(hash, eventsRoot) {
+		return ErrEventInclusionProofRootMismatch
+	}
+
+	return nil
+}
+
+// NewBlockHeader returns copy of block body without events. Header is covered by block checksum and signature.
+func NewBlockHeader(blockBody *blockchainProtocol.Block_Body) *blockchainProtocol.Block_Body {
+	header := proto.Clone(blockBody).(*blockchainProtocol.Block_Body)
+	header.Events = nil
+
+	return header
+}
+
+func blockEventIds(blockBody *blockchainProtocol.Block_Body) []EventId {
+	eventIds := make([]EventId, len(blockBody.Events))
+	for index, blockEvent := range blockBody.Events {
+		copy(eventIds[index][:], blockEvent.Id)
+	}
+
+	return eventIds
+}
+
+func eventsMerkleTreeLeaves(eventIds []EventId) [][]byte {
+	leaves := make([][]byte, len(eventIds))
+	for index, eventId := range eventIds {
+		leaves[index] = eventsMerkleTreeHash(eventsMerkleTreeLeafPrefix, eventId.Bytes())
+	}
+
+	return leaves
+}
+
+func eventsMerkleTreeLevel(nodes [][]byte) [][]byte {
+	level := make([][]byte, 0, (len(nodes)+1)/2)
+	for index := 0; index < len(nodes); index += 2 {
+		if index+1 == len(nodes) {
+			level = append(level, nodes[index])
+			continue
+		}
+
+		level = append(level, eventsMerkleTreeHash(eventsMerkleTreeNodePrefix, nodes[index], nodes[index+1]))
+	}
+
+	return level
+}
+
+func eventsMerkleTreeHash(prefix byte, parts ...[]byte) []byte {
+	hash := sha256.New()
+	hash.Write([]byte{prefix})
+	for _, part := range parts {
+		hash.Write(part)
+	}
+
+	return hash.Sum(nil)
+}
+
+var (
+	ErrEventInclusionProofEventNotInBlock = errors.New("event inclusion proof event not in block")
+	ErrEventInclusionProofInvalid         = errors.New("event inclusion proof invalid")
+	ErrEventInclusionProofRootMismatch    = errors.New("event inclusion proof root mismatch")
+)
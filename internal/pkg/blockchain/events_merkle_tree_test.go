@@ -0,0 +1,38 @@
+package blockchain
+
+import (
+	"github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEventInclusionProof(t *testing.T) {
+	for eventsCount := 1; eventsCount <= 7; eventsCount++ {
+		blockBody := &blockchain.Block_Body{}
+		for index := 0; index < eventsCount; index++ {
+			blockBody.Events = append(blockBody.Events, &blockchain.Block_Body_BlockEvent{
+				Id: []byte{byte(index), 31: 0xff},
+			})
+		}
+		eventsRoot := NewBlockEventsRoot(blockBody)
+
+		for _, blockEvent := range blockBody.Events {
+			eventId := EventId{}
+			copy(eventId[:], blockEvent.Id)
+
+			proof, err := NewEventInclusionProof(blockBody, eventId)
+			assert.NoError(t, err)
+			assert.NoError(t, VerifyEventInclusionProof(eventsRoot, proof))
+
+			proof.EventId = EmptyEventId.Bytes()
+			assert.ErrorIs(t, VerifyEventInclusionProof(eventsRoot, proof), ErrEventInclusionProofRootMismatch)
+		}
+	}
+
+	blockBody := &blockchain.Block_Body{}
+	_, err := NewEventInclusionProof(blockBody, EmptyEventId)
+	assert.ErrorIs(t, err, ErrEventInclusionProofEventNotInBlock)
+	assert.NotEmpty(t, NewBlockEventsRoot(blockBody))
+
+	assert.ErrorIs(t, VerifyEventInclusionProof(NewBlockEventsRoot(blockBody), &blockchain.EventInclusionProof{}), ErrEventInclusionProofInvalid)
+}
@@ -0,0 +1,87 @@
+package blockchain
+
+import (
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"sync"
+)
+
+const (
+	// DefaultForkMaxDepth is number of blocks below tip, under which side branch blocks are forgotten.
+	DefaultForkMaxDepth = 100
+)
+
+type forkStorageItem struct {
+	block  *blockchainProtocol.Block
+	height int
+}
+
+// ForkStorage keeps blocks of side branches, which are not part of main chain in block storage. Blocks are kept with
+// their height, so branch length can be compared with main chain without walking the branch.
+type ForkStorage struct {
+	state  sync.Mutex
+	blocks map[BlockId]*forkStorageItem
+}
+
+func NewForkStorage() *ForkStorage {
+	return &ForkStorage{
+		blocks: map[BlockId]*forkStorageItem{},
+	}
+}
+
+func (s *ForkStorage) Add(blockId BlockId, block *blockchainProtocol.Block, height int) {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	s.blocks[blockId] = &forkStorageItem{
+		block:  block,
+		height: height,
+	}
+}
+
+// Get returns side branch block with its height.
+func (s *ForkStorage) Get(blockId BlockId) (*blockchainProtocol.Block, int, bool) {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	item, exists := s.blocks[blockId]
+	if !exists {
+		return nil, 0, false
+	}
+
+	return item.block, item.height, true
+}
+
+func (s *ForkStorage) Exists(blockId BlockId) bool {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	_, exists := s.blocks[blockId]
+
+	return exists
+}
+
+func (s *ForkStorage) Remove(blockId BlockId) {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	delete(s.blocks, blockId)
+}
+
+func (s *ForkStorage) Count() int {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	return len(s.blocks)
+}
+
+// Prune removes blocks below given height.
+func (s *ForkStorage) Prune(minHeight int) {
+	defer s.state.Unlock()
+	s.state.Lock()
+
+	for blockId, item := range s.blocks {
+		if item.height < minHeight {
+			delete(s.blocks, blockId)
+		}
+	}
+}
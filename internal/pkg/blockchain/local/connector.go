@@ -19,20 +19,26 @@ func NewConnector() *Connector {
 	}
 }
 
-func (c *Connector) SendEventToBacklog(backlogEvent *blockchain.Event) error {
+func (c *Connector) SendEventToBacklog(ctx context.Context, backlogEvent *blockchain.Event) error {
 	backlogEventCopy := proto.Clone(backlogEvent).(*blockchain.Event)
 
-	c.eventBacklog <- backlogEventCopy
-
-	return nil
+	select {
+	case <-ctx.Done():
+		return ErrCanceledWriteBacklogLocalConnector
+	case c.eventBacklog <- backlogEventCopy:
+		return nil
+	}
 }
 
-func (c *Connector) SendBlockToBacklog(backlogBlock *blockchain.Block) error {
+func (c *Connector) SendBlockToBacklog(ctx context.Context, backlogBlock *blockchain.Block) error {
 	backlogBlockCopy := proto.Clone(backlogBlock).(*blockchain.Block)
 
-	c.blockBacklog <- backlogBlockCopy
-
-	return nil
+	select {
+	case <-ctx.Done():
+		return ErrCanceledWriteBacklogLocalConnector
+	case c.blockBacklog <- backlogBlockCopy:
+		return nil
+	}
 }
 
 func (c *Connector) GetBacklogBlock(ctx context.Context) (*blockchain.Block, error) {
@@ -54,5 +60,6 @@ func (c *Connector) GetBacklogEvent(ctx context.Context) (*blockchain.Event, err
 }
 
 var (
-	ErrCanceledReadBacklogLocalConnector = errors.New("canceled read backlog local connector")
+	ErrCanceledReadBacklogLocalConnector  = errors.New("canceled read backlog local connector")
+	ErrCanceledWriteBacklogLocalConnector = errors.New("canceled write backlog local connector")
 )
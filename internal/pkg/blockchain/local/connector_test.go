@@ -19,7 +19,7 @@ func TestConnector_SendEventToBacklog(t *testing.T) {
 
 	backlogEvent := &blockchain.Event{}
 
-	err := connector.SendEventToBacklog(backlogEvent)
+	err := connector.SendEventToBacklog(context.TODO(), backlogEvent)
 	assert.NoError(t, err)
 }
 
@@ -28,7 +28,7 @@ func TestConnector_SendBlockToBacklog(t *testing.T) {
 
 	backlogBlock := &blockchain.Block{}
 
-	err := connector.SendBlockToBacklog(backlogBlock)
+	err := connector.SendBlockToBacklog(context.TODO(), backlogBlock)
 	assert.NoError(t, err)
 }
 
@@ -37,10 +37,11 @@ func TestConnector_GetBacklogBlock(t *testing.T) {
 
 	backlogBlock := &blockchain.Block{}
 
-	err := connector.SendBlockToBacklog(backlogBlock)
+	err := connector.SendBlockToBacklog(context.TODO(), backlogBlock)
 	assert.NoError(t, err)
 
-	ctx, _ := context.WithTimeout(context.TODO(), time.Millisecond*10)
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Millisecond*10)
+	defer cancel()
 
 	receivedBacklogBlock, err := connector.GetBacklogBlock(ctx)
 	assert.NoError(t, err)
@@ -53,10 +54,11 @@ func TestConnector_GetBacklogEvent(t *testing.T) {
 
 	backlogEvent := &blockchain.Event{}
 
-	err := connector.SendEventToBacklog(backlogEvent)
+	err := connector.SendEventToBacklog(context.TODO(), backlogEvent)
 	assert.NoError(t, err)
 
-	ctx, _ := context.WithTimeout(context.TODO(), time.Millisecond*10)
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Millisecond*10)
+	defer cancel()
 
 	receivedBacklogEvent, err := connector.GetBacklogEvent(ctx)
 	assert.NoError(t, err)
@@ -13,22 +13,34 @@ type localBlockBacklogItem struct {
 	block    *blockchainProtocol.Block
 	sent     bool
 	received bool
+	// seenAt and receivedAt hold height, at which block was seen by Prune first after it was added or received, or -1
+	// before that.
+	seenAt     int
+	receivedAt int
 }
 
 type LocalBlockBacklog struct {
-	log            zerolog.Logger
-	blockValidator *BlockValidator
+	log               zerolog.Logger
+	blockValidator    *BlockValidator
+	confirmationDepth int
+	maxBlocks         int
 
 	blocks      map[BlockId]*localBlockBacklogItem
+	unsent      map[BlockId]struct{}
+	unreceived  map[BlockId]struct{}
 	state       sync.Mutex
 	latestBlock *localBlockBacklogItem
 }
 
-func NewLocalBlockBacklog(blockValidator *BlockValidator) *LocalBlockBacklog {
+func NewLocalBlockBacklog(blockValidator *BlockValidator, settings NetworkSettings) *LocalBlockBacklog {
 	return &LocalBlockBacklog{
-		log:            log.With().Str("applicationComponent", "blockchain").Str("blockchainComponent", "localBlockBacklog").Logger(),
-		blockValidator: blockValidator,
-		blocks:         map[BlockId]*localBlockBacklogItem{},
+		log:               log.With().Str("applicationComponent", "blockchain").Str("blockchainComponent", "localBlockBacklog").Logger(),
+		blockValidator:    blockValidator,
+		confirmationDepth: settings.backlogConfirmationDepth(),
+		maxBlocks:         settings.backlogMaxBlocks(),
+		blocks:            map[BlockId]*localBlockBacklogItem{},
+		unsent:            map[BlockId]struct{}{},
+		unreceived:        map[BlockId]struct{}{},
 	}
 }
 
@@ -52,12 +64,8 @@ func (b *LocalBlockBacklog) Unreceived() map[BlockId]*blockchainProtocol.Block {
 
 	confirmedBlocks := map[BlockId]*blockchainProtocol.Block{}
 
-	for blockId, block := range b.blocks {
-		if block.received {
-			continue
-		}
-
-		blockCopy := proto.Clone(block.block).(*blockchainProtocol.Block)
+	for blockId := range b.unreceived {
+		blockCopy := proto.Clone(b.blocks[blockId].block).(*blockchainProtocol.Block)
 		confirmedBlocks[blockId] = blockCopy
 	}
 
@@ -70,12 +78,8 @@ func (b *LocalBlockBacklog) Unsent() map[BlockId]*blockchainProtocol.Block {
 
 	unsentBlocks := map[BlockId]*blockchainProtocol.Block{}
 
-	for blockId, block := range b.blocks {
-		if block.sent {
-			continue
-		}
-
-		blockCopy := proto.Clone(block.block).(*blockchainProtocol.Block)
+	for blockId := range b.unsent {
+		blockCopy := proto.Clone(b.blocks[blockId].block).(*blockchainProtocol.Block)
 		unsentBlocks[blockId] = blockCopy
 	}
 
@@ -94,6 +98,7 @@ func (b *LocalBlockBacklog) MarkAsSent(blockId BlockId) error {
 			Msg("Local block marked as send.")
 
 		localBlock.sent = true
+		delete(b.unsent, blockId)
 	}
 
 	return nil
@@ -110,12 +115,51 @@ func (b *LocalBlockBacklog) MarkAsConfirmed(blockId BlockId) error {
 			Str("blockId", blockId.String()).
 			Msg("Local block marked as received.")
 
+		if !localBlock.received {
+			localBlock.receivedAt = -1
+		}
+
 		localBlock.received = true
+		delete(b.unreceived, blockId)
 	}
 
 	return nil
 }
 
+// Prune removes blocks received at least confirmation depth blocks below given height. Proposed blocks, which never
+// made it to chain, are removed after same depth, as their slot passed long ago.
+func (b *LocalBlockBacklog) Prune(height int) {
+	defer b.state.Unlock()
+	b.state.Lock()
+
+	prunedCount := 0
+
+	for blockId, localBlock := range b.blocks {
+		if localBlock.seenAt < 0 {
+			localBlock.seenAt = height
+		}
+		if localBlock.received && localBlock.receivedAt < 0 {
+			localBlock.receivedAt = height
+		}
+
+		if localBlock.received && height-localBlock.receivedAt < b.confirmationDepth {
+			continue
+		}
+		if !localBlock.received && height-localBlock.seenAt < b.confirmationDepth {
+			continue
+		}
+
+		delete(b.blocks, blockId)
+		delete(b.unsent, blockId)
+		delete(b.unreceived, blockId)
+		prunedCount++
+	}
+
+	if prunedCount > 0 {
+		b.log.Debug().Int("prunedBlocksCount", prunedCount).Int("height", height).Msg("Blocks pruned from local backlog.")
+	}
+}
+
 func (b *LocalBlockBacklog) Exists(blockId BlockId) bool {
 	defer b.state.Unlock()
 	b.state.Lock()
@@ -124,11 +168,11 @@ func (b *LocalBlockBacklog) Exists(blockId BlockId) bool {
 	return exists
 }
 
-func (b *LocalBlockBacklog) Add(localBlock blockchainProtocol.Block) (*BlockId, error) {
+func (b *LocalBlockBacklog) Add(localBlock *blockchainProtocol.Block) (*BlockId, error) {
 	defer b.state.Unlock()
 	b.state.Lock()
 
-	blockId, err := NewBlockId(&localBlock)
+	blockId, err := NewBlockId(localBlock)
 	if err != nil {
 		log.Warn().Err(err)
 		return nil, errors.Wrap(err, "unable to generate block id")
@@ -139,13 +183,21 @@ func (b *LocalBlockBacklog) Add(localBlock blockchainProtocol.Block) (*BlockId,
 		return nil, ErrLocalBacklogBlockAlreadyExists
 	}
 
-	blockCopy := proto.Clone(&localBlock).(*blockchainProtocol.Block)
+	if len(b.blocks) >= b.maxBlocks {
+		return nil, errors.Wrap(ErrLocalBacklogBlocksLimitReached, "unable to add block to local backlog")
+	}
+
+	blockCopy := proto.Clone(localBlock).(*blockchainProtocol.Block)
 
 	b.blocks[*blockId] = &localBlockBacklogItem{
-		block:    blockCopy,
-		received: false,
-		sent:     false,
+		block:      blockCopy,
+		received:   false,
+		sent:       false,
+		seenAt:     -1,
+		receivedAt: -1,
 	}
+	b.unsent[*blockId] = struct{}{}
+	b.unreceived[*blockId] = struct{}{}
 
 	log.Debug().Str("blockId", blockId.String()).Msg("Block added to local backlog.")
 
@@ -155,4 +207,5 @@ func (b *LocalBlockBacklog) Add(localBlock blockchainProtocol.Block) (*BlockId,
 var (
 	ErrLocalBacklogBlockAlreadyExists = errors.New("local backlog block already exists")
 	ErrLocalBacklogBlockNotFound      = errors.New("local backlog block not found")
+	ErrLocalBacklogBlocksLimitReached = errors.New("local backlog blocks limit reached")
 )
@@ -2,6 +2,7 @@ package blockchain
 
 import (
 	"github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -10,9 +11,9 @@ func TestLocalBlockBacklog_Add(t *testing.T) {
 	var err error
 	var blockId *BlockId
 
-	blockBacklog := NewLocalBlockBacklog(NewBlockValidator(NewEventValidator()))
+	blockBacklog := NewLocalBlockBacklog(NewBlockValidator(NewEventValidator(nil, NetworkSettings{}), newTestEventStorage(), nil, NetworkSettings{}), NetworkSettings{})
 
-	blockId, err = blockBacklog.Add(blockchain.Block{})
+	blockId, err = blockBacklog.Add(&blockchain.Block{})
 	assert.NotNil(t, blockId)
 	assert.NoError(t, err)
 }
@@ -21,9 +22,9 @@ func TestLocalBlockBacklog_Exists(t *testing.T) {
 	var err error
 	var blockId *BlockId
 
-	blockBacklog := NewLocalBlockBacklog(NewBlockValidator(NewEventValidator()))
+	blockBacklog := NewLocalBlockBacklog(NewBlockValidator(NewEventValidator(nil, NetworkSettings{}), newTestEventStorage(), nil, NetworkSettings{}), NetworkSettings{})
 
-	blockId, err = blockBacklog.Add(blockchain.Block{})
+	blockId, err = blockBacklog.Add(&blockchain.Block{})
 	assert.NotNil(t, blockId)
 	assert.NoError(t, err)
 
@@ -35,9 +36,9 @@ func TestLocalBlockBacklog_MarkAsConfirmed(t *testing.T) {
 	var err error
 	var blockId *BlockId
 
-	blockBacklog := NewLocalBlockBacklog(NewBlockValidator(NewEventValidator()))
+	blockBacklog := NewLocalBlockBacklog(NewBlockValidator(NewEventValidator(nil, NetworkSettings{}), newTestEventStorage(), nil, NetworkSettings{}), NetworkSettings{})
 
-	blockId, err = blockBacklog.Add(blockchain.Block{})
+	blockId, err = blockBacklog.Add(&blockchain.Block{})
 	assert.NotNil(t, blockId)
 	assert.NoError(t, err)
 
@@ -52,12 +53,12 @@ func TestLocalBlockBacklog_MarkAsConfirmed(t *testing.T) {
 }
 
 func TestLocalBlockBacklog_Unconfirmed(t *testing.T) {
-	blockBacklog := NewLocalBlockBacklog(NewBlockValidator(NewEventValidator()))
+	blockBacklog := NewLocalBlockBacklog(NewBlockValidator(NewEventValidator(nil, NetworkSettings{}), newTestEventStorage(), nil, NetworkSettings{}), NetworkSettings{})
 
 	unconfirmedEvents := blockBacklog.Unreceived()
 	assert.Empty(t, unconfirmedEvents)
 
-	blockId, err := blockBacklog.Add(blockchain.Block{})
+	blockId, err := blockBacklog.Add(&blockchain.Block{})
 	assert.NotNil(t, blockId)
 	assert.NoError(t, err)
 
@@ -70,9 +71,9 @@ func TestLocalBlockBacklog_MarkAsSent(t *testing.T) {
 	var err error
 	var blockId *BlockId
 
-	blockBacklog := NewLocalBlockBacklog(NewBlockValidator(NewEventValidator()))
+	blockBacklog := NewLocalBlockBacklog(NewBlockValidator(NewEventValidator(nil, NetworkSettings{}), newTestEventStorage(), nil, NetworkSettings{}), NetworkSettings{})
 
-	blockId, err = blockBacklog.Add(blockchain.Block{})
+	blockId, err = blockBacklog.Add(&blockchain.Block{})
 	assert.NotNil(t, blockId)
 	assert.NoError(t, err)
 
@@ -87,12 +88,12 @@ func TestLocalBlockBacklog_MarkAsSent(t *testing.T) {
 }
 
 func TestLocalBlockBacklog_Unsent(t *testing.T) {
-	blockBacklog := NewLocalBlockBacklog(NewBlockValidator(NewEventValidator()))
+	blockBacklog := NewLocalBlockBacklog(NewBlockValidator(NewEventValidator(nil, NetworkSettings{}), newTestEventStorage(), nil, NetworkSettings{}), NetworkSettings{})
 
 	unsentBlocks := blockBacklog.Unsent()
 	assert.Empty(t, unsentBlocks)
 
-	blockId, err := blockBacklog.Add(blockchain.Block{})
+	blockId, err := blockBacklog.Add(&blockchain.Block{})
 	assert.NotNil(t, blockId)
 	assert.NoError(t, err)
 
@@ -100,3 +101,38 @@ func TestLocalBlockBacklog_Unsent(t *testing.T) {
 	assert.Len(t, unsentBlocks, 1)
 	assert.Contains(t, unsentBlocks, *blockId)
 }
+
+func TestLocalBlockBacklog_Prune(t *testing.T) {
+	blockBacklog := NewLocalBlockBacklog(NewBlockValidator(NewEventValidator(nil, NetworkSettings{}), newTestEventStorage(), nil, NetworkSettings{}), NetworkSettings{BacklogConfirmationDepth: 2})
+
+	receivedBlockId, err := blockBacklog.Add(&blockchain.Block{})
+	assert.NoError(t, err)
+	assert.NoError(t, blockBacklog.MarkAsConfirmed(*receivedBlockId))
+
+	blockBacklog.Prune(5)
+
+	abandonedBlockId, err := blockBacklog.Add(&blockchain.Block{Signature: []byte{1}})
+	assert.NoError(t, err)
+
+	blockBacklog.Prune(6)
+	assert.True(t, blockBacklog.Exists(*receivedBlockId))
+	assert.True(t, blockBacklog.Exists(*abandonedBlockId))
+
+	blockBacklog.Prune(7)
+	assert.False(t, blockBacklog.Exists(*receivedBlockId))
+	assert.True(t, blockBacklog.Exists(*abandonedBlockId))
+
+	blockBacklog.Prune(8)
+	assert.False(t, blockBacklog.Exists(*abandonedBlockId))
+	assert.Empty(t, blockBacklog.Unsent())
+}
+
+func TestLocalBlockBacklog_AddLimitReached(t *testing.T) {
+	blockBacklog := NewLocalBlockBacklog(NewBlockValidator(NewEventValidator(nil, NetworkSettings{}), newTestEventStorage(), nil, NetworkSettings{}), NetworkSettings{BacklogMaxBlocks: 1})
+
+	_, err := blockBacklog.Add(&blockchain.Block{})
+	assert.NoError(t, err)
+
+	_, err = blockBacklog.Add(&blockchain.Block{Signature: []byte{1}})
+	assert.Equal(t, ErrLocalBacklogBlocksLimitReached, errors.Cause(err))
+}
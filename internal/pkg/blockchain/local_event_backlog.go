@@ -1,12 +1,14 @@
 package blockchain
 
 import (
+	"github.com/dominati-one/backend/internal/pkg/security"
 	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"sync"
+	"time"
 )
 
 type localEventBacklogItem struct {
@@ -16,20 +18,242 @@ type localEventBacklogItem struct {
 	confirmed bool
 }
 
-type LocalEventBacklog struct {
-	log            zerolog.Logger
-	eventValidator *EventValidator
+// LocalEventStatus describes state of event in local backlog.
+type LocalEventStatus struct {
+	Sent      bool
+	Received  bool
+	Confirmed bool
+}
 
-	state  sync.Mutex
-	events map[EventId]*localEventBacklogItem
+type LocalEventBacklog struct {
+	log               zerolog.Logger
+	eventValidator    *EventValidator
+	privateKey        *security.PrivateKey
+	journal           LocalEventJournal
+	confirmationDepth int
+	maxEvents         int
+	eventValidity     time.Duration
+
+	state     sync.Mutex
+	lastNonce uint64
+	events    map[EventId]*localEventBacklogItem
+	// unsent, unreceived and unconfirmed index events by state, so send loop does not scan whole backlog.
+	unsent      map[EventId]struct{}
+	unreceived  map[EventId]struct{}
+	unconfirmed map[EventId]struct{}
+	// confirmedAt holds height, at which confirmed event was seen by Prune first, or -1 before that.
+	confirmedAt map[EventId]int
 }
 
-func NewLocalEventBacklog(eventValidator *EventValidator) *LocalEventBacklog {
+// NewLocalEventBacklog creates backlog. Transitions of events are recorded in journal, backlog is kept in memory
+// only, when journal is nil.
+func NewLocalEventBacklog(eventValidator *EventValidator, privateKey *security.PrivateKey, journal LocalEventJournal, settings NetworkSettings) *LocalEventBacklog {
 	return &LocalEventBacklog{
-		log:            log.With().Str("applicationComponent", "blockchain").Str("blockchainComponent", "localEventBacklog").Logger(),
-		eventValidator: eventValidator,
-		events:         map[EventId]*localEventBacklogItem{},
+		log:               log.With().Str("applicationComponent", "blockchain").Str("blockchainComponent", "localEventBacklog").Logger(),
+		eventValidator:    eventValidator,
+		privateKey:        privateKey,
+		journal:           journal,
+		confirmationDepth: settings.backlogConfirmationDepth(),
+		maxEvents:         settings.backlogMaxEvents(),
+		eventValidity:     settings.eventMaxValidity(),
+		events:            map[EventId]*localEventBacklogItem{},
+		unsent:            map[EventId]struct{}{},
+		unreceived:        map[EventId]struct{}{},
+		unconfirmed:       map[EventId]struct{}{},
+		confirmedAt:       map[EventId]int{},
+	}
+}
+
+// Restore loads backlog from journal. Confirmed, expired and already stored events are pruned and journal is
+// compacted. Node's own network backlog does not survive restart, so unconfirmed events are sent again.
+func (b *LocalEventBacklog) Restore(eventStorage EventStorage) error {
+	defer b.state.Unlock()
+	b.state.Lock()
+
+	if b.journal == nil {
+		return nil
+	}
+
+	entries, err := b.journal.Load()
+	if err != nil {
+		return errors.Wrap(err, "unable to load local event journal")
+	}
+
+	events := map[EventId]*localEventBacklogItem{}
+	order := []EventId{}
+
+	for _, entry := range entries {
+		if entry.Operation == LocalEventJournalOperationAdd {
+			if _, exists := events[entry.EventId]; !exists {
+				order = append(order, entry.EventId)
+			}
+			events[entry.EventId] = &localEventBacklogItem{event: entry.Event}
+			continue
+		}
+
+		localEvent, exists := events[entry.EventId]
+		if !exists {
+			continue
+		}
+
+		switch entry.Operation {
+		case LocalEventJournalOperationSent:
+			localEvent.sent = true
+		case LocalEventJournalOperationReceived:
+			localEvent.received = true
+		case LocalEventJournalOperationConfirmed:
+			localEvent.confirmed = true
+		}
+	}
+
+	compactedEntries := []LocalEventJournalEntry{}
+	prunedCount := 0
+	now := CreateBlockTimestampFromNow().UnixMilliseconds()
+
+	for _, eventId := range order {
+		localEvent := events[eventId]
+
+		if localEvent.confirmed || localEvent.event.Body.GetValidUntil() < now || eventStorage.Exists(eventId) {
+			prunedCount++
+			continue
+		}
+
+		b.insert(eventId, localEvent.event)
+
+		compactedEntries = append(compactedEntries, LocalEventJournalEntry{
+			Operation: LocalEventJournalOperationAdd,
+			EventId:   eventId,
+			Event:     localEvent.event,
+		})
+	}
+
+	if err := b.journal.Compact(compactedEntries); err != nil {
+		return errors.Wrap(err, "unable to compact local event journal")
+	}
+
+	b.log.Info().
+		Int("restoredEventsCount", len(compactedEntries)).
+		Int("prunedEventsCount", prunedCount).
+		Msg("Local event backlog restored.")
+
+	return nil
+}
+
+// Prune removes events confirmed at least confirmation depth blocks below given height and compacts journal, when
+// any event was removed.
+func (b *LocalEventBacklog) Prune(height int) error {
+	defer b.state.Unlock()
+	b.state.Lock()
+
+	prunedCount := 0
+
+	for eventId, confirmedAt := range b.confirmedAt {
+		if confirmedAt < 0 {
+			b.confirmedAt[eventId] = height
+			confirmedAt = height
+		}
+
+		if height-confirmedAt < b.confirmationDepth {
+			continue
+		}
+
+		delete(b.events, eventId)
+		delete(b.confirmedAt, eventId)
+		delete(b.unsent, eventId)
+		delete(b.unreceived, eventId)
+		prunedCount++
+	}
+
+	if prunedCount == 0 {
+		return nil
+	}
+
+	b.log.Debug().Int("prunedEventsCount", prunedCount).Int("height", height).Msg("Confirmed events pruned from local backlog.")
+
+	return b.compactJournal()
+}
+
+// RemoveExpired removes unconfirmed events, which expire before given block timestamp and will never be confirmed.
+func (b *LocalEventBacklog) RemoveExpired(timestamp BlockTimestamp) error {
+	defer b.state.Unlock()
+	b.state.Lock()
+
+	removedCount := 0
+
+	for eventId := range b.unconfirmed {
+		if b.events[eventId].event.Body.GetValidUntil() >= timestamp.UnixMilliseconds() {
+			continue
+		}
+
+		b.log.Warn().Str("eventId", eventId.String()).Msg("Local event expired before it was confirmed.")
+
+		delete(b.events, eventId)
+		delete(b.unsent, eventId)
+		delete(b.unreceived, eventId)
+		delete(b.unconfirmed, eventId)
+		removedCount++
+	}
+
+	if removedCount == 0 {
+		return nil
+	}
+
+	return b.compactJournal()
+}
+
+// compactJournal replaces journal with entries describing current backlog. Lock must be held.
+func (b *LocalEventBacklog) compactJournal() error {
+	if b.journal == nil {
+		return nil
+	}
+
+	entries := []LocalEventJournalEntry{}
+	for eventId, localEvent := range b.events {
+		entries = append(entries, LocalEventJournalEntry{
+			Operation: LocalEventJournalOperationAdd,
+			EventId:   eventId,
+			Event:     localEvent.event,
+		})
+
+		if localEvent.sent {
+			entries = append(entries, LocalEventJournalEntry{Operation: LocalEventJournalOperationSent, EventId: eventId})
+		}
+		if localEvent.received {
+			entries = append(entries, LocalEventJournalEntry{Operation: LocalEventJournalOperationReceived, EventId: eventId})
+		}
+		if localEvent.confirmed {
+			entries = append(entries, LocalEventJournalEntry{Operation: LocalEventJournalOperationConfirmed, EventId: eventId})
+		}
+	}
+
+	if err := b.journal.Compact(entries); err != nil {
+		return errors.Wrap(err, "unable to compact local event journal")
+	}
+
+	return nil
+}
+
+// insert adds unsent event to backlog and its indexes. Lock must be held.
+func (b *LocalEventBacklog) insert(eventId EventId, event *blockchainProtocol.Event) {
+	b.events[eventId] = &localEventBacklogItem{
+		event: event,
 	}
+	b.unsent[eventId] = struct{}{}
+	b.unreceived[eventId] = struct{}{}
+	b.unconfirmed[eventId] = struct{}{}
+}
+
+// record appends transition to journal, when backlog has one. Lock must be held.
+func (b *LocalEventBacklog) record(operation LocalEventJournalOperation, eventId EventId, event *blockchainProtocol.Event) error {
+	if b.journal == nil {
+		return nil
+	}
+
+	return b.journal.Append(LocalEventJournalEntry{
+		Operation: operation,
+		EventId:   eventId,
+		Event:     event,
+	})
 }
 
 // Exists checks if event is in local backlog.
@@ -42,6 +266,23 @@ func (b *LocalEventBacklog) Exists(eventId EventId) bool {
 	return exists
 }
 
+// Status returns state of event in local backlog. False is returned, when event is not in backlog.
+func (b *LocalEventBacklog) Status(eventId EventId) (LocalEventStatus, bool) {
+	defer b.state.Unlock()
+	b.state.Lock()
+
+	localEvent, exists := b.events[eventId]
+	if !exists {
+		return LocalEventStatus{}, false
+	}
+
+	return LocalEventStatus{
+		Sent:      localEvent.sent,
+		Received:  localEvent.received,
+		Confirmed: localEvent.confirmed,
+	}, true
+}
+
 // MarkAsReceived should be called, when local event is received from network. This means, that event was validated by nodes
 // and will be placed on block.
 func (b *LocalEventBacklog) MarkAsReceived(eventId EventId) error {
@@ -51,12 +292,19 @@ func (b *LocalEventBacklog) MarkAsReceived(eventId EventId) error {
 	if localEvent, exists := b.events[eventId]; !exists {
 		return errors.Wrap(ErrLocalBacklogEventNotFound, "unable to mark local event as received")
 	} else {
+		if !localEvent.received {
+			if err := b.record(LocalEventJournalOperationReceived, eventId, nil); err != nil {
+				return errors.Wrap(err, "unable to record local event as received")
+			}
+		}
+
 		b.log.Trace().
 			Str("eventId", eventId.String()).
 			Str("eventData", localEvent.event.Body.String()).
 			Msg("Local event marked as received.")
 
 		localEvent.received = true
+		delete(b.unreceived, eventId)
 	}
 
 	return nil
@@ -71,12 +319,23 @@ func (b *LocalEventBacklog) MarkAsConfirmed(eventId EventId) error {
 	if localEvent, exists := b.events[eventId]; !exists {
 		return errors.Wrap(ErrLocalBacklogEventNotFound, "unable to mark local event as confirmed")
 	} else {
+		if !localEvent.confirmed {
+			if err := b.record(LocalEventJournalOperationConfirmed, eventId, nil); err != nil {
+				return errors.Wrap(err, "unable to record local event as confirmed")
+			}
+		}
+
 		b.log.Trace().
 			Str("eventId", eventId.String()).
 			Str("eventData", localEvent.event.Body.String()).
 			Msg("Local event marked as confirmed.")
 
+		if !localEvent.confirmed {
+			b.confirmedAt[eventId] = -1
+		}
+
 		localEvent.confirmed = true
+		delete(b.unconfirmed, eventId)
 	}
 
 	return nil
@@ -90,12 +349,19 @@ func (b *LocalEventBacklog) MarkAsSent(eventId EventId) error {
 	if localEvent, exists := b.events[eventId]; !exists {
 		return errors.Wrap(ErrLocalBacklogEventNotFound, "unable to mark local event as sent")
 	} else {
+		if !localEvent.sent {
+			if err := b.record(LocalEventJournalOperationSent, eventId, nil); err != nil {
+				return errors.Wrap(err, "unable to record local event as sent")
+			}
+		}
+
 		b.log.Trace().
 			Str("eventId", eventId.String()).
 			Str("eventData", localEvent.event.String()).
 			Msg("Local event marked as send.")
 
 		localEvent.sent = true
+		delete(b.unsent, eventId)
 	}
 
 	return nil
@@ -108,12 +374,8 @@ func (b *LocalEventBacklog) Unreceived() map[EventId]*blockchainProtocol.Event {
 
 	unreceived := map[EventId]*blockchainProtocol.Event{}
 
-	for eventId, event := range b.events {
-		if event.received {
-			continue
-		}
-
-		unreceived[eventId] = proto.Clone(event.event).(*blockchainProtocol.Event)
+	for eventId := range b.unreceived {
+		unreceived[eventId] = proto.Clone(b.events[eventId].event).(*blockchainProtocol.Event)
 	}
 
 	return unreceived
@@ -126,12 +388,8 @@ func (b *LocalEventBacklog) Unconfirmed() map[EventId]*blockchainProtocol.Event
 
 	unconfirmed := map[EventId]*blockchainProtocol.Event{}
 
-	for eventId, event := range b.events {
-		if event.confirmed {
-			continue
-		}
-
-		unconfirmed[eventId] = proto.Clone(event.event).(*blockchainProtocol.Event)
+	for eventId := range b.unconfirmed {
+		unconfirmed[eventId] = proto.Clone(b.events[eventId].event).(*blockchainProtocol.Event)
 	}
 
 	return unconfirmed
@@ -144,18 +402,14 @@ func (b *LocalEventBacklog) Unsent() map[EventId]*blockchainProtocol.Event {
 
 	unsent := map[EventId]*blockchainProtocol.Event{}
 
-	for eventId, event := range b.events {
-		if event.sent {
-			continue
-		}
-
-		unsent[eventId] = proto.Clone(event.event).(*blockchainProtocol.Event)
+	for eventId := range b.unsent {
+		unsent[eventId] = proto.Clone(b.events[eventId].event).(*blockchainProtocol.Event)
 	}
 
 	return unsent
 }
 
-// Add insert local emitted event in to backlog.
+// Add insert local emitted event in to backlog. Event body is signed with backlog private key.
 func (b *LocalEventBacklog) Add(localEvent interface{}) (EventId, error) {
 	defer b.state.Unlock()
 	b.state.Lock()
@@ -175,11 +429,27 @@ func (b *LocalEventBacklog) Add(localEvent interface{}) (EventId, error) {
 		return EmptyEventId, ErrLocalBacklogUnsupportedEvent
 	}
 
-	backlogEvent.Timestamp = CreateBlockTimestampFromNow().UnixMilliseconds()
+	now := CreateBlockTimestampFromNow()
+
+	b.lastNonce++
+	if nonce := uint64(now.UnixNano()); nonce > b.lastNonce {
+		b.lastNonce = nonce
+	}
+
+	backlogEvent.Timestamp = now.UnixMilliseconds()
+	backlogEvent.Body.ValidFrom = now.UnixMilliseconds()
+	backlogEvent.Body.ValidUntil = now.Add(b.eventValidity).UnixMilliseconds()
+	backlogEvent.Body.Nonce = b.lastNonce
+
+	signature, err := security.CreateSignatureFromBody(backlogEvent.Body, b.privateKey)
+	if err != nil {
+		return EmptyEventId, errors.Wrap(err, "unable to sign event")
+	}
+	backlogEvent.Signature = signature.Bytes()
 
 	log = log.With().Str("eventData", backlogEvent.String()).Logger()
 
-	err := b.eventValidator.Validate(backlogEvent)
+	err = b.eventValidator.Validate(backlogEvent)
 	if err != nil {
 		log.Warn().Err(err)
 		return EmptyEventId, errors.Wrap(err, "unable to add event to local backlog")
@@ -195,13 +465,16 @@ func (b *LocalEventBacklog) Add(localEvent interface{}) (EventId, error) {
 		return EmptyEventId, errors.Wrap(ErrLocalBacklogEventAlreadyExists, "unable to add event to local backlog")
 	}
 
-	b.events[eventId] = &localEventBacklogItem{
-		event:     backlogEvent,
-		received:  false,
-		sent:      false,
-		confirmed: false,
+	if len(b.events) >= b.maxEvents {
+		return EmptyEventId, errors.Wrap(ErrLocalBacklogEventsLimitReached, "unable to add event to local backlog")
 	}
 
+	if err := b.record(LocalEventJournalOperationAdd, eventId, backlogEvent); err != nil {
+		return EmptyEventId, errors.Wrap(err, "unable to record event in local event journal")
+	}
+
+	b.insert(eventId, backlogEvent)
+
 	log.Debug().Str("eventId", eventId.String()).Msg("Event added to local backlog.")
 
 	return eventId, nil
@@ -211,4 +484,5 @@ var (
 	ErrLocalBacklogUnsupportedEvent   = errors.New("local backlog unsupported event")
 	ErrLocalBacklogEventNotFound      = errors.New("local backlog event not found")
 	ErrLocalBacklogEventAlreadyExists = errors.New("local backlog event already exists")
+	ErrLocalBacklogEventsLimitReached = errors.New("local backlog events limit reached")
 )
@@ -2,15 +2,18 @@ package blockchain
 
 import (
 	"github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestLocalEventBacklog_Add(t *testing.T) {
 	var err error
 	var eventId EventId
 
-	eventBacklog := NewLocalEventBacklog(NewEventValidator())
+	privateKey := testPrivateKey(t)
+	eventBacklog := NewLocalEventBacklog(NewEventValidator(nil, NetworkSettings{}), privateKey, nil, NetworkSettings{})
 
 	eventId, err = eventBacklog.Add("invalid event")
 	assert.EqualValues(t, EmptyEventId, eventId)
@@ -20,7 +23,7 @@ func TestLocalEventBacklog_Add(t *testing.T) {
 	assert.NotEqualValues(t, EmptyEventId, eventId)
 	assert.NoError(t, err)
 
-	eventId, err = eventBacklog.Add(&blockchain.EventCreatePlayer{})
+	eventId, err = eventBacklog.Add(&blockchain.EventCreatePlayer{PublicKey: privateKey.PublicKey(), Name: "player"})
 	assert.NotEqualValues(t, EmptyEventId, eventId)
 	assert.NoError(t, err)
 }
@@ -29,7 +32,7 @@ func TestLocalEventBacklog_Exists(t *testing.T) {
 	var err error
 	var eventId EventId
 
-	eventBacklog := NewLocalEventBacklog(NewEventValidator())
+	eventBacklog := NewLocalEventBacklog(NewEventValidator(nil, NetworkSettings{}), testPrivateKey(t), nil, NetworkSettings{})
 
 	eventId, err = eventBacklog.Add(&blockchain.EventCreatePlanet{})
 	assert.NotEqualValues(t, EmptyEventId, eventId)
@@ -39,11 +42,36 @@ func TestLocalEventBacklog_Exists(t *testing.T) {
 	assert.True(t, eventExists)
 }
 
+func TestLocalEventBacklog_Status(t *testing.T) {
+	eventBacklog := NewLocalEventBacklog(NewEventValidator(nil, NetworkSettings{}), testPrivateKey(t), nil, NetworkSettings{})
+
+	_, exists := eventBacklog.Status(EmptyEventId)
+	assert.False(t, exists)
+
+	eventId, err := eventBacklog.Add(&blockchain.EventCreatePlanet{})
+	assert.NoError(t, err)
+
+	status, exists := eventBacklog.Status(eventId)
+	assert.True(t, exists)
+	assert.Equal(t, LocalEventStatus{}, status)
+
+	assert.NoError(t, eventBacklog.MarkAsSent(eventId))
+	assert.NoError(t, eventBacklog.MarkAsReceived(eventId))
+
+	status, _ = eventBacklog.Status(eventId)
+	assert.Equal(t, LocalEventStatus{Sent: true, Received: true}, status)
+
+	assert.NoError(t, eventBacklog.MarkAsConfirmed(eventId))
+
+	status, _ = eventBacklog.Status(eventId)
+	assert.Equal(t, LocalEventStatus{Sent: true, Received: true, Confirmed: true}, status)
+}
+
 func TestLocalEventBacklog_MarkAsConfirmed(t *testing.T) {
 	var err error
 	var eventId EventId
 
-	eventBacklog := NewLocalEventBacklog(NewEventValidator())
+	eventBacklog := NewLocalEventBacklog(NewEventValidator(nil, NetworkSettings{}), testPrivateKey(t), nil, NetworkSettings{})
 
 	eventId, err = eventBacklog.Add(&blockchain.EventCreatePlanet{})
 	assert.NotEqualValues(t, EmptyEventId, eventId)
@@ -60,7 +88,7 @@ func TestLocalEventBacklog_MarkAsConfirmed(t *testing.T) {
 }
 
 func TestLocalEventBacklog_Unconfirmed(t *testing.T) {
-	eventBacklog := NewLocalEventBacklog(NewEventValidator())
+	eventBacklog := NewLocalEventBacklog(NewEventValidator(nil, NetworkSettings{}), testPrivateKey(t), nil, NetworkSettings{})
 
 	unconfirmedEvents := eventBacklog.Unreceived()
 	assert.Empty(t, unconfirmedEvents)
@@ -78,7 +106,7 @@ func TestLocalEventBacklog_MarkAsSent(t *testing.T) {
 	var err error
 	var eventId EventId
 
-	eventBacklog := NewLocalEventBacklog(NewEventValidator())
+	eventBacklog := NewLocalEventBacklog(NewEventValidator(nil, NetworkSettings{}), testPrivateKey(t), nil, NetworkSettings{})
 
 	eventId, err = eventBacklog.Add(&blockchain.EventCreatePlanet{})
 	assert.NotEqualValues(t, EmptyEventId, eventId)
@@ -95,7 +123,7 @@ func TestLocalEventBacklog_MarkAsSent(t *testing.T) {
 }
 
 func TestLocalEventBacklog_Unsent(t *testing.T) {
-	eventBacklog := NewLocalEventBacklog(NewEventValidator())
+	eventBacklog := NewLocalEventBacklog(NewEventValidator(nil, NetworkSettings{}), testPrivateKey(t), nil, NetworkSettings{})
 
 	unsentEvents := eventBacklog.Unsent()
 	assert.Empty(t, unsentEvents)
@@ -108,3 +136,132 @@ func TestLocalEventBacklog_Unsent(t *testing.T) {
 	assert.Len(t, unsentEvents, 1)
 	assert.Contains(t, unsentEvents, eventId)
 }
+
+type testLocalEventJournal struct {
+	entries []LocalEventJournalEntry
+}
+
+func (j *testLocalEventJournal) Append(entry LocalEventJournalEntry) error {
+	j.entries = append(j.entries, entry)
+
+	return nil
+}
+
+func (j *testLocalEventJournal) Load() ([]LocalEventJournalEntry, error) {
+	return j.entries, nil
+}
+
+func (j *testLocalEventJournal) Compact(entries []LocalEventJournalEntry) error {
+	j.entries = entries
+
+	return nil
+}
+
+func TestLocalEventBacklog_Restore(t *testing.T) {
+	privateKey := testPrivateKey(t)
+	journal := &testLocalEventJournal{}
+	eventStorage := newTestEventStorage()
+
+	eventBacklog := NewLocalEventBacklog(NewEventValidator(nil, NetworkSettings{}), privateKey, journal, NetworkSettings{})
+
+	sentEventId, err := eventBacklog.Add(&blockchain.EventCreatePlanet{})
+	assert.NoError(t, err)
+	assert.NoError(t, eventBacklog.MarkAsSent(sentEventId))
+
+	unsentEventId, err := eventBacklog.Add(&blockchain.EventCreatePlayer{PublicKey: privateKey.PublicKey(), Name: "player"})
+	assert.NoError(t, err)
+
+	confirmedEventId, err := eventBacklog.Add(&blockchain.EventCreatePlayer{PublicKey: privateKey.PublicKey(), Name: "confirmed"})
+	assert.NoError(t, err)
+	assert.NoError(t, eventBacklog.MarkAsSent(confirmedEventId))
+	assert.NoError(t, eventBacklog.MarkAsConfirmed(confirmedEventId))
+
+	storedEventId, err := eventBacklog.Add(&blockchain.EventCreatePlayer{PublicKey: privateKey.PublicKey(), Name: "stored"})
+	assert.NoError(t, err)
+	_, err = eventStorage.Add(eventBacklog.Unsent()[storedEventId], BlockId{})
+	assert.NoError(t, err)
+
+	assert.Len(t, journal.entries, 7)
+
+	restoredBacklog := NewLocalEventBacklog(NewEventValidator(nil, NetworkSettings{}), privateKey, journal, NetworkSettings{})
+	assert.NoError(t, restoredBacklog.Restore(eventStorage))
+
+	assert.True(t, restoredBacklog.Exists(sentEventId))
+	assert.True(t, restoredBacklog.Exists(unsentEventId))
+	assert.False(t, restoredBacklog.Exists(confirmedEventId))
+	assert.False(t, restoredBacklog.Exists(storedEventId))
+
+	unsentEvents := restoredBacklog.Unsent()
+	assert.Len(t, unsentEvents, 2)
+	assert.Contains(t, unsentEvents, sentEventId)
+	assert.Contains(t, unsentEvents, unsentEventId)
+
+	assert.Len(t, journal.entries, 2)
+}
+
+func TestLocalEventBacklog_Prune(t *testing.T) {
+	privateKey := testPrivateKey(t)
+	journal := &testLocalEventJournal{}
+
+	eventBacklog := NewLocalEventBacklog(NewEventValidator(nil, NetworkSettings{}), privateKey, journal, NetworkSettings{BacklogConfirmationDepth: 2})
+
+	confirmedEventId, err := eventBacklog.Add(&blockchain.EventCreatePlanet{})
+	assert.NoError(t, err)
+	assert.NoError(t, eventBacklog.MarkAsSent(confirmedEventId))
+	assert.NoError(t, eventBacklog.MarkAsConfirmed(confirmedEventId))
+
+	pendingEventId, err := eventBacklog.Add(&blockchain.EventCreatePlayer{PublicKey: privateKey.PublicKey(), Name: "player"})
+	assert.NoError(t, err)
+	assert.NoError(t, eventBacklog.MarkAsSent(pendingEventId))
+
+	assert.NoError(t, eventBacklog.Prune(5))
+	assert.NoError(t, eventBacklog.Prune(6))
+	assert.True(t, eventBacklog.Exists(confirmedEventId))
+	assert.Len(t, journal.entries, 5)
+
+	assert.NoError(t, eventBacklog.Prune(7))
+	assert.False(t, eventBacklog.Exists(confirmedEventId))
+	assert.True(t, eventBacklog.Exists(pendingEventId))
+	assert.Empty(t, eventBacklog.Unsent())
+	assert.Len(t, eventBacklog.Unconfirmed(), 1)
+
+	assert.Equal(t, []LocalEventJournalEntry{
+		{Operation: LocalEventJournalOperationAdd, EventId: pendingEventId, Event: journal.entries[0].Event},
+		{Operation: LocalEventJournalOperationSent, EventId: pendingEventId},
+	}, journal.entries)
+}
+
+func TestLocalEventBacklog_AddLimitReached(t *testing.T) {
+	eventBacklog := NewLocalEventBacklog(NewEventValidator(nil, NetworkSettings{}), testPrivateKey(t), nil, NetworkSettings{BacklogMaxEvents: 1})
+
+	_, err := eventBacklog.Add(&blockchain.EventCreatePlanet{})
+	assert.NoError(t, err)
+
+	_, err = eventBacklog.Add(&blockchain.EventCreatePlanet{Seed: 1})
+	assert.Equal(t, ErrLocalBacklogEventsLimitReached, errors.Cause(err))
+}
+
+func TestLocalEventBacklog_RemoveExpired(t *testing.T) {
+	journal := &testLocalEventJournal{}
+	eventBacklog := NewLocalEventBacklog(NewEventValidator(nil, NetworkSettings{}), testPrivateKey(t), journal, NetworkSettings{})
+
+	firstEventId, err := eventBacklog.Add(&blockchain.EventCreatePlanet{})
+	assert.NoError(t, err)
+
+	secondEventId, err := eventBacklog.Add(&blockchain.EventCreatePlanet{})
+	assert.NoError(t, err)
+	assert.NoError(t, eventBacklog.MarkAsConfirmed(secondEventId))
+
+	unconfirmedEvents := eventBacklog.Unconfirmed()
+	firstEvent := unconfirmedEvents[firstEventId]
+	assert.Equal(t, firstEvent.Body.ValidFrom+uint64(DefaultEventMaxValidity/time.Millisecond), firstEvent.Body.ValidUntil)
+	assert.NotZero(t, firstEvent.Body.Nonce)
+
+	assert.NoError(t, eventBacklog.RemoveExpired(CreateBlockTimestampFromNow()))
+	assert.True(t, eventBacklog.Exists(firstEventId))
+
+	assert.NoError(t, eventBacklog.RemoveExpired(CreateBlockTimestampFromNow().Add(DefaultEventMaxValidity+time.Second)))
+	assert.False(t, eventBacklog.Exists(firstEventId))
+	assert.True(t, eventBacklog.Exists(secondEventId))
+	assert.Len(t, journal.entries, 2)
+}
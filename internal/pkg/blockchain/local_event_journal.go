@@ -0,0 +1,32 @@
+package blockchain
+
+import (
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+)
+
+type LocalEventJournalOperation uint8
+
+const (
+	LocalEventJournalOperationAdd LocalEventJournalOperation = iota + 1
+	LocalEventJournalOperationSent
+	LocalEventJournalOperationReceived
+	LocalEventJournalOperationConfirmed
+)
+
+// LocalEventJournalEntry is single transition of local event backlog. Event is set for add operation only.
+type LocalEventJournalEntry struct {
+	Operation LocalEventJournalOperation
+	EventId   EventId
+	Event     *blockchainProtocol.Event
+}
+
+// LocalEventJournal durably records transitions of local event backlog, so events submitted by players survive
+// restart of node.
+type LocalEventJournal interface {
+	// Append records entry. Entry must be durable, when Append returns.
+	Append(entry LocalEventJournalEntry) error
+	// Load returns all recorded entries in order.
+	Load() ([]LocalEventJournalEntry, error)
+	// Compact replaces recorded entries with given ones.
+	Compact(entries []LocalEventJournalEntry) error
+}
@@ -7,44 +7,117 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"sync"
 	"time"
 )
 
+const (
+	DefaultBacklogConfirmationDepth = 10
+	DefaultBacklogMaxEvents         = 10000
+	DefaultBacklogMaxBlocks         = 100
+)
+
 type NetworkSettings struct {
 	BlockInterval       time.Duration
+	BlockMaxClockDrift  time.Duration
+	BlockSlotTimeout    time.Duration
+	BlockMaxEvents      int
+	BlockMaxEventsSize  int
+	EventMaxValidity    time.Duration
 	AuthorityPublicKeys *security.PublicKeysBag
 	GenesisBlock        *blockchainProtocol.Block
+	// BacklogConfirmationDepth is number of blocks, after which confirmed items are removed from backlogs.
+	BacklogConfirmationDepth int
+	// BacklogMaxEvents is capacity of each event backlog, new events are rejected, when backlog is full.
+	BacklogMaxEvents int
+	// BacklogMaxBlocks is capacity of local block backlog.
+	BacklogMaxBlocks int
+}
+
+func (s NetworkSettings) blockMaxEvents() int {
+	if s.BlockMaxEvents <= 0 {
+		return DefaultBlockMaxEvents
+	}
+
+	return s.BlockMaxEvents
+}
+
+func (s NetworkSettings) blockMaxEventsSize() int {
+	if s.BlockMaxEventsSize <= 0 {
+		return DefaultBlockMaxEventsSize
+	}
+
+	return s.BlockMaxEventsSize
+}
+
+func (s NetworkSettings) eventMaxValidity() time.Duration {
+	if s.EventMaxValidity <= 0 {
+		return DefaultEventMaxValidity
+	}
+
+	return s.EventMaxValidity
+}
+
+func (s NetworkSettings) backlogConfirmationDepth() int {
+	if s.BacklogConfirmationDepth <= 0 {
+		return DefaultBacklogConfirmationDepth
+	}
+
+	return s.BacklogConfirmationDepth
+}
+
+func (s NetworkSettings) backlogMaxEvents() int {
+	if s.BacklogMaxEvents <= 0 {
+		return DefaultBacklogMaxEvents
+	}
+
+	return s.BacklogMaxEvents
+}
+
+func (s NetworkSettings) backlogMaxBlocks() int {
+	if s.BacklogMaxBlocks <= 0 {
+		return DefaultBacklogMaxBlocks
+	}
+
+	return s.BacklogMaxBlocks
 }
 
 type Network struct {
 	log                            zerolog.Logger
 	settings                       NetworkSettings
+	privateKey                     *security.PrivateKey
 	connector                      Connector
 	eventEmitter                   *EventEmitter
 	localEventBacklog              *LocalEventBacklog
 	networkEventBacklog            *NetworkEventBacklog
 	eventValidator                 *EventValidator
 	eventStorage                   EventStorage
+	stateHasher                    StateHasher
 	blockTicker                    *BlockTicker
+	proposerSchedule               *ProposerSchedule
 	blockStorage                   BlockStorage
 	blockValidator                 *BlockValidator
 	localBlockBacklog              *LocalBlockBacklog
 	blockBlockchainBacklogReceiver *BlockBlockchainBacklogReceiver
+	loops                          sync.WaitGroup
 }
 
-func NewNetwork(settings NetworkSettings, connector Connector, eventStorage EventStorage, blockStorage BlockStorage) *Network {
-	eventValidator := NewEventValidator()
-	blockValidator := NewBlockValidator(eventValidator)
+func NewNetwork(settings NetworkSettings, connector Connector, eventStorage EventStorage, blockStorage BlockStorage, stateHasher StateHasher, stateRewinder StateRewinder, localEventJournal LocalEventJournal, privateKey *security.PrivateKey) *Network {
+	eventValidator := NewEventValidator(eventStorage, settings)
+	blockValidator := NewBlockValidator(eventValidator, eventStorage, stateHasher, settings)
 
-	localBlockBacklog := NewLocalBlockBacklog(blockValidator)
-	localEventBacklog := NewLocalEventBacklog(eventValidator)
-	networkEventBacklog := NewNetworkEventBacklog(eventValidator)
+	localBlockBacklog := NewLocalBlockBacklog(blockValidator, settings)
+	localEventBacklog := NewLocalEventBacklog(eventValidator, privateKey, localEventJournal, settings)
+	networkEventBacklog := NewNetworkEventBacklog(eventValidator, settings)
 
 	eventEmitter := NewEventEmitter()
 
 	blockchainBlockBacklogReceiver := NewBlockBlockchainBacklogReceiver(blockBlockchainBacklogReceiverDependencies{
 		connector:           connector,
 		blockStorage:        blockStorage,
+		forkStorage:         NewForkStorage(),
+		eventStorage:        eventStorage,
+		stateRewinder:       stateRewinder,
 		eventEmitter:        eventEmitter,
 		blockValidator:      blockValidator,
 		localBlockBacklog:   localBlockBacklog,
@@ -56,13 +129,16 @@ func NewNetwork(settings NetworkSettings, connector Connector, eventStorage Even
 		log:                            log.With().Str("applicationComponent", "blockchain").Logger(),
 		connector:                      connector,
 		settings:                       settings,
+		privateKey:                     privateKey,
 		eventStorage:                   eventStorage,
+		stateHasher:                    stateHasher,
 		localEventBacklog:              localEventBacklog,
 		networkEventBacklog:            networkEventBacklog,
 		eventValidator:                 eventValidator,
 		eventEmitter:                   eventEmitter,
 		localBlockBacklog:              localBlockBacklog,
 		blockTicker:                    NewBlockTicker(settings),
+		proposerSchedule:               NewProposerSchedule(settings),
 		blockStorage:                   blockStorage,
 		blockValidator:                 blockValidator,
 		blockBlockchainBacklogReceiver: blockchainBlockBacklogReceiver,
@@ -78,10 +154,18 @@ func (n *Network) Start(ctx context.Context) error {
 		}
 	}
 
-	go n.localEventBacklogSendLoop(ctx)
-	go n.blockchainEventBacklogReceiveLoop(ctx)
-	go n.blockBuildLoop(ctx)
-	go n.localBlockBacklogSendLoop(ctx)
+	if err := n.localEventBacklog.Restore(n.eventStorage); err != nil {
+		return errors.Wrap(err, "unable to restore local event backlog")
+	}
+
+	n.startLoop(ctx, n.localEventBacklogSendLoop)
+	n.startLoop(ctx, n.blockchainEventBacklogReceiveLoop)
+	if n.settings.AuthorityPublicKeys.Contains(n.privateKey.PublicKey()) {
+		n.startLoop(ctx, n.blockBuildLoop)
+	} else {
+		n.log.Info().Msg("Node key is not network authority. Blocks will not be built.")
+	}
+	n.startLoop(ctx, n.localBlockBacklogSendLoop)
 
 	if err := n.blockBlockchainBacklogReceiver.Start(ctx); err != nil {
 		return errors.Wrap(err, "unable to start blockchain block backlog receiver")
@@ -92,6 +176,21 @@ func (n *Network) Start(ctx context.Context) error {
 	return nil
 }
 
+// Wait blocks until all loops of network finished. Loops finish, when context passed to Start is done.
+func (n *Network) Wait() {
+	n.loops.Wait()
+	n.blockBlockchainBacklogReceiver.Wait()
+}
+
+func (n *Network) startLoop(ctx context.Context, loop func(ctx context.Context)) {
+	n.loops.Add(1)
+
+	go func() {
+		defer n.loops.Done()
+		loop(ctx)
+	}()
+}
+
 func (n *Network) LocalEventBacklog() *LocalEventBacklog {
 	return n.localEventBacklog
 }
@@ -101,14 +200,17 @@ func (n *Network) EventEmitter() *EventEmitter {
 }
 
 func (n *Network) localEventBacklogSendLoop(ctx context.Context) {
-	for range time.NewTicker(time.Millisecond * 100).C {
+	ticker := time.NewTicker(time.Millisecond * 100)
+	defer ticker.Stop()
+
+	for waitForTick(ctx, ticker) {
 		for eventId, event := range n.localEventBacklog.Unsent() {
 			log := n.log.With().
 				Str("eventData", event.String()).
 				Str("eventId", eventId.String()).
 				Logger()
 
-			err := n.connector.SendEventToBacklog(event)
+			err := n.connector.SendEventToBacklog(ctx, event)
 			if err != nil {
 				log.Warn().Err(err).Msg("Unable to send event from local backlog to blockchain backlog.")
 				continue
@@ -123,12 +225,17 @@ func (n *Network) localEventBacklogSendLoop(ctx context.Context) {
 			log.Debug().Msg("Event sent from local backlog to blockchain backlog.")
 		}
 	}
-
 }
 
 func (n *Network) blockchainEventBacklogReceiveLoop(ctx context.Context) {
-	for range time.NewTicker(time.Millisecond * 100).C {
+	ticker := time.NewTicker(time.Millisecond * 100)
+	defer ticker.Stop()
+
+	for waitForTick(ctx, ticker) {
 		blockchainEvent, err := n.connector.GetBacklogEvent(ctx)
+		if ctx.Err() != nil {
+			return
+		}
 		if err != nil {
 			log.Warn().Err(err).Msg("Unable to read event from blockchain backlog.")
 			continue
@@ -164,62 +271,114 @@ func (n *Network) blockchainEventBacklogReceiveLoop(ctx context.Context) {
 	}
 }
 
+// blockBuildLoop builds blocks in slots scheduled for this node. After block is proposed, node waits until it is
+// accepted or until its slot ends, then next slot is calculated from new tip of chain.
 func (n *Network) blockBuildLoop(ctx context.Context) {
-	for {
+	select {
+	case <-ctx.Done():
+		return
+	case <-n.blockBlockchainBacklogReceiver.Synchronized():
+	}
+
+	publicKey := n.privateKey.PublicKey()
+
+	// retryTicker delays next attempt after error, so failing storage or builder does not spin the loop.
+	retryTicker := time.NewTicker(time.Millisecond * 100)
+	defer retryTicker.Stop()
+
+	for ctx.Err() == nil {
 		lastBlock, err := n.blockStorage.GetLatestBlock()
 		if err != nil {
 			log.Warn().Err(err).Msg("Error while reading last block from storage.")
+			waitForTick(ctx, retryTicker)
+			continue
+		}
+
+		lastBlockId, err := NewBlockId(lastBlock)
+		if err != nil {
+			log.Warn().Err(err).Msg("Unable to generate last block id.")
+			waitForTick(ctx, retryTicker)
 			continue
 		}
 
-		lastBlockTimestamp := CreateBlockTimestampFromUnixMilliseconds(lastBlock.Body.Timestamp)
+		tipChanged := func() bool {
+			latestBlock, err := n.blockStorage.GetLatestBlock()
+			if err != nil {
+				return true
+			}
+
+			latestBlockId, err := NewBlockId(latestBlock)
+
+			return err != nil || *latestBlockId != *lastBlockId
+		}
+
+		slotStart, slotEnd, err := n.proposerSchedule.NextSlot(publicKey, lastBlock, CreateBlockTimestampFromNow())
+		if err != nil {
+			log.Error().Err(err).Msg("Unable to calculate proposer slot. Blocks will not be built.")
+			return
+		}
 
-		blockTimestamp, err := n.blockTicker.WaitForNext(ctx, lastBlockTimestamp)
+		blockTimestamp, err := n.blockTicker.WaitForSlot(ctx, slotStart, slotEnd, tipChanged)
+		if ctx.Err() != nil {
+			return
+		}
 		if err != nil {
-			log.Warn().Err(err).Msg("Error while calculating next block timestamp.")
+			log.Warn().Err(err).Msg("Error while waiting for proposer slot.")
+			waitForTick(ctx, retryTicker)
+			continue
+		}
+
+		if blockTimestamp == nil {
 			continue
 		}
 
+		n.networkEventBacklog.RemoveExpired(*blockTimestamp)
+
 		events := []*blockchainProtocol.Event{}
 
 		for _, event := range n.networkEventBacklog.Unconfirmed() {
 			events = append(events, event)
 		}
 
-		blockBuilder := NewBlockBuilder(lastBlock, events)
+		blockBuilder := NewBlockBuilder(lastBlock, events, n.eventValidator, n.stateHasher, n.privateKey, n.settings)
 
-		newBlock, err := blockBuilder.Build(*blockTimestamp)
+		newBlock, err := blockBuilder.Build(ctx, *blockTimestamp)
 		if err != nil {
 			log.Warn().Err(err).Msg("Unable to build block.")
+			waitForTick(ctx, retryTicker)
 			continue
 		}
 
-		blockId, err := n.localBlockBacklog.Add(*newBlock)
+		blockId, err := n.localBlockBacklog.Add(newBlock)
 		if err != nil {
 			log.Warn().Err(err).Msg("Unable to add block to local backlog.")
+			waitForTick(ctx, retryTicker)
 			continue
 		}
 
-		backOffDuration := n.settings.BlockInterval - time.Second
-
 		log.Info().
 			Str("blockId", blockId.String()).
 			Uint64("blockTimestamp", blockTimestamp.UnixMilliseconds()).
-			Dur("backOffDuration", backOffDuration).
-			Msg("Block added to local backlog. Backing off.")
+			Uint64("slotEnd", slotEnd.UnixMilliseconds()).
+			Msg("Block added to local backlog. Waiting for block acceptance.")
 
-		time.Sleep(backOffDuration)
+		if _, err := n.blockTicker.WaitForSlot(ctx, slotEnd, slotEnd, tipChanged); err != nil && ctx.Err() == nil {
+			log.Warn().Err(err).Msg("Error while waiting for block acceptance.")
+		}
 	}
 }
 
 func (n *Network) localBlockBacklogSendLoop(ctx context.Context) {
-	for range time.NewTicker(time.Millisecond * 100).C {
+	ticker := time.NewTicker(time.Millisecond * 100)
+	defer ticker.Stop()
+
+	for waitForTick(ctx, ticker) {
 		for blockId, block := range n.localBlockBacklog.Unsent() {
 			log := n.log.With().
 				Str("blockId", blockId.String()).
 				Logger()
 
-			err := n.connector.SendBlockToBacklog(block)
+			err := n.connector.SendBlockToBacklog(ctx, block)
 			if err != nil {
 				log.Warn().Err(err).Msg("Unable to send block from local backlog to blockchain backlog.")
 				continue
@@ -235,3 +394,13 @@ func (n *Network) localBlockBacklogSendLoop(ctx context.Context) {
 		}
 	}
 }
+
+// waitForTick waits for next tick of ticker. False is returned, when context is done first.
+func waitForTick(ctx context.Context, ticker *time.Ticker) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-ticker.C:
+		return true
+	}
+}
@@ -0,0 +1,26 @@
+package network
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"github.com/dominati-one/backend/internal/pkg/security"
+)
+
+// devAuthoritySeed is publicly known, so anybody can rebuild development authority key and seal blocks with it.
+// Development authority must be used for local development only.
+var devAuthoritySeed = sha256.Sum256([]byte("dominatione testnet authority"))
+
+func CreateDevAuthority() *security.PublicKeysBag {
+	return security.NewPublicKeysBag([]crypto.PublicKey{
+		CreateDevAuthorityPrivateKey().PublicKey(),
+	})
+}
+
+func CreateDevAuthorityPrivateKey() *security.PrivateKey {
+	privateKey, err := security.NewPrivateKeyFromSeed(devAuthoritySeed[:])
+	if err != nil {
+		panic(err)
+	}
+
+	return privateKey
+}
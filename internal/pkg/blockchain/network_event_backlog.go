@@ -15,17 +15,30 @@ type networkEventBacklogItem struct {
 }
 
 type NetworkEventBacklog struct {
-	log            zerolog.Logger
-	eventValidator *EventValidator
-	state          sync.Mutex
-	events         map[EventId]*networkEventBacklogItem
+	log               zerolog.Logger
+	eventValidator    *EventValidator
+	confirmationDepth int
+	maxEvents         int
+	state             sync.Mutex
+	events            map[EventId]*networkEventBacklogItem
+	// unconfirmed indexes events waiting for block, so block builder does not scan whole backlog.
+	unconfirmed map[EventId]struct{}
+	// confirmedAt holds height, at which confirmed event was seen by Prune first, or -1 before that.
+	confirmedAt map[EventId]int
+	// nonces indexes nonces of unconfirmed events, so only one event per nonce of signer waits for block.
+	nonces map[eventNonce]EventId
 }
 
-func NewNetworkEventBacklog(eventValidator *EventValidator) *NetworkEventBacklog {
+func NewNetworkEventBacklog(eventValidator *EventValidator, settings NetworkSettings) *NetworkEventBacklog {
 	return &NetworkEventBacklog{
-		log:            log.With().Str("applicationComponent", "blockchain").Str("blockchainComponent", "networkEventBacklog").Logger(),
-		eventValidator: eventValidator,
-		events:         map[EventId]*networkEventBacklogItem{},
+		log:               log.With().Str("applicationComponent", "blockchain").Str("blockchainComponent", "networkEventBacklog").Logger(),
+		eventValidator:    eventValidator,
+		confirmationDepth: settings.backlogConfirmationDepth(),
+		maxEvents:         settings.backlogMaxEvents(),
+		events:            map[EventId]*networkEventBacklogItem{},
+		unconfirmed:       map[EventId]struct{}{},
+		confirmedAt:       map[EventId]int{},
+		nonces:            map[eventNonce]EventId{},
 	}
 }
 
@@ -51,12 +64,36 @@ func (b *NetworkEventBacklog) MarkAsConfirmed(eventId EventId) error {
 			Str("eventData", networkEvent.event.Body.String()).
 			Msg("Network event marked as confirmed.")
 
+		if !networkEvent.confirmed {
+			b.confirmedAt[eventId] = -1
+		}
+
 		networkEvent.confirmed = true
+		b.removeUnconfirmed(eventId)
 	}
 
 	return nil
 }
 
+// Requeue returns event of orphaned block back to backlog as unconfirmed, so it can be included in another block.
+func (b *NetworkEventBacklog) Requeue(eventId EventId, networkEvent *blockchain.Event) {
+	defer b.state.Unlock()
+	b.state.Lock()
+
+	if item, exists := b.events[eventId]; exists {
+		item.confirmed = false
+	} else {
+		b.events[eventId] = &networkEventBacklogItem{
+			event:     networkEvent,
+			confirmed: false,
+		}
+	}
+	b.addUnconfirmed(eventId)
+	delete(b.confirmedAt, eventId)
+
+	b.log.Debug().Str("eventId", eventId.String()).Msg("Event requeued to network backlog.")
+}
+
 // Unconfirmed returns map with unconfirmed events. Events are copy of original backlog item.
 func (b *NetworkEventBacklog) Unconfirmed() map[EventId]*blockchain.Event {
 	defer b.state.Unlock()
@@ -64,15 +101,75 @@ func (b *NetworkEventBacklog) Unconfirmed() map[EventId]*blockchain.Event {
 
 	unconfirmed := map[EventId]*blockchain.Event{}
 
-	for eventId, event := range b.events {
-		if event.confirmed {
+	for eventId := range b.unconfirmed {
+		unconfirmed[eventId] = proto.Clone(b.events[eventId].event).(*blockchain.Event)
+	}
+
+	return unconfirmed
+}
+
+// Prune removes events confirmed at least confirmation depth blocks below given height. Requeued events are never
+// pruned, until they are confirmed again.
+func (b *NetworkEventBacklog) Prune(height int) {
+	defer b.state.Unlock()
+	b.state.Lock()
+
+	prunedCount := 0
+
+	for eventId, confirmedAt := range b.confirmedAt {
+		if confirmedAt < 0 {
+			b.confirmedAt[eventId] = height
+			confirmedAt = height
+		}
+
+		if height-confirmedAt < b.confirmationDepth {
 			continue
 		}
 
-		unconfirmed[eventId] = proto.Clone(event.event).(*blockchain.Event)
+		delete(b.events, eventId)
+		delete(b.confirmedAt, eventId)
+		prunedCount++
 	}
 
-	return unconfirmed
+	if prunedCount > 0 {
+		b.log.Debug().Int("prunedEventsCount", prunedCount).Int("height", height).Msg("Confirmed events pruned from network backlog.")
+	}
+}
+
+// RemoveExpired removes unconfirmed events, which expire before given block timestamp and can not be included in
+// block anymore.
+func (b *NetworkEventBacklog) RemoveExpired(timestamp BlockTimestamp) {
+	defer b.state.Unlock()
+	b.state.Lock()
+
+	for eventId := range b.unconfirmed {
+		if b.events[eventId].event.Body.GetValidUntil() >= timestamp.UnixMilliseconds() {
+			continue
+		}
+
+		b.log.Debug().Str("eventId", eventId.String()).Msg("Expired event removed from network backlog.")
+
+		b.removeUnconfirmed(eventId)
+		delete(b.events, eventId)
+	}
+}
+
+// addUnconfirmed indexes event as unconfirmed. Lock must be held.
+func (b *NetworkEventBacklog) addUnconfirmed(eventId EventId) {
+	b.unconfirmed[eventId] = struct{}{}
+
+	if nonce, err := newEventNonce(b.events[eventId].event); err == nil {
+		b.nonces[nonce] = eventId
+	}
+}
+
+// removeUnconfirmed removes event from unconfirmed indexes. Lock must be held.
+func (b *NetworkEventBacklog) removeUnconfirmed(eventId EventId) {
+	delete(b.unconfirmed, eventId)
+
+	if nonce, err := newEventNonce(b.events[eventId].event); err == nil && b.nonces[nonce] == eventId {
+		delete(b.nonces, nonce)
+	}
 }
 
 func (b *NetworkEventBacklog) All() map[EventId]*blockchain.Event {
@@ -111,10 +208,24 @@ func (b *NetworkEventBacklog) Add(networkEvent *blockchain.Event) error {
 		return errors.Wrap(ErrNetworkBacklogEventAlreadyExists, "unable to add event to network backlog")
 	}
 
+	nonce, err := newEventNonce(networkEvent)
+	if err != nil {
+		return errors.Wrap(err, "unable to add event to network backlog")
+	}
+
+	if _, exists := b.nonces[nonce]; exists {
+		return errors.Wrap(ErrNetworkBacklogNonceAlreadyUsed, "unable to add event to network backlog")
+	}
+
+	if len(b.events) >= b.maxEvents {
+		return errors.Wrap(ErrNetworkBacklogEventsLimitReached, "unable to add event to network backlog")
+	}
+
 	b.events[eventId] = &networkEventBacklogItem{
 		event:     networkEvent,
 		confirmed: false,
 	}
+	b.addUnconfirmed(eventId)
 
 	log.Debug().Str("eventId", eventId.String()).Msg("Event added to network backlog.")
 
@@ -123,4 +234,6 @@ func (b *NetworkEventBacklog) Add(networkEvent *blockchain.Event) error {
 
 var (
 	ErrNetworkBacklogEventAlreadyExists = errors.New("network backlog event already exists")
+	ErrNetworkBacklogEventsLimitReached = errors.New("network backlog events limit reached")
+	ErrNetworkBacklogNonceAlreadyUsed   = errors.New("network backlog nonce already used")
 )
@@ -2,25 +2,31 @@ package blockchain
 
 import (
 	"github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestNetworkEventBacklog_Add(t *testing.T) {
 	var err error
 
-	eventBacklog := NewNetworkEventBacklog(NewEventValidator())
+	eventBacklog := NewNetworkEventBacklog(NewEventValidator(nil, NetworkSettings{}), NetworkSettings{})
 
 	err = eventBacklog.Add(&blockchain.Event{})
+	assert.Error(t, err)
+
+	err = eventBacklog.Add(createSignedEvent(t, &blockchain.EventCreatePlanet{}))
 	assert.NoError(t, err)
 }
 
 func TestNetworkEventBacklog_MarkAsConfirmed(t *testing.T) {
 	var err error
 
-	eventBacklog := NewNetworkEventBacklog(NewEventValidator())
+	eventBacklog := NewNetworkEventBacklog(NewEventValidator(nil, NetworkSettings{}), NetworkSettings{})
 
-	event := &blockchain.Event{}
+	event := createSignedEvent(t, &blockchain.EventCreatePlanet{})
 	eventId := MustEventId(event)
 
 	err = eventBacklog.Add(event)
@@ -35,3 +41,52 @@ func TestNetworkEventBacklog_MarkAsConfirmed(t *testing.T) {
 	unconfirmedEvents = eventBacklog.Unconfirmed()
 	assert.Empty(t, unconfirmedEvents)
 }
+
+func TestNetworkEventBacklog_Prune(t *testing.T) {
+	eventBacklog := NewNetworkEventBacklog(NewEventValidator(nil, NetworkSettings{}), NetworkSettings{BacklogConfirmationDepth: 2})
+
+	event := createSignedEvent(t, &blockchain.EventCreatePlanet{})
+	eventId := MustEventId(event)
+
+	assert.NoError(t, eventBacklog.Add(event))
+	assert.NoError(t, eventBacklog.MarkAsConfirmed(*eventId))
+
+	eventBacklog.Prune(5)
+	assert.True(t, eventBacklog.Exists(*eventId))
+
+	eventBacklog.Prune(6)
+	assert.True(t, eventBacklog.Exists(*eventId))
+
+	eventBacklog.Prune(7)
+	assert.False(t, eventBacklog.Exists(*eventId))
+}
+
+func TestNetworkEventBacklog_AddLimitReached(t *testing.T) {
+	eventBacklog := NewNetworkEventBacklog(NewEventValidator(nil, NetworkSettings{}), NetworkSettings{BacklogMaxEvents: 1})
+
+	assert.NoError(t, eventBacklog.Add(createSignedEvent(t, &blockchain.EventCreatePlanet{})))
+	assert.Equal(t, ErrNetworkBacklogEventsLimitReached, errors.Cause(eventBacklog.Add(createSignedEvent(t, &blockchain.EventCreatePlanet{}))))
+}
+
+func TestNetworkEventBacklog_RemoveExpired(t *testing.T) {
+	eventBacklog := NewNetworkEventBacklog(NewEventValidator(nil, NetworkSettings{}), NetworkSettings{})
+	privateKey := testPrivateKey(t)
+
+	event := createSignedEventWithKey(t, privateKey, &blockchain.EventCreatePlanet{})
+	eventId := MustEventId(event)
+	assert.NoError(t, eventBacklog.Add(event))
+
+	replayedEvent := proto.Clone(event).(*blockchain.Event)
+	replayedEvent.Body.GetCreatePlanet().Seed = 1
+	err := eventBacklog.Add(signTestEvent(t, privateKey, replayedEvent))
+	assert.Equal(t, ErrNetworkBacklogNonceAlreadyUsed, errors.Cause(err))
+
+	eventBacklog.RemoveExpired(CreateBlockTimestampFromNow())
+	assert.True(t, eventBacklog.Exists(*eventId))
+
+	eventBacklog.RemoveExpired(CreateBlockTimestampFromNow().Add(DefaultEventMaxValidity + time.Second))
+	assert.False(t, eventBacklog.Exists(*eventId))
+	assert.Empty(t, eventBacklog.Unconfirmed())
+
+	assert.NoError(t, eventBacklog.Add(replayedEvent))
+}
@@ -0,0 +1,75 @@
+package p2p
+
+import (
+	"errors"
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+)
+
+type testBlockStorage struct {
+	blocks []*blockchainProtocol.Block
+}
+
+func newTestBlockStorage() *testBlockStorage {
+	return &testBlockStorage{
+		blocks: []*blockchainProtocol.Block{{Body: &blockchainProtocol.Block_Body{}}},
+	}
+}
+
+func (s *testBlockStorage) Count() int {
+	return len(s.blocks)
+}
+
+func (s *testBlockStorage) Add(block *blockchainProtocol.Block) (*blockchain.BlockId, error) {
+	s.blocks = append(s.blocks, block)
+
+	return blockchain.NewBlockId(block)
+}
+
+func (s *testBlockStorage) Get(blockId blockchain.BlockId) (*blockchainProtocol.Block, error) {
+	for _, block := range s.blocks {
+		if id, _ := blockchain.NewBlockId(block); *id == blockId {
+			return block, nil
+		}
+	}
+
+	return nil, errTestBlockNotFound
+}
+
+func (s *testBlockStorage) GetByHeight(height int) (*blockchainProtocol.Block, error) {
+	if height < 0 || height >= len(s.blocks) {
+		return nil, errTestBlockNotFound
+	}
+
+	return s.blocks[height], nil
+}
+
+func (s *testBlockStorage) GetLatestBlock() (*blockchainProtocol.Block, error) {
+	return s.blocks[len(s.blocks)-1], nil
+}
+
+func (s *testBlockStorage) GetHeight(blockId blockchain.BlockId) (int, error) {
+	for height, block := range s.blocks {
+		if id, _ := blockchain.NewBlockId(block); *id == blockId {
+			return height, nil
+		}
+	}
+
+	return 0, errTestBlockNotFound
+}
+
+func (s *testBlockStorage) Truncate(height int) error {
+	s.blocks = s.blocks[:height+1]
+
+	return nil
+}
+
+func (s *testBlockStorage) Exists(blockId blockchain.BlockId) bool {
+	_, err := s.Get(blockId)
+
+	return err == nil
+}
+
+var (
+	errTestBlockNotFound = errors.New("test block not found")
+)
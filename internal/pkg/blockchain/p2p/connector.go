@@ -0,0 +1,277 @@
+package p2p
+
+import (
+	"context"
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/dominati-one/backend/pkg/protocol/p2p"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultReconnectMinBackoff = 100 * time.Millisecond
+	DefaultReconnectMaxBackoff = 10 * time.Second
+	DefaultPeerQueueSize       = 1024
+	DefaultSeenCacheSize       = 65536
+	MaxGetBlocksCount          = 100
+)
+
+type ConnectorSettings struct {
+	// ListenAddress is host:port where gossip from other peers is accepted.
+	ListenAddress string
+	// Peers are host:port addresses of peers, which are dialed and receive gossip from this node.
+	Peers               []string
+	ReconnectMinBackoff time.Duration
+	ReconnectMaxBackoff time.Duration
+	PeerQueueSize       int
+	SeenCacheSize       int
+}
+
+// Connector gossips events and block proposals between nodes over gRPC. Every valid message seen for the first time
+// is put to local backlog and forwarded to all peers. Messages already seen or invalid are dropped, so gossip
+// does not loop and invalid messages do not spread over network.
+type Connector struct {
+	log            zerolog.Logger
+	settings       ConnectorSettings
+	blockStorage   blockchain.BlockStorage
+	eventValidator *blockchain.EventValidator
+	blockValidator *blockchain.BlockValidator
+
+	eventBacklog chan *blockchainProtocol.Event
+	blockBacklog chan *blockchainProtocol.Block
+
+	seenMutex sync.Mutex
+	seen      *seenCache
+
+	peers  []*peer
+	socket net.Listener
+	server *grpc.Server
+	loops  sync.WaitGroup
+}
+
+// NewConnector creates connector. Block storage is used to serve blocks to peers, which are synchronizing chain.
+// Validators check only signatures and structure of messages, chain and game state are checked by network.
+func NewConnector(settings ConnectorSettings, blockStorage blockchain.BlockStorage, eventValidator *blockchain.EventValidator, blockValidator *blockchain.BlockValidator) *Connector {
+	if settings.ReconnectMinBackoff == 0 {
+		settings.ReconnectMinBackoff = DefaultReconnectMinBackoff
+	}
+	if settings.ReconnectMaxBackoff == 0 {
+		settings.ReconnectMaxBackoff = DefaultReconnectMaxBackoff
+	}
+	if settings.PeerQueueSize == 0 {
+		settings.PeerQueueSize = DefaultPeerQueueSize
+	}
+	if settings.SeenCacheSize == 0 {
+		settings.SeenCacheSize = DefaultSeenCacheSize
+	}
+
+	c := &Connector{
+		log:            log.With().Str("applicationComponent", "blockchain").Str("blockchainComponent", "p2pConnector").Logger(),
+		settings:       settings,
+		blockStorage:   blockStorage,
+		eventValidator: eventValidator,
+		blockValidator: blockValidator,
+		eventBacklog:   make(chan *blockchainProtocol.Event, 2048),
+		blockBacklog:   make(chan *blockchainProtocol.Block, 64),
+		seen:           newSeenCache(settings.SeenCacheSize),
+	}
+
+	for _, address := range settings.Peers {
+		c.peers = append(c.peers, newPeer(address, settings, c.log))
+	}
+
+	return c
+}
+
+// Start listens for gossip from other peers and starts dialing configured peers.
+func (c *Connector) Start(ctx context.Context) error {
+	socket, err := net.Listen("tcp", c.settings.ListenAddress)
+	if err != nil {
+		return errors.Wrap(err, "unable to start peer listener")
+	}
+
+	c.socket = socket
+	c.server = grpc.NewServer()
+
+	p2p.RegisterPeerServer(c.server, newPeerServer(c))
+
+	c.loops.Add(1 + len(c.peers))
+
+	go func() {
+		defer c.loops.Done()
+
+		if err := c.server.Serve(socket); err != nil {
+			c.log.Error().Err(err).Msg("Peer server stopped.")
+		}
+	}()
+
+	for _, connectorPeer := range c.peers {
+		go func(connectorPeer *peer) {
+			defer c.loops.Done()
+			connectorPeer.run(ctx)
+		}(connectorPeer)
+	}
+
+	c.log.Info().Str("listenAddress", socket.Addr().String()).Int("peersCount", len(c.peers)).Msg("Started.")
+
+	return nil
+}
+
+// Stop closes peer listener and all incoming gossip streams. Peers stop dialing, when context passed to Start is done.
+func (c *Connector) Stop() {
+	if c.server != nil {
+		c.server.Stop()
+	}
+
+	for _, peer := range c.peers {
+		peer.close()
+	}
+}
+
+// Wait blocks until peer server and all peers finished. Peers finish, when context passed to Start is done, and server
+// finishes after Stop.
+func (c *Connector) Wait() {
+	c.loops.Wait()
+}
+
+// Addr returns address peer listener is bound to.
+func (c *Connector) Addr() net.Addr {
+	return c.socket.Addr()
+}
+
+func (c *Connector) SendEventToBacklog(ctx context.Context, backlogEvent *blockchainProtocol.Event) error {
+	return c.handleEvent(ctx, proto.Clone(backlogEvent).(*blockchainProtocol.Event))
+}
+
+func (c *Connector) SendBlockToBacklog(ctx context.Context, backlogBlock *blockchainProtocol.Block) error {
+	return c.handleBlock(ctx, proto.Clone(backlogBlock).(*blockchainProtocol.Block))
+}
+
+func (c *Connector) GetBacklogBlock(ctx context.Context) (*blockchainProtocol.Block, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ErrCanceledReadBacklogP2PConnector
+	case backlogBlock := <-c.blockBacklog:
+		return backlogBlock, nil
+	}
+}
+
+func (c *Connector) GetBacklogEvent(ctx context.Context) (*blockchainProtocol.Event, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ErrCanceledReadBacklogP2PConnector
+	case backlogEvent := <-c.eventBacklog:
+		return backlogEvent, nil
+	}
+}
+
+// GetPeerTips asks all peers for their latest block. Unreachable peers are skipped.
+func (c *Connector) GetPeerTips(ctx context.Context) ([]blockchain.PeerTip, error) {
+	tips := []blockchain.PeerTip{}
+
+	for _, peer := range c.peers {
+		tip, err := peer.getTip(ctx)
+		if err != nil {
+			c.log.Debug().Err(err).Str("peerAddress", peer.address).Msg("Unable to get peer tip.")
+			continue
+		}
+
+		tips = append(tips, *tip)
+	}
+
+	return tips, nil
+}
+
+func (c *Connector) GetBlocks(ctx context.Context, peerAddress string, fromHeight int, count int) ([]*blockchainProtocol.Block, error) {
+	for _, peer := range c.peers {
+		if peer.address == peerAddress {
+			return peer.getBlocks(ctx, fromHeight, count)
+		}
+	}
+
+	return nil, ErrP2PConnectorUnknownPeer
+}
+
+// handleEvent puts event to backlog and forwards it to peers. Event is dropped, when its signature or structure is
+// invalid or it was seen already. Only valid event is marked as seen, so event rejected for starting slightly ahead
+// of local clock is accepted, when it arrives again. Full backlog is waited for until context is done.
+func (c *Connector) handleEvent(ctx context.Context, event *blockchainProtocol.Event) error {
+	eventId, err := blockchain.NewEventId(event)
+	if err != nil {
+		return errors.Wrap(err, "unable to calculate event id")
+	}
+
+	if err := c.eventValidator.ValidateAt(event, blockchain.CreateBlockTimestampFromNow()); err != nil {
+		return errors.Wrapf(err, "invalid event %s", eventId)
+	}
+
+	if !c.markAsSeen(eventId) {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ErrCanceledWriteBacklogP2PConnector
+	case c.eventBacklog <- event:
+	}
+
+	c.broadcast(&p2p.GossipMessage{Payload: &p2p.GossipMessage_Event{Event: event}})
+
+	return nil
+}
+
+// handleBlock puts block proposal to backlog and forwards it to peers. Block is dropped, when its seal or structure is
+// invalid or it was seen already. Only valid block is marked as seen. Full backlog is waited for until context is
+// done.
+func (c *Connector) handleBlock(ctx context.Context, block *blockchainProtocol.Block) error {
+	blockId, err := blockchain.NewBlockId(block)
+	if err != nil {
+		return errors.Wrap(err, "unable to calculate block id")
+	}
+
+	if err := c.blockValidator.ValidateIntegrity(block); err != nil {
+		return errors.Wrapf(err, "invalid block %s", blockId)
+	}
+
+	if !c.markAsSeen(*blockId) {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ErrCanceledWriteBacklogP2PConnector
+	case c.blockBacklog <- block:
+	}
+
+	c.broadcast(&p2p.GossipMessage{Payload: &p2p.GossipMessage_Block{Block: block}})
+
+	return nil
+}
+
+// markAsSeen returns false, when message with given id was already seen.
+func (c *Connector) markAsSeen(id [32]byte) bool {
+	defer c.seenMutex.Unlock()
+	c.seenMutex.Lock()
+
+	return c.seen.add(id)
+}
+
+func (c *Connector) broadcast(message *p2p.GossipMessage) {
+	for _, peer := range c.peers {
+		peer.enqueue(message)
+	}
+}
+
+var (
+	ErrCanceledReadBacklogP2PConnector  = errors.New("canceled read backlog p2p connector")
+	ErrCanceledWriteBacklogP2PConnector = errors.New("canceled write backlog p2p connector")
+	ErrP2PConnectorEmptyMessage         = errors.New("p2p connector empty message")
+	ErrP2PConnectorUnknownPeer          = errors.New("p2p connector unknown peer")
+)
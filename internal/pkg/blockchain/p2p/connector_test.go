@@ -0,0 +1,259 @@
+package p2p
+
+import (
+	"context"
+	"crypto"
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	"github.com/dominati-one/backend/internal/pkg/security"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestConnector_Gossip(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	addresses := []string{freeAddress(t), freeAddress(t), freeAddress(t)}
+
+	authorityKey := testPrivateKey(t)
+
+	// Nodes are connected in line, so messages reach last node only through middle one.
+	connectors := []*Connector{
+		newTestConnector(t, ConnectorSettings{ListenAddress: addresses[0], Peers: []string{addresses[1]}}, newTestBlockStorage(), authorityKey),
+		newTestConnector(t, ConnectorSettings{ListenAddress: addresses[1], Peers: []string{addresses[0], addresses[2]}}, newTestBlockStorage(), authorityKey),
+		newTestConnector(t, ConnectorSettings{ListenAddress: addresses[2], Peers: []string{addresses[1]}}, newTestBlockStorage(), authorityKey),
+	}
+
+	// Last node starts later, so middle node has to reconnect.
+	for _, connector := range connectors[:2] {
+		assert.NoError(t, connector.Start(ctx))
+		defer connector.Stop()
+	}
+
+	event := createTestEvent(t)
+	block := createTestBlock(t, authorityKey)
+
+	assert.NoError(t, connectors[0].SendEventToBacklog(ctx, event))
+
+	time.Sleep(200 * time.Millisecond)
+	assert.NoError(t, connectors[2].Start(ctx))
+	defer connectors[2].Stop()
+
+	assert.NoError(t, connectors[0].SendBlockToBacklog(ctx, block))
+
+	for _, connector := range connectors {
+		receiveCtx, receiveCancel := context.WithTimeout(ctx, 5*time.Second)
+
+		receivedEvent, err := connector.GetBacklogEvent(receiveCtx)
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(event, receivedEvent))
+
+		receivedBlock, err := connector.GetBacklogBlock(receiveCtx)
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(block, receivedBlock))
+
+		receiveCancel()
+	}
+
+	// Duplicates sent back by peers are dropped.
+	assert.NoError(t, connectors[2].SendEventToBacklog(ctx, event))
+
+	for _, connector := range connectors {
+		receiveCtx, receiveCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+
+		_, err := connector.GetBacklogEvent(receiveCtx)
+		assert.ErrorIs(t, err, ErrCanceledReadBacklogP2PConnector)
+
+		receiveCancel()
+	}
+}
+
+func TestConnector_GossipInvalid(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	addresses := []string{freeAddress(t), freeAddress(t)}
+	authorityKey := testPrivateKey(t)
+
+	connectors := []*Connector{
+		newTestConnector(t, ConnectorSettings{ListenAddress: addresses[0], Peers: []string{addresses[1]}}, newTestBlockStorage(), authorityKey),
+		newTestConnector(t, ConnectorSettings{ListenAddress: addresses[1], Peers: []string{addresses[0]}}, newTestBlockStorage(), authorityKey),
+	}
+
+	for _, connector := range connectors {
+		assert.NoError(t, connector.Start(ctx))
+		defer connector.Stop()
+	}
+
+	unsignedEvent := createTestEvent(t)
+	unsignedEvent.Signature = nil
+	err := connectors[0].SendEventToBacklog(ctx, unsignedEvent)
+	assert.Equal(t, blockchain.ErrEventValidatorInvalidSignature, errors.Cause(err))
+
+	foreignBlock := createTestBlock(t, testPrivateKey(t))
+	err = connectors[0].SendBlockToBacklog(ctx, foreignBlock)
+	assert.Equal(t, blockchain.ErrBlockValidatorUnknownAuthority, errors.Cause(err))
+
+	// Invalid messages are neither put to backlog nor forwarded.
+	for _, connector := range connectors {
+		receiveCtx, receiveCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+
+		_, err := connector.GetBacklogEvent(receiveCtx)
+		assert.ErrorIs(t, err, ErrCanceledReadBacklogP2PConnector)
+
+		_, err = connector.GetBacklogBlock(receiveCtx)
+		assert.ErrorIs(t, err, ErrCanceledReadBacklogP2PConnector)
+
+		receiveCancel()
+	}
+}
+
+func TestConnector_GossipRejectedEventAgain(t *testing.T) {
+	connector := newTestConnector(t, ConnectorSettings{}, newTestBlockStorage(), testPrivateKey(t))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+	defer cancel()
+
+	futureEvent := createTestEvent(t)
+	futureEvent.Body.ValidFrom = blockchain.CreateBlockTimestampFromNow().Add(time.Minute).UnixMilliseconds()
+	signature, err := security.CreateSignatureFromBody(futureEvent.Body, testPrivateKey(t))
+	assert.NoError(t, err)
+	futureEvent.Signature = signature.Bytes()
+
+	err = connector.SendEventToBacklog(ctx, futureEvent)
+	assert.Equal(t, blockchain.ErrEventValidatorFutureEvent, errors.Cause(err))
+
+	// Rejected event is not remembered as seen, so it is accepted, once it becomes valid.
+	connector.eventValidator = blockchain.NewEventValidator(nil, blockchain.NetworkSettings{BlockMaxClockDrift: time.Hour})
+	assert.NoError(t, connector.SendEventToBacklog(ctx, futureEvent))
+
+	backlogEvent, err := connector.GetBacklogEvent(ctx)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(futureEvent, backlogEvent))
+}
+
+func TestConnector_SendToFullBacklog(t *testing.T) {
+	authorityKey := testPrivateKey(t)
+	connector := newTestConnector(t, ConnectorSettings{}, newTestBlockStorage(), authorityKey)
+	connector.eventBacklog = make(chan *blockchainProtocol.Event)
+	connector.blockBacklog = make(chan *blockchainProtocol.Block)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, connector.SendEventToBacklog(ctx, createTestEvent(t)), ErrCanceledWriteBacklogP2PConnector)
+	assert.ErrorIs(t, connector.SendBlockToBacklog(ctx, createTestBlock(t, authorityKey)), ErrCanceledWriteBacklogP2PConnector)
+}
+
+func TestSeenCache_Add(t *testing.T) {
+	cache := newSeenCache(2)
+
+	assert.True(t, cache.add([32]byte{1}))
+	assert.False(t, cache.add([32]byte{1}))
+	assert.True(t, cache.add([32]byte{2}))
+	assert.True(t, cache.add([32]byte{3}))
+	assert.True(t, cache.add([32]byte{1}))
+	assert.False(t, cache.add([32]byte{3}))
+}
+
+func freeAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	return listener.Addr().String()
+}
+
+func TestConnector_GetBlocks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	addresses := []string{freeAddress(t), freeAddress(t)}
+
+	blockStorage := newTestBlockStorage()
+	for timestamp := uint64(1); timestamp <= 5; timestamp++ {
+		blockStorage.blocks = append(blockStorage.blocks, &blockchainProtocol.Block{Body: &blockchainProtocol.Block_Body{Timestamp: timestamp}})
+	}
+
+	server := newTestConnector(t, ConnectorSettings{ListenAddress: addresses[0]}, blockStorage, testPrivateKey(t))
+	assert.NoError(t, server.Start(ctx))
+	defer server.Stop()
+
+	client := newTestConnector(t, ConnectorSettings{ListenAddress: addresses[1], Peers: []string{addresses[0], freeAddress(t)}}, newTestBlockStorage(), testPrivateKey(t))
+	assert.NoError(t, client.Start(ctx))
+	defer client.Stop()
+
+	tips, err := client.GetPeerTips(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, tips, 1)
+	assert.Equal(t, addresses[0], tips[0].Peer)
+	assert.Equal(t, 5, tips[0].Height)
+
+	blocks, err := client.GetBlocks(ctx, addresses[0], 3, 10)
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 3)
+	assert.True(t, proto.Equal(blockStorage.blocks[3], blocks[0]))
+
+	_, err = client.GetBlocks(ctx, "127.0.0.1:1", 0, 1)
+	assert.ErrorIs(t, err, ErrP2PConnectorUnknownPeer)
+}
+
+func newTestConnector(t *testing.T, settings ConnectorSettings, blockStorage blockchain.BlockStorage, authorityKey *security.PrivateKey) *Connector {
+	networkSettings := blockchain.NetworkSettings{
+		AuthorityPublicKeys: security.NewPublicKeysBag([]crypto.PublicKey{authorityKey.PublicKey()}),
+	}
+
+	eventValidator := blockchain.NewEventValidator(nil, networkSettings)
+	blockValidator := blockchain.NewBlockValidator(eventValidator, nil, nil, networkSettings)
+
+	return NewConnector(settings, blockStorage, eventValidator, blockValidator)
+}
+
+func createTestEvent(t *testing.T) *blockchainProtocol.Event {
+	now := blockchain.CreateBlockTimestampFromNow()
+
+	event := &blockchainProtocol.Event{
+		Body: &blockchainProtocol.Event_Body{
+			Event:      &blockchainProtocol.Event_Body_CreatePlanet{CreatePlanet: &blockchainProtocol.EventCreatePlanet{}},
+			ValidFrom:  now.UnixMilliseconds(),
+			ValidUntil: now.Add(blockchain.DefaultEventMaxValidity).UnixMilliseconds(),
+			Nonce:      1,
+		},
+		Timestamp: now.UnixMilliseconds(),
+	}
+
+	signature, err := security.CreateSignatureFromBody(event.Body, testPrivateKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	event.Signature = signature.Bytes()
+
+	return event
+}
+
+func createTestBlock(t *testing.T, authorityKey *security.PrivateKey) *blockchainProtocol.Block {
+	previousBlock := &blockchainProtocol.Block{Body: &blockchainProtocol.Block_Body{Timestamp: 1}}
+
+	block, err := blockchain.NewBlockBuilder(previousBlock, nil, nil, nil, authorityKey, blockchain.NetworkSettings{}).Build(context.TODO(), blockchain.CreateBlockTimestampFromNow())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return block
+}
+
+func testPrivateKey(t *testing.T) *security.PrivateKey {
+	privateKey, err := security.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return privateKey
+}
@@ -0,0 +1,175 @@
+package p2p
+
+import (
+	"context"
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/dominati-one/backend/pkg/protocol/p2p"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"sync"
+	"time"
+)
+
+const (
+	peerDialTimeout = 5 * time.Second
+)
+
+// peer keeps outgoing gossip stream to one remote node. Messages are queued while peer is unreachable, stream is
+// reopened with exponential backoff.
+type peer struct {
+	log      zerolog.Logger
+	address  string
+	settings ConnectorSettings
+	queue    chan *p2p.GossipMessage
+	pending  *p2p.GossipMessage
+
+	connectionMutex sync.Mutex
+	connection      *grpc.ClientConn
+}
+
+func newPeer(address string, settings ConnectorSettings, log zerolog.Logger) *peer {
+	return &peer{
+		log:      log.With().Str("peerAddress", address).Logger(),
+		address:  address,
+		settings: settings,
+		queue:    make(chan *p2p.GossipMessage, settings.PeerQueueSize),
+	}
+}
+
+// enqueue adds message to peer queue. Message is dropped, when queue is full, peers catch up on blocks by sync.
+func (p *peer) enqueue(message *p2p.GossipMessage) {
+	select {
+	case p.queue <- message:
+	default:
+		p.log.Warn().Msg("Peer queue is full. Dropping gossip message.")
+	}
+}
+
+// client returns client for unary requests. Connection is shared and reconnected by gRPC itself.
+func (p *peer) client() (p2p.PeerClient, error) {
+	defer p.connectionMutex.Unlock()
+	p.connectionMutex.Lock()
+
+	if p.connection == nil {
+		connection, err := grpc.Dial(p.address, grpc.WithInsecure())
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to dial peer")
+		}
+		p.connection = connection
+	}
+
+	return p2p.NewPeerClient(p.connection), nil
+}
+
+func (p *peer) close() {
+	defer p.connectionMutex.Unlock()
+	p.connectionMutex.Lock()
+
+	if p.connection != nil {
+		_ = p.connection.Close()
+		p.connection = nil
+	}
+}
+
+func (p *peer) getTip(ctx context.Context) (*blockchain.PeerTip, error) {
+	client, err := p.client()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.GetTip(ctx, &p2p.GetTipRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get tip")
+	}
+
+	tip := &blockchain.PeerTip{
+		Peer:   p.address,
+		Height: int(response.Height),
+	}
+	copy(tip.BlockId[:], response.BlockId)
+
+	return tip, nil
+}
+
+func (p *peer) getBlocks(ctx context.Context, fromHeight int, count int) ([]*blockchainProtocol.Block, error) {
+	client, err := p.client()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.GetBlocks(ctx, &p2p.GetBlocksRequest{
+		FromHeight: uint64(fromHeight),
+		Count:      uint32(count),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get blocks")
+	}
+
+	return response.Blocks, nil
+}
+
+func (p *peer) run(ctx context.Context) {
+	backOffDuration := p.settings.ReconnectMinBackoff
+
+	for ctx.Err() == nil {
+		connected, err := p.gossip(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if connected {
+			backOffDuration = p.settings.ReconnectMinBackoff
+		}
+
+		p.log.Warn().Err(err).Dur("backOffDuration", backOffDuration).Msg("Peer connection lost. Backing off.")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backOffDuration):
+		}
+
+		backOffDuration *= 2
+		if backOffDuration > p.settings.ReconnectMaxBackoff {
+			backOffDuration = p.settings.ReconnectMaxBackoff
+		}
+	}
+}
+
+// gossip dials peer and sends queued messages until stream fails. Returns true, when stream was opened.
+func (p *peer) gossip(ctx context.Context) (bool, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, peerDialTimeout)
+	defer cancel()
+
+	connection, err := grpc.DialContext(dialCtx, p.address, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return false, errors.Wrap(err, "unable to dial peer")
+	}
+	defer connection.Close()
+
+	stream, err := p2p.NewPeerClient(connection).Gossip(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "unable to open gossip stream")
+	}
+
+	p.log.Info().Msg("Connected to peer.")
+
+	for {
+		if p.pending == nil {
+			select {
+			case <-ctx.Done():
+				_, _ = stream.CloseAndRecv()
+				return true, ctx.Err()
+			case p.pending = <-p.queue:
+			}
+		}
+
+		if err := stream.Send(p.pending); err != nil {
+			return true, errors.Wrap(err, "unable to send gossip message")
+		}
+
+		p.pending = nil
+	}
+}
@@ -0,0 +1,87 @@
+package p2p
+
+import (
+	"context"
+	"github.com/dominati-one/backend/internal/pkg/blockchain"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/dominati-one/backend/pkg/protocol/p2p"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+)
+
+// peerServer handles requests of remote peers.
+type peerServer struct {
+	p2p.UnimplementedPeerServer
+	connector *Connector
+}
+
+func newPeerServer(connector *Connector) *peerServer {
+	return &peerServer{connector: connector}
+}
+
+// Gossip receives stream of messages from one peer.
+func (s *peerServer) Gossip(stream p2p.Peer_GossipServer) error {
+	var receivedMessages uint64
+
+	for {
+		message, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&p2p.GossipResponse{ReceivedMessages: receivedMessages})
+		}
+		if err != nil {
+			return err
+		}
+
+		receivedMessages++
+
+		if event := message.GetEvent(); event != nil {
+			err = s.connector.handleEvent(stream.Context(), event)
+		} else if block := message.GetBlock(); block != nil {
+			err = s.connector.handleBlock(stream.Context(), block)
+		} else {
+			err = ErrP2PConnectorEmptyMessage
+		}
+
+		if err != nil {
+			s.connector.log.Warn().Err(err).Msg("Unable to handle gossip message.")
+		}
+	}
+}
+
+func (s *peerServer) GetTip(ctx context.Context, request *p2p.GetTipRequest) (*p2p.GetTipResponse, error) {
+	latestBlock, err := s.connector.blockStorage.GetLatestBlock()
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, "unable to read latest block")
+	}
+
+	blockId, err := blockchain.NewBlockId(latestBlock)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "unable to calculate block id")
+	}
+
+	return &p2p.GetTipResponse{
+		Height:  uint64(s.connector.blockStorage.Count() - 1),
+		BlockId: blockId.Bytes(),
+	}, nil
+}
+
+func (s *peerServer) GetBlocks(ctx context.Context, request *p2p.GetBlocksRequest) (*p2p.GetBlocksResponse, error) {
+	count := int(request.Count)
+	if count > MaxGetBlocksCount {
+		count = MaxGetBlocksCount
+	}
+
+	blocks := []*blockchainProtocol.Block{}
+
+	for height := int(request.FromHeight); height < int(request.FromHeight)+count; height++ {
+		block, err := s.connector.blockStorage.GetByHeight(height)
+		if err != nil {
+			break
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	return &p2p.GetBlocksResponse{Blocks: blocks}, nil
+}
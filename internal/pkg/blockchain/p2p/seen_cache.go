@@ -0,0 +1,36 @@
+package p2p
+
+// seenCache remembers ids of recently seen messages. Oldest ids are forgotten, when cache is full.
+type seenCache struct {
+	size  int
+	ids   map[[32]byte]struct{}
+	order [][32]byte
+	next  int
+}
+
+func newSeenCache(size int) *seenCache {
+	return &seenCache{
+		size:  size,
+		ids:   make(map[[32]byte]struct{}, size),
+		order: make([][32]byte, 0, size),
+	}
+}
+
+// add returns false, when id is already in cache.
+func (c *seenCache) add(id [32]byte) bool {
+	if _, exists := c.ids[id]; exists {
+		return false
+	}
+
+	if len(c.order) < c.size {
+		c.order = append(c.order, id)
+	} else {
+		delete(c.ids, c.order[c.next])
+		c.order[c.next] = id
+		c.next = (c.next + 1) % c.size
+	}
+
+	c.ids[id] = struct{}{}
+
+	return true
+}
@@ -0,0 +1,118 @@
+package blockchain
+
+import (
+	"crypto/ed25519"
+	"github.com/dominati-one/backend/internal/pkg/security"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	DefaultBlockSlotTimeout = 5 * time.Second
+)
+
+// ProposerSchedule decides which authority proposes next block. Authorities take turns in order of their keys, turn
+// passes to authority following proposer of previous block. First slot starts with previous block and ends slot
+// timeout after block interval, when scheduled authority missed it, slot falls through to next authority every slot
+// timeout.
+type ProposerSchedule struct {
+	authorities   []ed25519.PublicKey
+	blockInterval time.Duration
+	slotTimeout   time.Duration
+}
+
+func NewProposerSchedule(settings NetworkSettings) *ProposerSchedule {
+	slotTimeout := settings.BlockSlotTimeout
+	if slotTimeout <= 0 {
+		slotTimeout = DefaultBlockSlotTimeout
+	}
+
+	authorities := []ed25519.PublicKey{}
+	if settings.AuthorityPublicKeys != nil {
+		authorities = settings.AuthorityPublicKeys.Keys()
+	}
+
+	return &ProposerSchedule{
+		authorities:   authorities,
+		blockInterval: settings.BlockInterval,
+		slotTimeout:   slotTimeout,
+	}
+}
+
+// Proposer returns authority scheduled to propose block with given timestamp on top of previous block.
+func (s *ProposerSchedule) Proposer(previousBlock *blockchainProtocol.Block, blockTimestamp BlockTimestamp) (ed25519.PublicKey, error) {
+	if len(s.authorities) == 0 {
+		return nil, ErrProposerScheduleNoAuthorities
+	}
+
+	previousTimestamp := CreateBlockTimestampFromUnixMilliseconds(previousBlock.Body.Timestamp)
+
+	slot := s.slot(previousTimestamp, blockTimestamp)
+
+	return s.authorities[(s.firstProposerIndex(previousBlock)+slot)%len(s.authorities)], nil
+}
+
+// NextSlot returns start and end of earliest slot of authority, which did not end before now. Block proposed in
+// that slot on top of previous block gets start of slot or current time, whatever is later, as its timestamp.
+func (s *ProposerSchedule) NextSlot(publicKey ed25519.PublicKey, previousBlock *blockchainProtocol.Block, now BlockTimestamp) (BlockTimestamp, BlockTimestamp, error) {
+	authorityIndex := -1
+	for index, authority := range s.authorities {
+		if authority.Equal(publicKey) {
+			authorityIndex = index
+		}
+	}
+
+	if authorityIndex < 0 {
+		return EmptyBlockTimestamp, EmptyBlockTimestamp, ErrProposerScheduleUnknownAuthority
+	}
+
+	authoritiesCount := len(s.authorities)
+	previousTimestamp := CreateBlockTimestampFromUnixMilliseconds(previousBlock.Body.Timestamp)
+
+	slot := (authorityIndex - s.firstProposerIndex(previousBlock) + authoritiesCount) % authoritiesCount
+	for !now.Before(s.slotEnd(previousTimestamp, slot).Time) {
+		slot += authoritiesCount
+	}
+
+	return s.slotStart(previousTimestamp, slot), s.slotEnd(previousTimestamp, slot), nil
+}
+
+// firstProposerIndex returns index of authority following proposer of previous block. Genesis block is not signed,
+// so first authority proposes block following it.
+func (s *ProposerSchedule) firstProposerIndex(previousBlock *blockchainProtocol.Block) int {
+	signature, err := security.NewSignature(previousBlock.Signature)
+	if err != nil {
+		return 0
+	}
+
+	for index, authority := range s.authorities {
+		if authority.Equal(signature.PublicKey()) {
+			return (index + 1) % len(s.authorities)
+		}
+	}
+
+	return 0
+}
+
+func (s *ProposerSchedule) slot(previousTimestamp BlockTimestamp, blockTimestamp BlockTimestamp) int {
+	elapsed := blockTimestamp.Sub(previousTimestamp.Add(s.blockInterval).Time)
+	if elapsed < s.slotTimeout {
+		return 0
+	}
+
+	return int(elapsed / s.slotTimeout)
+}
+
+func (s *ProposerSchedule) slotStart(previousTimestamp BlockTimestamp, slot int) BlockTimestamp {
+	return previousTimestamp.Add(s.blockInterval + time.Duration(slot)*s.slotTimeout)
+}
+
+func (s *ProposerSchedule) slotEnd(previousTimestamp BlockTimestamp, slot int) BlockTimestamp {
+	return s.slotStart(previousTimestamp, slot+1)
+}
+
+var (
+	ErrProposerScheduleNoAuthorities    = errors.New("proposer schedule no authorities")
+	ErrProposerScheduleUnknownAuthority = errors.New("proposer schedule unknown authority")
+)
@@ -0,0 +1,128 @@
+package blockchain
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"github.com/dominati-one/backend/internal/pkg/security"
+	"github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestProposerSchedule_Proposer(t *testing.T) {
+	authorityKeys := testAuthorityKeys(t, 3)
+	schedule := NewProposerSchedule(testScheduleSettings(authorityKeys))
+	authorities := schedule.authorities
+
+	genesisBlock := testGenesisBlock()
+	genesisTimestamp := CreateBlockTimestampFromUnixMilliseconds(genesisBlock.Body.Timestamp)
+
+	proposer, err := schedule.Proposer(genesisBlock, genesisTimestamp.Add(10*time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, authorities[0], proposer)
+
+	proposer, err = schedule.Proposer(genesisBlock, genesisTimestamp.Add(15*time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, authorities[1], proposer)
+
+	proposer, err = schedule.Proposer(genesisBlock, genesisTimestamp.Add(31*time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, authorities[1], proposer)
+
+	block := buildTestBlockAt(t, genesisBlock, []*blockchain.Event{}, testAuthorityKey(t, authorityKeys, authorities[1]), 10*time.Second)
+	blockTimestamp := CreateBlockTimestampFromUnixMilliseconds(block.Body.Timestamp)
+
+	proposer, err = schedule.Proposer(block, blockTimestamp.Add(10*time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, authorities[2], proposer)
+
+	_, err = NewProposerSchedule(NetworkSettings{AuthorityPublicKeys: security.NewPublicKeysBag(nil)}).Proposer(genesisBlock, blockTimestamp)
+	assert.ErrorIs(t, err, ErrProposerScheduleNoAuthorities)
+}
+
+func TestProposerSchedule_NextSlot(t *testing.T) {
+	authorityKeys := testAuthorityKeys(t, 3)
+	schedule := NewProposerSchedule(testScheduleSettings(authorityKeys))
+	authorities := schedule.authorities
+
+	genesisBlock := testGenesisBlock()
+	genesisTimestamp := CreateBlockTimestampFromUnixMilliseconds(genesisBlock.Body.Timestamp)
+
+	slotStart, slotEnd, err := schedule.NextSlot(authorities[0], genesisBlock, genesisTimestamp)
+	assert.NoError(t, err)
+	assert.Equal(t, genesisTimestamp.Add(10*time.Second), slotStart)
+	assert.Equal(t, genesisTimestamp.Add(15*time.Second), slotEnd)
+
+	slotStart, slotEnd, err = schedule.NextSlot(authorities[2], genesisBlock, genesisTimestamp)
+	assert.NoError(t, err)
+	assert.Equal(t, genesisTimestamp.Add(20*time.Second), slotStart)
+	assert.Equal(t, genesisTimestamp.Add(25*time.Second), slotEnd)
+
+	// Missed slot is taken by next authority, authority gets its next turn after all others.
+	slotStart, _, err = schedule.NextSlot(authorities[0], genesisBlock, genesisTimestamp.Add(15*time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, genesisTimestamp.Add(25*time.Second), slotStart)
+
+	for _, timestamp := range []BlockTimestamp{slotStart, slotStart.Add(4 * time.Second)} {
+		proposer, err := schedule.Proposer(genesisBlock, timestamp)
+		assert.NoError(t, err)
+		assert.Equal(t, authorities[0], proposer)
+	}
+
+	_, _, err = schedule.NextSlot(testPrivateKey(t).PublicKey(), genesisBlock, genesisTimestamp)
+	assert.ErrorIs(t, err, ErrProposerScheduleUnknownAuthority)
+}
+
+func TestBlockValidator_ValidateProposer(t *testing.T) {
+	authorityKeys := testAuthorityKeys(t, 2)
+	blockValidator := NewBlockValidator(NewEventValidator(nil, NetworkSettings{}), newTestEventStorage(), nil, testScheduleSettings(authorityKeys))
+	authorities := blockValidator.proposerSchedule.authorities
+
+	genesisBlock := testGenesisBlock()
+	genesisBlock.Body.Timestamp = CreateBlockTimestampFromNow().Add(-time.Minute).UnixMilliseconds()
+
+	block := buildTestBlockAt(t, genesisBlock, []*blockchain.Event{}, testAuthorityKey(t, authorityKeys, authorities[0]), 10*time.Second)
+	assert.NoError(t, blockValidator.Validate(context.TODO(), genesisBlock, block))
+
+	block = buildTestBlockAt(t, genesisBlock, []*blockchain.Event{}, testAuthorityKey(t, authorityKeys, authorities[1]), 10*time.Second)
+	assert.ErrorIs(t, blockValidator.Validate(context.TODO(), genesisBlock, block), ErrBlockValidatorUnexpectedProposer)
+
+	block = buildTestBlockAt(t, genesisBlock, []*blockchain.Event{}, testAuthorityKey(t, authorityKeys, authorities[1]), 16*time.Second)
+	assert.NoError(t, blockValidator.Validate(context.TODO(), genesisBlock, block))
+}
+
+func testScheduleSettings(authorityKeys []*security.PrivateKey) NetworkSettings {
+	publicKeys := []crypto.PublicKey{}
+	for _, authorityKey := range authorityKeys {
+		publicKeys = append(publicKeys, authorityKey.PublicKey())
+	}
+
+	return NetworkSettings{
+		BlockInterval:       10 * time.Second,
+		BlockSlotTimeout:    5 * time.Second,
+		AuthorityPublicKeys: security.NewPublicKeysBag(publicKeys),
+	}
+}
+
+func testAuthorityKeys(t *testing.T, count int) []*security.PrivateKey {
+	authorityKeys := []*security.PrivateKey{}
+	for len(authorityKeys) < count {
+		authorityKeys = append(authorityKeys, testPrivateKey(t))
+	}
+
+	return authorityKeys
+}
+
+func testAuthorityKey(t *testing.T, authorityKeys []*security.PrivateKey, publicKey ed25519.PublicKey) *security.PrivateKey {
+	for _, authorityKey := range authorityKeys {
+		if publicKey.Equal(authorityKey.PublicKey()) {
+			return authorityKey
+		}
+	}
+
+	t.Fatal("authority key not found")
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package blockchain
+
+import (
+	"context"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+)
+
+// StateHasher calculates hash of game state resulting from applying block on top of current state. Current state
+// must stay untouched.
+type StateHasher interface {
+	HashBlockState(ctx context.Context, block *blockchainProtocol.Block) ([]byte, error)
+}
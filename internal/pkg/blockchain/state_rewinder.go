@@ -0,0 +1,11 @@
+package blockchain
+
+import (
+	"context"
+)
+
+// StateRewinder brings game state back to state after block at given height, when chain is reorganized. Block at
+// that height and all blocks below it are still in block storage, when state is rewound.
+type StateRewinder interface {
+	RewindState(ctx context.Context, height int) error
+}
@@ -0,0 +1,22 @@
+package blockchain
+
+import (
+	"context"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+)
+
+// PeerTip is latest block known to peer.
+type PeerTip struct {
+	Peer    string
+	Height  int
+	BlockId BlockId
+}
+
+// SyncConnector is implemented by connectors, which are able to download blocks missed by node from its peers.
+type SyncConnector interface {
+	// GetPeerTips returns tips of all reachable peers.
+	GetPeerTips(ctx context.Context) ([]PeerTip, error)
+
+	// GetBlocks returns up to count blocks of peer starting at given height.
+	GetBlocks(ctx context.Context, peer string, fromHeight int, count int) ([]*blockchainProtocol.Block, error)
+}
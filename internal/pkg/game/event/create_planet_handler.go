@@ -20,7 +20,7 @@ func NewCreatePlanetHandler(state *world.State) *CreatePlanetHandler {
 func (h *CreatePlanetHandler) Validate(event *blockchainProtocol.EventCreatePlanet, signature *security.Signature) error {
 	stateClone := h.state.Clone()
 
-	planetEntity, err := stateClone.Actions().Planet().Create()
+	planetEntity, err := stateClone.Actions().Planet().Create(event.Seed, event.GenerationVersion)
 	if err != nil {
 		return errors.Wrap(err, "unable to create planet")
 	}
@@ -37,7 +37,7 @@ func (h *CreatePlanetHandler) Handle(event *blockchainProtocol.EventCreatePlanet
 		return errors.Wrap(err, "validation failed")
 	}
 
-	planetEntity, err := h.state.Actions().Planet().Create()
+	planetEntity, err := h.state.Actions().Planet().Create(event.Seed, event.GenerationVersion)
 	if err != nil {
 		return errors.Wrap(err, "unable to create planet")
 	}
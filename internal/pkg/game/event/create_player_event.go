@@ -1,9 +1,11 @@
 package event
 
 import (
+	"crypto/ed25519"
 	"github.com/dominati-one/backend/internal/pkg/game/world"
 	"github.com/dominati-one/backend/internal/pkg/security"
 	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/pkg/errors"
 )
 
 type CreatePlayerHandler struct {
@@ -16,6 +18,32 @@ func NewCreatePlayerHandler(state *world.State) *CreatePlayerHandler {
 	}
 }
 
+func (h *CreatePlayerHandler) Validate(event *blockchainProtocol.EventCreatePlayer, signature *security.Signature) error {
+	if !signature.PublicKey().Equal(ed25519.PublicKey(event.PublicKey)) {
+		return ErrCreatePlayerSignerMismatch
+	}
+
+	stateClone := h.state.Clone()
+
+	if _, err := stateClone.Actions().Player().Create(event.Name, event.PublicKey); err != nil {
+		return errors.Wrap(err, "unable to create player")
+	}
+
+	return nil
+}
+
 func (h *CreatePlayerHandler) Handle(event *blockchainProtocol.EventCreatePlayer, signature *security.Signature) error {
+	if err := h.Validate(event, signature); err != nil {
+		return errors.Wrap(err, "validation failed")
+	}
+
+	if _, err := h.state.Actions().Player().Create(event.Name, event.PublicKey); err != nil {
+		return errors.Wrap(err, "unable to create player")
+	}
+
 	return nil
 }
+
+var (
+	ErrCreatePlayerSignerMismatch = errors.New("create player signer mismatch")
+)
@@ -1,7 +1,10 @@
 package game
 
 import (
+	"bufio"
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
 	"github.com/dominati-one/backend/internal/pkg/game/event"
 	"github.com/dominati-one/backend/internal/pkg/game/world"
 	"github.com/dominati-one/backend/internal/pkg/security"
@@ -9,11 +12,16 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"io"
+	"sync"
 	"time"
 )
 
 type Game struct {
-	log        zerolog.Logger
+	log zerolog.Logger
+	// swap guards world state and world clock pointers, which are replaced as whole by ApplyBlock, ReadSnapshot and
+	// Replace, while API readers hold previous ones.
+	swap       sync.RWMutex
 	state      *world.State
 	worldClock *world.WorldClock
 }
@@ -28,8 +36,12 @@ func NewGame() *Game {
 	}
 }
 
-func (g *Game) SetCurrentTimestamp(timestamp uint64) error {
-	delta, err := g.worldClock.SetCurrentTimestamp(timestamp)
+// SetBlockTimestamp advances world to timestamp of block. Randomness of world is seeded from block, see
+// NewBlockRandomSeed.
+func (g *Game) SetBlockTimestamp(blockBody *blockchainProtocol.Block_Body) error {
+	state, worldClock := g.current()
+
+	delta, err := worldClock.SetCurrentTimestamp(blockBody.Timestamp)
 	if err != nil {
 		return errors.Wrap(err, "unable to set current timestamp on world clock")
 	}
@@ -38,7 +50,7 @@ func (g *Game) SetCurrentTimestamp(timestamp uint64) error {
 
 	g.log.Trace().Msg("Delta time processing started.")
 
-	err = g.state.ApplyDeltaTime(delta)
+	err = state.ApplyDeltaTime(delta, NewBlockRandomSeed(blockBody))
 	if err != nil {
 		return errors.Wrap(err, "unable to apply delta time on world state")
 	}
@@ -56,12 +68,18 @@ func (g *Game) ApplyEvent(blockchainEvent *blockchainProtocol.Event) error {
 		return errors.Wrap(err, "unable to create signature")
 	}
 
+	if err := signature.Verify(blockchainEvent.Body); err != nil {
+		return errors.Wrap(err, "unable to verify signature")
+	}
+
+	state := g.State()
+
 	if createPlanetEvent := blockchainEvent.Body.GetCreatePlanet(); createPlanetEvent != nil {
-		return event.NewCreatePlanetHandler(g.state).Handle(createPlanetEvent, signature)
+		return event.NewCreatePlanetHandler(state).Handle(createPlanetEvent, signature)
 	}
 
 	if createPlayerEvent := blockchainEvent.Body.GetCreatePlayer(); createPlayerEvent != nil {
-		return event.NewCreatePlayerHandler(g.state).Handle(createPlayerEvent, signature)
+		return event.NewCreatePlayerHandler(state).Handle(createPlayerEvent, signature)
 	}
 
 	return nil
@@ -75,18 +93,152 @@ func (g *Game) Start(ctx context.Context) error {
 	return nil
 }
 
+// State returns current world state. Block application replaces state as whole instead of changing it, so readers
+// should take state once and use it for whole request to see consistent world.
 func (g *Game) State() *world.State {
+	defer g.swap.RUnlock()
+	g.swap.RLock()
+
 	return g.state
 }
 
 func (g *Game) WorldClock() *world.WorldClock {
+	defer g.swap.RUnlock()
+	g.swap.RLock()
+
 	return g.worldClock
 }
 
+// current returns world state and world clock, which belong together.
+func (g *Game) current() (*world.State, *world.WorldClock) {
+	defer g.swap.RUnlock()
+	g.swap.RLock()
+
+	return g.state, g.worldClock
+}
+
+// ApplyBlock applies block timestamp and its events as one transaction. Block is applied on clone of game, which
+// replaces game only after whole block was applied, so game always reflects state at exact block boundary. Errors of
+// events rejected by game are returned in order of block events, rejected event leaves state untouched.
+func (g *Game) ApplyBlock(block *blockchainProtocol.Block) (eventErrors []error, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			eventErrors = nil
+			err = errors.Wrapf(ErrGameBlockApplicationPanicked, "%v", recovered)
+		}
+	}()
+
+	gameClone := g.Clone()
+
+	eventErrors, err = gameClone.applyBlock(block)
+	if err != nil {
+		return nil, err
+	}
+
+	g.Replace(gameClone)
+
+	return eventErrors, nil
+}
+
+// HashBlockState returns world state hash after block timestamp and events are applied on clone of game, game itself
+// stays untouched. Events rejected by game are skipped, because they leave state untouched.
+func (g *Game) HashBlockState(block *blockchainProtocol.Block) ([]byte, error) {
+	gameClone := g.Clone()
+
+	if _, err := gameClone.applyBlock(block); err != nil {
+		return nil, err
+	}
+
+	return gameClone.State().Hash()
+}
+
+func (g *Game) applyBlock(block *blockchainProtocol.Block) ([]error, error) {
+	if err := g.SetBlockTimestamp(block.Body); err != nil {
+		return nil, errors.Wrap(err, "unable to apply block timestamp")
+	}
+
+	eventErrors := make([]error, len(block.Body.Events))
+	for eventIndex, blockEvent := range block.Body.Events {
+		eventErrors[eventIndex] = g.ApplyEvent(blockEvent.Event)
+	}
+
+	return eventErrors, nil
+}
+
+// NewBlockRandomSeed derives random seed from id of previous block and timestamp of block. Both are known before
+// state hash of block is calculated, so every node derives the same seed.
+func NewBlockRandomSeed(blockBody *blockchainProtocol.Block_Body) int64 {
+	timestampBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(timestampBytes, blockBody.Timestamp)
+
+	hash := sha256.New()
+	hash.Write(blockBody.PreviousBlockId)
+	hash.Write(timestampBytes)
+
+	return int64(binary.BigEndian.Uint64(hash.Sum(nil)))
+}
+
+// WriteSnapshot serializes world clock and world state.
+func (g *Game) WriteSnapshot(w io.Writer) error {
+	state, worldClock := g.current()
+
+	if err := worldClock.WriteSnapshot(w); err != nil {
+		return errors.Wrap(err, "unable to write world clock snapshot")
+	}
+
+	if err := state.WriteSnapshot(w); err != nil {
+		return errors.Wrap(err, "unable to write world state snapshot")
+	}
+
+	return nil
+}
+
+// ReadSnapshot replaces world clock and world state with snapshot written by WriteSnapshot. Game is left untouched,
+// when snapshot can not be read.
+func (g *Game) ReadSnapshot(r io.Reader) error {
+	// Shared buffered reader, so world clock does not read ahead into world state part of snapshot.
+	reader := bufio.NewReader(r)
+
+	worldClock, err := world.NewWorldClockFromSnapshot(reader)
+	if err != nil {
+		return errors.Wrap(err, "unable to read world clock snapshot")
+	}
+
+	state, err := world.NewStateFromSnapshot(reader)
+	if err != nil {
+		return errors.Wrap(err, "unable to read world state snapshot")
+	}
+
+	defer g.swap.Unlock()
+	g.swap.Lock()
+
+	g.worldClock = worldClock
+	g.state = state
+
+	return nil
+}
+
+// Replace swaps world clock and world state with those of other game, which must not be used afterwards.
+func (g *Game) Replace(other *Game) {
+	state, worldClock := other.current()
+
+	defer g.swap.Unlock()
+	g.swap.Lock()
+
+	g.worldClock = worldClock
+	g.state = state
+}
+
 func (g *Game) Clone() *Game {
+	state, worldClock := g.current()
+
 	return &Game{
 		log:        zerolog.Nop(),
-		state:      g.state.Clone(),
-		worldClock: g.worldClock.Clone(),
+		state:      state.Clone(),
+		worldClock: worldClock.Clone(),
 	}
 }
+
+var (
+	ErrGameBlockApplicationPanicked = errors.New("game block application panicked")
+)
@@ -4,6 +4,7 @@ type Actions struct {
 	planet *PlanetActions
 	seed   *SeedActions
 	plant  *PlantActions
+	player *PlayerActions
 }
 
 func newActions(state *State) *Actions {
@@ -11,6 +12,7 @@ func newActions(state *State) *Actions {
 		planet: newPlanetActions(state),
 		seed:   newSeedActions(state),
 		plant:  newPlantActions(state),
+		player: newPlayerActions(state),
 	}
 }
 
@@ -21,3 +23,11 @@ func (a *Actions) Planet() *PlanetActions {
 func (a *Actions) Seed() *SeedActions {
 	return a.seed
 }
+
+func (a *Actions) Plant() *PlantActions {
+	return a.plant
+}
+
+func (a *Actions) Player() *PlayerActions {
+	return a.player
+}
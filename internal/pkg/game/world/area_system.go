@@ -73,6 +73,120 @@ func (s *AreaSystem) Clone(newState *State) *AreaSystem {
 	}
 }
 
+func (s *AreaSystem) writeSnapshot(writer *snapshotWriter) {
+	areaEntities := make([]component.Entity, 0, len(s.areas))
+	for entity := range s.areas {
+		areaEntities = append(areaEntities, entity)
+	}
+
+	writer.writeUint64(uint64(len(areaEntities)))
+	for _, entity := range sortedEntities(areaEntities) {
+		area := s.areas[entity]
+
+		writer.writeEntity(entity)
+		writer.writeUint32(area.Width)
+		writer.writeUint32(area.Height)
+		writeAreaTilesSnapshot(writer, s.areasTiles[entity])
+		writer.writeBitmap(s.areasOccupancy[entity][component.AreaPositionLayerSurface])
+		writer.writeBitmap(s.areasOccupancy[entity][component.AreaPositionLayerPlayer])
+	}
+
+	positionEntities := make([]component.Entity, 0, len(s.areasPositions))
+	for entity := range s.areasPositions {
+		positionEntities = append(positionEntities, entity)
+	}
+
+	writer.writeUint64(uint64(len(positionEntities)))
+	for _, entity := range sortedEntities(positionEntities) {
+		areaPosition := s.areasPositions[entity]
+
+		writer.writeEntity(entity)
+		writer.writeEntity(areaPosition.Entity)
+		writer.writeUint8(uint8(areaPosition.Layer))
+		writer.writeUint32(areaPosition.X)
+		writer.writeUint32(areaPosition.Y)
+		writer.writeUint8(areaPosition.Width)
+		writer.writeUint8(areaPosition.Height)
+	}
+}
+
+func (s *AreaSystem) readSnapshot(reader *snapshotReader) {
+	areasCount := reader.readUint64()
+	for i := uint64(0); i < areasCount && reader.err == nil; i++ {
+		entity := reader.readEntity()
+
+		s.areas[entity] = component.Area{
+			Width:  reader.readUint32(),
+			Height: reader.readUint32(),
+		}
+		s.areasTiles[entity] = readAreaTilesSnapshot(reader)
+		s.areasOccupancy[entity] = map[component.AreaPositionLayer]*roaring64.Bitmap{
+			component.AreaPositionLayerSurface: reader.readBitmap(),
+			component.AreaPositionLayerPlayer:  reader.readBitmap(),
+		}
+	}
+
+	positionsCount := reader.readUint64()
+	for i := uint64(0); i < positionsCount && reader.err == nil; i++ {
+		entity := reader.readEntity()
+
+		s.areasPositions[entity] = component.AreaPosition{
+			Entity: reader.readEntity(),
+			Layer:  component.AreaPositionLayer(reader.readUint8()),
+			X:      reader.readUint32(),
+			Y:      reader.readUint32(),
+			Width:  reader.readUint8(),
+			Height: reader.readUint8(),
+		}
+	}
+}
+
+// writeAreaTilesSnapshot writes tiles run-length encoded, because generated terrain has large uniform regions.
+func writeAreaTilesSnapshot(writer *snapshotWriter, areaTiles component.AreaTiles) {
+	writer.writeUint64(uint64(len(areaTiles)))
+
+	for index := 0; index < len(areaTiles); {
+		runLength := 1
+		for index+runLength < len(areaTiles) && areaTiles[index+runLength] == areaTiles[index] {
+			runLength++
+		}
+
+		writer.writeUint64(uint64(runLength))
+		writer.writeUint8(uint8(areaTiles[index].Kind))
+		writer.writeEntity(areaTiles[index].OwnerEntity)
+
+		index += runLength
+	}
+}
+
+func readAreaTilesSnapshot(reader *snapshotReader) component.AreaTiles {
+	tilesCount := reader.readUint64()
+	if reader.err != nil {
+		return component.AreaTiles{}
+	}
+
+	areaTiles := make(component.AreaTiles, 0, tilesCount)
+
+	for uint64(len(areaTiles)) < tilesCount && reader.err == nil {
+		runLength := reader.readUint64()
+		areaTile := component.AreaTile{
+			Kind:        component.AreaTileKind(reader.readUint8()),
+			OwnerEntity: reader.readEntity(),
+		}
+
+		if runLength == 0 || uint64(len(areaTiles))+runLength > tilesCount {
+			reader.err = ErrSnapshotInvalidFormat
+			break
+		}
+
+		for i := uint64(0); i < runLength; i++ {
+			areaTiles = append(areaTiles, areaTile)
+		}
+	}
+
+	return areaTiles
+}
+
 func (s *AreaSystem) ValidatePosition(entity component.Entity, component component.AreaPosition) error {
 	area, exists := s.areas[component.Entity]
 	if !exists {
@@ -267,6 +381,13 @@ func (s *AreaSystem) removePosition(entity component.Entity) error {
 		return ErrAreaPositionComponentNotFound
 	}
 
+	areaPosition := s.areasPositions[entity]
+	if s.hasArea(areaPosition.Entity) {
+		if err := s.releasePosition(areaPosition); err != nil {
+			return errors.Wrap(err, "unable to release position")
+		}
+	}
+
 	delete(s.areasPositions, entity)
 
 	return nil
@@ -354,6 +475,24 @@ func (s *AreaSystem) takePosition(areaPosition component.AreaPosition) error {
 	return nil
 }
 
+// positionTaken checks, if any tile of area position is occupied in its layer.
+func (s *AreaSystem) positionTaken(areaPosition component.AreaPosition) (bool, error) {
+	bitmap := s.areasOccupancy[areaPosition.Entity][areaPosition.Layer]
+
+	areaPositionBitmap, err := s.areaPositionToBitmap(areaPosition)
+	if err != nil {
+		return false, err
+	}
+
+	for _, areaPositionIndex := range areaPositionBitmap.ToArray() {
+		if bitmap.Contains(areaPositionIndex) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func (s *AreaSystem) releasePosition(areaPosition component.AreaPosition) error {
 	bitmap := s.areasOccupancy[areaPosition.Entity][areaPosition.Layer]
 
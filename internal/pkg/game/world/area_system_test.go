@@ -571,6 +571,8 @@ func TestAreaSystem_removePosition(t *testing.T) {
 	err = state.area.removePosition(entityWithPosition)
 	assert.NoError(t, err)
 
+	err = state.area.takePosition(areaPosition)
+	assert.NoError(t, err)
 }
 
 func TestAreaSystem_removeArea(t *testing.T) {
@@ -8,16 +8,20 @@ import (
 type Planet struct {
 	Seed int64
 	Name string
+	// GenerationVersion selects parameters, with which planet was generated from seed.
+	GenerationVersion uint32
 }
 
 func (p Planet) Protobuf() *component.Planet {
 	return &component.Planet{
-		Seed: p.Seed,
-		Name: p.Name,
+		Seed:              p.Seed,
+		Name:              p.Name,
+		GenerationVersion: p.GenerationVersion,
 	}
 }
 
 func (p Planet) MarshalZerologObject(e *zerolog.Event) {
 	e.Int64("planetSeed", p.Seed)
 	e.Str("planetName", p.Name)
+	e.Uint32("planetGenerationVersion", p.GenerationVersion)
 }
@@ -16,10 +16,36 @@ const (
 	PlantKindCorn
 )
 
+// PlantStage is derived from maturity and age of plant.
+type PlantStage uint8
+
+const (
+	PlantStageSapling PlantStage = iota
+	PlantStageMature
+	PlantStageWithered
+)
+
+// PlantWitheredAge is fraction of lifespan, after which plant withers.
+const PlantWitheredAge float32 = 0.8
+
 type Plant struct {
 	Kind               PlantKind
 	Maturity           float32
 	AnemochoryMaturity float32
+	// Age is fraction of lifespan of plant, plant dies when it reaches 1.
+	Age float32
+}
+
+func (p Plant) Stage() PlantStage {
+	if p.Age >= PlantWitheredAge {
+		return PlantStageWithered
+	}
+
+	if p.Maturity >= 1 {
+		return PlantStageMature
+	}
+
+	return PlantStageSapling
 }
 
 func (p PlantKind) String() string {
@@ -41,8 +67,23 @@ func (p PlantKind) String() string {
 	}
 }
 
+func (p PlantStage) String() string {
+	switch p {
+	case PlantStageSapling:
+		return "PlantStageSapling"
+	case PlantStageMature:
+		return "PlantStageMature"
+	case PlantStageWithered:
+		return "PlantStageWithered"
+	default:
+		panic(fmt.Sprintf("missing PlantStage to string conversion for %d", p))
+	}
+}
+
 func (p Plant) MarshalZerologObject(e *zerolog.Event) {
 	e.Str("plantKind", p.Kind.String())
 	e.Float32("plantMaturity", p.Maturity)
 	e.Float32("plantAnemochoryMaturity", p.AnemochoryMaturity)
+	e.Float32("plantAge", p.Age)
+	e.Str("plantStage", p.Stage().String())
 }
@@ -0,0 +1,24 @@
+package component
+
+import (
+	"encoding/hex"
+	"github.com/dominati-one/backend/pkg/protocol/component"
+	"github.com/rs/zerolog"
+)
+
+type Player struct {
+	Name      string
+	PublicKey []byte
+}
+
+func (p Player) Protobuf() *component.Player {
+	return &component.Player{
+		Name:      p.Name,
+		PublicKey: p.PublicKey,
+	}
+}
+
+func (p Player) MarshalZerologObject(e *zerolog.Event) {
+	e.Str("playerName", p.Name)
+	e.Str("playerPublicKey", hex.EncodeToString(p.PublicKey))
+}
@@ -58,29 +58,27 @@ func (g *planetGenerator) get(x, y uint32) float64 {
 	return value / 1.75
 }
 
-func (f *PlanetActions) Create() (*component.Entity, error) {
-	planetEntity := f.state.Create(component.EntityKindPlanet)
-
-	seed := int64(f.state.Planet().Count() + 1)
-
-	width, height := f.createDimensionsFromSeed(seed)
-
-	name, err := f.createNameFromSeed(seed)
+// Create creates planet generated from seed with parameters of given generation version, so the same seed and
+// version always produce the same planet. Version 0 ignores seed and regenerates planets of events created before
+// generation was versioned.
+func (f *PlanetActions) Create(seed int64, generationVersion uint32) (*component.Entity, error) {
+	params, err := GetPlanetGenerationParams(generationVersion)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to create planetComponent name from seed")
+		return nil, errors.Wrap(err, "unable to get planet generation params")
 	}
 
-	planetComponent := component.Planet{
-		Seed: seed,
-		Name: name,
+	if params.SeedFromPlanetCount {
+		seed = int64(f.state.Planet().Count() + 1)
 	}
 
-	areaComponent := component.Area{
-		Width:  width,
-		Height: height,
+	planetComponent, areaComponent, err := f.createFromSeed(seed, generationVersion, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create planet from seed")
 	}
 
-	areaTiles := f.createAreaTilesFromSeed(width, height, seed, planetEntity)
+	planetEntity := f.state.Create(component.EntityKindPlanet)
+
+	areaTiles := f.createAreaTilesFromSeed(areaComponent.Width, areaComponent.Height, seed, params, planetEntity)
 
 	if err := f.state.planet.add(planetEntity, planetComponent); err != nil {
 		return nil, errors.Wrap(err, "planet component add to planet system failed")
@@ -93,40 +91,35 @@ func (f *PlanetActions) Create() (*component.Entity, error) {
 	return &planetEntity, nil
 }
 
-func (f *PlanetActions) createDimensionsFromSeed(seed int64) (uint32, uint32) {
+func (f *PlanetActions) createFromSeed(seed int64, generationVersion uint32, params *PlanetGenerationParams) (component.Planet, component.Area, error) {
 	source := rand.New(rand.NewSource(seed))
 
-	width := uint32(1000 + (source.Float64() * 4000))
-	height := uint32(1000 + (source.Float64() * 4000))
+	width, height := params.createDimensions(source)
 
-	return width, height
-}
+	name, err := params.createName(seed, source)
+	if err != nil {
+		return component.Planet{}, component.Area{}, errors.Wrap(err, "unable to create planet name")
+	}
 
-func (f *PlanetActions) createNameFromSeed(seed int64) (string, error) {
-	names := []string{
-		"New Ganymede",
-		"Tatlon",
-		"Aertan",
-		"New Kenya",
-		"Satai",
-		"Callisto",
-		"9733 Sagittae III",
-		"Ru-Shou Prime",
-		"New Earth",
+	planetComponent := component.Planet{
+		Seed:              seed,
+		Name:              name,
+		GenerationVersion: generationVersion,
 	}
 
-	if seed < 1 || seed > int64(len(names)) {
-		return "", ErrPlanetNameOutOfBounds
+	areaComponent := component.Area{
+		Width:  width,
+		Height: height,
 	}
 
-	return names[seed-1], nil
+	return planetComponent, areaComponent, nil
 }
 
-func (f *PlanetActions) createAreaTilesFromSeed(width, height uint32, seed int64, planetEntity component.Entity) component.AreaTiles {
+func (f *PlanetActions) createAreaTilesFromSeed(width, height uint32, seed int64, params *PlanetGenerationParams, planetEntity component.Entity) component.AreaTiles {
 	generators := &planetGenerators{
-		surfaceGenerator: newPlanetGenerator(seed, 8.0, width, height),
-		fertileGenerator: newPlanetGenerator(seed, 16.0, width, height),
-		stoneGenerator:   newPlanetGenerator(seed, 32.0, width, height),
+		surfaceGenerator: newPlanetGenerator(seed, params.SurfaceFrequency, width, height),
+		fertileGenerator: newPlanetGenerator(seed, params.FertileFrequency, width, height),
+		stoneGenerator:   newPlanetGenerator(seed, params.StoneFrequency, width, height),
 	}
 
 	tiles := make(component.AreaTiles, width*height)
@@ -137,7 +130,7 @@ func (f *PlanetActions) createAreaTilesFromSeed(width, height uint32, seed int64
 		for x = 0; x < width; x++ {
 			index := x + (y * width)
 
-			areaTileKind := f.createSurfaceFromPosition(x, y, width, height, generators)
+			areaTileKind := f.createSurfaceFromPosition(x, y, width, height, params, generators)
 
 			tiles[index] = component.AreaTile{
 				Kind:        areaTileKind,
@@ -149,14 +142,7 @@ func (f *PlanetActions) createAreaTilesFromSeed(width, height uint32, seed int64
 	return tiles
 }
 
-func (f *PlanetActions) createSurfaceFromPosition(x, y, width, height uint32, generators *planetGenerators) component.AreaTileKind {
-	shallowWaterLevel := 0.30
-	waterLevel := 0.40
-	sandLevel := 0.43
-	stoneLevel := 0.80
-
-	wrapDistance := 0.85
-
+func (f *PlanetActions) createSurfaceFromPosition(x, y, width, height uint32, params *PlanetGenerationParams, generators *planetGenerators) component.AreaTileKind {
 	surfaceLevel := generators.surfaceGenerator.get(x, y)
 
 	xDistance := math.Abs(float64(width/2)-float64(x)) / float64(width/2)
@@ -164,28 +150,28 @@ func (f *PlanetActions) createSurfaceFromPosition(x, y, width, height uint32, ge
 
 	distance := math.Max(xDistance, yDistance)
 
-	if distance > wrapDistance {
-		deepLevelModifier := (distance - wrapDistance) / (1 - wrapDistance)
+	if distance > params.WrapDistance {
+		deepLevelModifier := (distance - params.WrapDistance) / (1 - params.WrapDistance)
 		surfaceLevel -= deepLevelModifier
 		if surfaceLevel < 0 {
 			surfaceLevel = 0
 		}
 	}
 
-	if surfaceLevel < shallowWaterLevel {
+	if surfaceLevel < params.ShallowWaterLevel {
 		return component.AreaTileKindShallowWater
-	} else if surfaceLevel < waterLevel {
+	} else if surfaceLevel < params.WaterLevel {
 		return component.AreaTileKindWater
-	} else if surfaceLevel < sandLevel {
+	} else if surfaceLevel < params.SandLevel {
 		return component.AreaTileKindSand
-	} else if surfaceLevel > stoneLevel {
-		if generators.stoneGenerator.get(x, y) > 0.7 {
+	} else if surfaceLevel > params.StoneLevel {
+		if generators.stoneGenerator.get(x, y) > params.LavaLevel {
 			return component.AreaTileKindLava
 		} else {
 			return component.AreaTileKindStone
 		}
 	} else {
-		if generators.fertileGenerator.get(x, y) > 0.25 {
+		if generators.fertileGenerator.get(x, y) > params.FertileGroundLevel {
 			return component.AreaTileKindGround
 		} else {
 			return component.AreaTileKindFertileGround
@@ -196,5 +182,6 @@ func (f *PlanetActions) createSurfaceFromPosition(x, y, width, height uint32, ge
 }
 
 var (
-	ErrPlanetNameOutOfBounds = errors.New("planet name out of bounds")
+	ErrPlanetGenerationVersionNotFound = errors.New("planet generation version not found")
+	ErrPlanetNameOutOfBounds           = errors.New("planet name out of bounds")
 )
@@ -0,0 +1,93 @@
+package world
+
+import (
+	"github.com/dominati-one/backend/internal/pkg/game/world/component"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func testPlanetGenerationParams(t *testing.T) *PlanetGenerationParams {
+	params, err := GetPlanetGenerationParams(CurrentPlanetGenerationVersion)
+	assert.NoError(t, err)
+
+	params.MinWidth, params.MaxWidth = 20, 40
+	params.MinHeight, params.MaxHeight = 20, 40
+
+	return params
+}
+
+func TestPlanetActions_CreateFromSeed(t *testing.T) {
+	state := NewState()
+	params := testPlanetGenerationParams(t)
+
+	planet, area, err := state.Actions().Planet().createFromSeed(42, CurrentPlanetGenerationVersion, params)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 42, planet.Seed)
+	assert.Equal(t, CurrentPlanetGenerationVersion, planet.GenerationVersion)
+	assert.NotEmpty(t, planet.Name)
+	assert.True(t, area.Width >= params.MinWidth && area.Width <= params.MaxWidth)
+	assert.True(t, area.Height >= params.MinHeight && area.Height <= params.MaxHeight)
+
+	regeneratedPlanet, regeneratedArea, err := state.Actions().Planet().createFromSeed(42, CurrentPlanetGenerationVersion, params)
+	assert.NoError(t, err)
+	assert.Equal(t, planet, regeneratedPlanet)
+	assert.Equal(t, area, regeneratedArea)
+
+	tiles := state.Actions().Planet().createAreaTilesFromSeed(area.Width, area.Height, 42, params, component.Entity(1))
+	regeneratedTiles := state.Actions().Planet().createAreaTilesFromSeed(area.Width, area.Height, 42, params, component.Entity(1))
+	assert.Equal(t, tiles, regeneratedTiles)
+
+	names := map[string]struct{}{}
+	for seed := int64(1); seed <= 100; seed++ {
+		otherPlanet, _, err := state.Actions().Planet().createFromSeed(seed, CurrentPlanetGenerationVersion, params)
+		assert.NoError(t, err)
+		names[otherPlanet.Name] = struct{}{}
+	}
+	assert.Greater(t, len(names), 90)
+}
+
+func TestPlanetActions_CreateFromSeedUnversioned(t *testing.T) {
+	state := NewState()
+	params, err := GetPlanetGenerationParams(0)
+	assert.NoError(t, err)
+
+	// Planets of unversioned events were generated from number of planets plus one and named from fixed list.
+	source := rand.New(rand.NewSource(1))
+	width := uint32(1000 + (source.Float64() * 4000))
+	height := uint32(1000 + (source.Float64() * 4000))
+
+	planet, area, err := state.Actions().Planet().createFromSeed(1, 0, params)
+	assert.NoError(t, err)
+	assert.Equal(t, component.Planet{Seed: 1, Name: "New Ganymede"}, planet)
+	assert.Equal(t, component.Area{Width: width, Height: height}, area)
+
+	planet, _, err = state.Actions().Planet().createFromSeed(9, 0, params)
+	assert.NoError(t, err)
+	assert.Equal(t, "New Earth", planet.Name)
+
+	_, _, err = state.Actions().Planet().createFromSeed(10, 0, params)
+	assert.Equal(t, ErrPlanetNameOutOfBounds, errors.Cause(err))
+}
+
+func TestPlanetActions_Create(t *testing.T) {
+	state := NewState()
+
+	_, err := state.Actions().Planet().Create(42, CurrentPlanetGenerationVersion+1)
+	assert.Equal(t, ErrPlanetGenerationVersionNotFound, errors.Cause(err))
+	assert.Equal(t, 0, state.Planet().Count())
+}
+
+func TestGetPlanetGenerationParams(t *testing.T) {
+	params, err := GetPlanetGenerationParams(1)
+	assert.NoError(t, err)
+
+	params.MinWidth = 1
+	params.NameSyllables[0] = "changed"
+
+	original, err := GetPlanetGenerationParams(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1000, original.MinWidth)
+	assert.Equal(t, "ae", original.NameSyllables[0])
+}
@@ -0,0 +1,158 @@
+package world
+
+import (
+	"math/rand"
+	"strings"
+)
+
+const (
+	// CurrentPlanetGenerationVersion is used for newly created planets. Parameters of released versions must never
+	// change, otherwise planets created from events with older version would not regenerate identically.
+	CurrentPlanetGenerationVersion uint32 = 1
+)
+
+// PlanetGenerationParams holds all inputs of planet generation besides seed.
+type PlanetGenerationParams struct {
+	MinWidth  uint32
+	MaxWidth  uint32
+	MinHeight uint32
+	MaxHeight uint32
+
+	// Surface level below ShallowWaterLevel is shallow water, below WaterLevel water and below SandLevel sand. Surface
+	// level above StoneLevel is stone, or lava when stone noise is above LavaLevel. Remaining tiles are ground, or
+	// fertile ground when fertile noise is below FertileGroundLevel.
+	ShallowWaterLevel  float64
+	WaterLevel         float64
+	SandLevel          float64
+	StoneLevel         float64
+	LavaLevel          float64
+	FertileGroundLevel float64
+	// WrapDistance is relative distance from center of planet, where surface starts to sink into water.
+	WrapDistance float64
+
+	SurfaceFrequency float64
+	FertileFrequency float64
+	StoneFrequency   float64
+
+	// SeedFromPlanetCount ignores seed of event and uses number of existing planets plus one, as planets were created
+	// before seed of event was honored.
+	SeedFromPlanetCount bool
+	// Names are picked by seed starting from one, when set. Names are generated from NameSyllables otherwise.
+	Names []string
+
+	NameSyllables    []string
+	NameMinSyllables int
+	NameMaxSyllables int
+	NameSuffixes     []string
+	// NameSuffixChance is probability of name having one of NameSuffixes.
+	NameSuffixChance float64
+}
+
+var planetGenerationParams = map[uint32]PlanetGenerationParams{
+	// Version 0 is carried by events created before generation was versioned.
+	0: {
+		MinWidth:  1000,
+		MaxWidth:  5000,
+		MinHeight: 1000,
+		MaxHeight: 5000,
+
+		ShallowWaterLevel:  0.30,
+		WaterLevel:         0.40,
+		SandLevel:          0.43,
+		StoneLevel:         0.80,
+		LavaLevel:          0.7,
+		FertileGroundLevel: 0.25,
+		WrapDistance:       0.85,
+
+		SurfaceFrequency: 8.0,
+		FertileFrequency: 16.0,
+		StoneFrequency:   32.0,
+
+		SeedFromPlanetCount: true,
+		Names: []string{
+			"New Ganymede",
+			"Tatlon",
+			"Aertan",
+			"New Kenya",
+			"Satai",
+			"Callisto",
+			"9733 Sagittae III",
+			"Ru-Shou Prime",
+			"New Earth",
+		},
+	},
+	1: {
+		MinWidth:  1000,
+		MaxWidth:  5000,
+		MinHeight: 1000,
+		MaxHeight: 5000,
+
+		ShallowWaterLevel:  0.30,
+		WaterLevel:         0.40,
+		SandLevel:          0.43,
+		StoneLevel:         0.80,
+		LavaLevel:          0.7,
+		FertileGroundLevel: 0.25,
+		WrapDistance:       0.85,
+
+		SurfaceFrequency: 8.0,
+		FertileFrequency: 16.0,
+		StoneFrequency:   32.0,
+
+		NameSyllables: []string{
+			"ae", "al", "an", "ar", "ba", "cal", "da", "den", "el", "ga", "ny", "ka", "ke", "lis", "lon", "ma", "me",
+			"nya", "or", "ra", "ru", "sa", "shou", "ta", "tai", "tan", "tat", "to", "ve", "xa", "ze",
+		},
+		NameMinSyllables: 2,
+		NameMaxSyllables: 4,
+		NameSuffixes:     []string{"Prime", "Major", "Minor", "II", "III", "IV", "V"},
+		NameSuffixChance: 0.25,
+	},
+}
+
+// GetPlanetGenerationParams returns copy of parameters of given generation version.
+func GetPlanetGenerationParams(version uint32) (*PlanetGenerationParams, error) {
+	params, exists := planetGenerationParams[version]
+	if !exists {
+		return nil, ErrPlanetGenerationVersionNotFound
+	}
+
+	params.Names = append([]string{}, params.Names...)
+	params.NameSyllables = append([]string{}, params.NameSyllables...)
+	params.NameSuffixes = append([]string{}, params.NameSuffixes...)
+
+	return &params, nil
+}
+
+func (p *PlanetGenerationParams) createDimensions(source *rand.Rand) (uint32, uint32) {
+	width := p.MinWidth + uint32(source.Float64()*float64(p.MaxWidth-p.MinWidth))
+	height := p.MinHeight + uint32(source.Float64()*float64(p.MaxHeight-p.MinHeight))
+
+	return width, height
+}
+
+func (p *PlanetGenerationParams) createName(seed int64, source *rand.Rand) (string, error) {
+	if len(p.Names) > 0 {
+		if seed < 1 || seed > int64(len(p.Names)) {
+			return "", ErrPlanetNameOutOfBounds
+		}
+
+		return p.Names[seed-1], nil
+	}
+
+	syllablesCount := p.NameMinSyllables + source.Intn(p.NameMaxSyllables-p.NameMinSyllables+1)
+
+	builder := strings.Builder{}
+	for i := 0; i < syllablesCount; i++ {
+		builder.WriteString(p.NameSyllables[source.Intn(len(p.NameSyllables))])
+	}
+
+	name := builder.String()
+	name = strings.ToUpper(name[:1]) + name[1:]
+
+	if source.Float64() < p.NameSuffixChance {
+		name += " " + p.NameSuffixes[source.Intn(len(p.NameSuffixes))]
+	}
+
+	return name, nil
+}
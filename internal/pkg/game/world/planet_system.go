@@ -29,8 +29,9 @@ func (s *PlanetSystem) clone(newState *State) *PlanetSystem {
 
 	for entity, planet := range s.planets {
 		planetsClone[entity] = component.Planet{
-			Seed: planet.Seed,
-			Name: planet.Name,
+			Seed:              planet.Seed,
+			Name:              planet.Name,
+			GenerationVersion: planet.GenerationVersion,
 		}
 	}
 
@@ -41,6 +42,33 @@ func (s *PlanetSystem) clone(newState *State) *PlanetSystem {
 	}
 }
 
+func (s *PlanetSystem) writeSnapshot(writer *snapshotWriter) {
+	entities := sortedEntities(s.Entities())
+
+	writer.writeUint64(uint64(len(entities)))
+	for _, entity := range entities {
+		planet := s.planets[entity]
+
+		writer.writeEntity(entity)
+		writer.writeUint64(uint64(planet.Seed))
+		writer.writeString(planet.Name)
+		writer.writeUint32(planet.GenerationVersion)
+	}
+}
+
+func (s *PlanetSystem) readSnapshot(reader *snapshotReader) {
+	count := reader.readUint64()
+	for i := uint64(0); i < count && reader.err == nil; i++ {
+		entity := reader.readEntity()
+
+		s.planets[entity] = component.Planet{
+			Seed:              int64(reader.readUint64()),
+			Name:              reader.readString(),
+			GenerationVersion: reader.readUint32(),
+		}
+	}
+}
+
 func (s *PlanetSystem) remove(entity component.Entity) error {
 	if !s.exists(entity) {
 		return ErrPlanetComponentNotFound
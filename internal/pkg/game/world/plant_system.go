@@ -5,8 +5,66 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"math/rand"
 )
 
+// plantLifecycle holds growth rates of plant kind per second of world time and its seed dispersal.
+type plantLifecycle struct {
+	// maturityFactor is rate, with which plant matures.
+	maturityFactor float32
+	// agingFactor is rate, with which plant ages, reciprocal of lifespan.
+	agingFactor float32
+	// anemochoryFactor is rate, with which mature plant gets ready to disperse seeds.
+	anemochoryFactor float32
+	// seedKind is kind of dispersed seeds, which are dropped up to dispersalRadius tiles away from plant.
+	seedKind        component.SeedKind
+	dispersalRadius uint32
+	dispersalSeeds  int
+}
+
+var plantLifecycles = map[component.PlantKind]plantLifecycle{
+	component.PlantKindOakTree: {
+		maturityFactor:   WeekDeltaFactor,
+		agingFactor:      WeekDeltaFactor / 12,
+		anemochoryFactor: WeekDeltaFactor,
+		seedKind:         component.SeedKindOakTree,
+		dispersalRadius:  2,
+		dispersalSeeds:   1,
+	},
+	component.PlantKindPineTree: {
+		maturityFactor:   FourDaysDeltaFactor,
+		agingFactor:      WeekDeltaFactor / 8,
+		anemochoryFactor: FourDaysDeltaFactor,
+		seedKind:         component.SeedKindPineTree,
+		dispersalRadius:  2,
+		dispersalSeeds:   2,
+	},
+	component.PlantKindWheat: {
+		maturityFactor:   ThreeDaysDeltaFactor,
+		agingFactor:      WeekDeltaFactor / 2,
+		anemochoryFactor: ThreeDaysDeltaFactor,
+		seedKind:         component.SeedKindWheat,
+		dispersalRadius:  1,
+		dispersalSeeds:   2,
+	},
+	component.PlantKindCorn: {
+		maturityFactor:   ThreeDaysDeltaFactor,
+		agingFactor:      WeekDeltaFactor / 2,
+		anemochoryFactor: ThreeDaysDeltaFactor,
+		seedKind:         component.SeedKindCorn,
+		dispersalRadius:  1,
+		dispersalSeeds:   1,
+	},
+	component.PlantKindCannabis: {
+		maturityFactor:   TwoDaysDeltaFactor,
+		agingFactor:      WeekDeltaFactor,
+		anemochoryFactor: TwoDaysDeltaFactor,
+		seedKind:         component.SeedKindCannabis,
+		dispersalRadius:  1,
+		dispersalSeeds:   1,
+	},
+}
+
 type PlantSystem struct {
 	log   zerolog.Logger
 	state *State
@@ -36,7 +94,40 @@ func (s *PlantSystem) clone(newState *State) *PlantSystem {
 	}
 }
 
+func (s *PlantSystem) writeSnapshot(writer *snapshotWriter) {
+	entities := sortedEntities(s.Entities())
+
+	writer.writeUint64(uint64(len(entities)))
+	for _, entity := range entities {
+		plant := s.plants[entity]
+
+		writer.writeEntity(entity)
+		writer.writeUint8(uint8(plant.Kind))
+		writer.writeFloat32(plant.Maturity)
+		writer.writeFloat32(plant.AnemochoryMaturity)
+		writer.writeFloat32(plant.Age)
+	}
+}
+
+func (s *PlantSystem) readSnapshot(reader *snapshotReader) {
+	count := reader.readUint64()
+	for i := uint64(0); i < count && reader.err == nil; i++ {
+		entity := reader.readEntity()
+
+		s.plants[entity] = component.Plant{
+			Kind:               component.PlantKind(reader.readUint8()),
+			Maturity:           reader.readFloat32(),
+			AnemochoryMaturity: reader.readFloat32(),
+			Age:                reader.readFloat32(),
+		}
+	}
+}
+
 func (s *PlantSystem) validate(entity component.Entity, plant component.Plant) error {
+	if _, exists := plantLifecycles[plant.Kind]; !exists {
+		return ErrUnsupportedPlant
+	}
+
 	return nil
 }
 
@@ -56,6 +147,19 @@ func (s *PlantSystem) add(entity component.Entity, plant component.Plant) error
 	return nil
 }
 
+func (s *PlantSystem) Get(entity component.Entity) (*component.Plant, error) {
+	plant, exists := s.plants[entity]
+	if !exists {
+		return nil, ErrPlantComponentNotFound
+	}
+
+	return &plant, nil
+}
+
+func (s *PlantSystem) Count() int {
+	return len(s.plants)
+}
+
 func (s *PlantSystem) Entities() []component.Entity {
 	entities := []component.Entity{}
 
@@ -82,11 +186,172 @@ func (s *PlantSystem) remove(entity component.Entity) error {
 	return nil
 }
 
-func (s *PlantSystem) applyDeltaTime(delta uint64) error {
+// applyDeltaTime matures and ages plants and lets mature plants disperse seeds. Plants are processed in order of
+// entities with random source derived from block, so all nodes remove plants and drop seeds identically. Plant, which
+// reached end of its lifespan, is removed with all its components and frees its tile.
+func (s *PlantSystem) applyDeltaTime(delta uint64, random *rand.Rand) error {
+	deltaSeconds := float32(delta) / 1000
+
+	for _, entity := range sortedEntities(s.Entities()) {
+		plant := s.plants[entity]
+
+		lifecycle, exists := plantLifecycles[plant.Kind]
+		if !exists {
+			return errors.Wrapf(ErrUnsupportedPlant, "unable to apply delta time on plant %s", entity)
+		}
+
+		previousStage := plant.Stage()
+
+		// Explicit conversions round products, so compiler can not fuse them with addition, which would change result on
+		// some architectures and diverge state hash between nodes.
+		plant.Maturity += float32(lifecycle.maturityFactor * deltaSeconds)
+		if plant.Maturity > 1 {
+			plant.Maturity = 1
+		}
+
+		plant.Age += float32(lifecycle.agingFactor * deltaSeconds)
+
+		if plant.Age >= 1 {
+			if err := s.state.Remove(entity); err != nil {
+				return errors.Wrapf(err, "unable to remove dead plant %s", entity)
+			}
+
+			s.log.Debug().EmbedObject(entity).EmbedObject(plant).Msg("Plant died.")
+
+			continue
+		}
+
+		if plant.Stage() == component.PlantStageMature {
+			plant.AnemochoryMaturity += float32(lifecycle.anemochoryFactor * deltaSeconds)
+		}
+
+		// Every full anemochory maturity disperses seeds once, remainder is kept for next dispersal.
+		for plant.AnemochoryMaturity >= 1 {
+			plant.AnemochoryMaturity--
+
+			if err := s.disperseSeeds(entity, lifecycle, random); err != nil {
+				return errors.Wrapf(err, "unable to disperse seeds of plant %s", entity)
+			}
+		}
+
+		s.plants[entity] = plant
+
+		if plant.Stage() != previousStage {
+			s.log.Debug().EmbedObject(entity).EmbedObject(plant).Msg("Plant stage changed.")
+		}
+	}
+
+	return nil
+}
+
+// disperseSeeds drops seeds of plant on randomly chosen free neighboring tiles suitable for seeds. Dispersed seeds
+// are possessed by owner of plant. Plant without area position or possession has nowhere to drop seeds or nobody to
+// give them to, so it disperses nothing instead of failing whole block.
+func (s *PlantSystem) disperseSeeds(entity component.Entity, lifecycle plantLifecycle, random *rand.Rand) error {
+	position, err := s.state.area.GetPosition(entity)
+	if err == ErrAreaPositionComponentNotFound {
+		s.log.Warn().EmbedObject(entity).Msg("Plant without area position skipped seed dispersal.")
+
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "unable to get area position")
+	}
+
+	possession, err := s.state.possession.Get(entity)
+	if err == ErrPossessionComponentNotFound {
+		s.log.Warn().EmbedObject(entity).Msg("Plant without possession skipped seed dispersal.")
+
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "unable to get possession")
+	}
+
+	candidates, err := s.dispersalPositions(*position, lifecycle.dispersalRadius)
+	if err != nil {
+		return errors.Wrap(err, "unable to get dispersal positions")
+	}
+
+	for i := 0; i < lifecycle.dispersalSeeds && len(candidates) > 0; i++ {
+		candidateIndex := random.Intn(len(candidates))
+		candidate := candidates[candidateIndex]
+		candidates = append(candidates[:candidateIndex], candidates[candidateIndex+1:]...)
+
+		seedEntity, err := s.state.actions.seed.CreateSeed(lifecycle.seedKind, possession.OwnerEntity, candidate.Entity, candidate.X, candidate.Y)
+		if err != nil {
+			return errors.Wrapf(err, "unable to create seed at %d,%d", candidate.X, candidate.Y)
+		}
+
+		s.log.Debug().EmbedObject(entity).Str("seedEntity", seedEntity.String()).EmbedObject(candidate).Msg("Plant dispersed seed.")
+	}
+
 	return nil
 }
 
+// dispersalPositions returns free surface positions with tiles suitable for seeds around plant position, ordered by
+// rows and columns.
+func (s *PlantSystem) dispersalPositions(position component.AreaPosition, radius uint32) ([]component.AreaPosition, error) {
+	area, err := s.state.area.GetArea(position.Entity)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get area")
+	}
+
+	left, top := uint32(0), uint32(0)
+	if position.X > radius {
+		left = position.X - radius
+	}
+	if position.Y > radius {
+		top = position.Y - radius
+	}
+
+	right := position.X + uint32(position.Width) - 1 + radius
+	if right >= area.Width {
+		right = area.Width - 1
+	}
+	bottom := position.Y + uint32(position.Height) - 1 + radius
+	if bottom >= area.Height {
+		bottom = area.Height - 1
+	}
+
+	positions := []component.AreaPosition{}
+
+	for y := top; y <= bottom; y++ {
+		for x := left; x <= right; x++ {
+			tile, err := s.state.area.GetTile(position.Entity, x, y)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to get tile at %d,%d", x, y)
+			}
+
+			if !seedAreaTileKindSuitable(tile.Kind) {
+				continue
+			}
+
+			candidate := component.AreaPosition{
+				Entity: position.Entity,
+				Layer:  component.AreaPositionLayerSurface,
+				X:      x,
+				Y:      y,
+				Width:  1,
+				Height: 1,
+			}
+
+			taken, err := s.state.area.positionTaken(candidate)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to check position at %d,%d", x, y)
+			}
+
+			if !taken {
+				positions = append(positions, candidate)
+			}
+		}
+	}
+
+	return positions, nil
+}
+
 var (
 	ErrPlantComponentNotFound      = errors.New("plant component not found")
 	ErrPlantComponentAlreadyExists = errors.New("plant component already hasPosition")
+	ErrUnsupportedPlant            = errors.New("unsupported plant")
 )
@@ -0,0 +1,319 @@
+package world
+
+import (
+	"bytes"
+	"github.com/dominati-one/backend/internal/pkg/game/world/component"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestPlantSystem_Clone(t *testing.T) {
+	state := NewState()
+	planetEntity := createTestPlantPlanet(t, state)
+	plantEntity := createTestPlant(t, state, planetEntity, 1, 1)
+
+	stateClone := state.Clone()
+
+	assert.NotNil(t, stateClone.plant.state)
+	assert.NotNil(t, stateClone.plant.plants)
+
+	assert.NoError(t, stateClone.plant.applyDeltaTime(1000*60*60*24, testRandom()))
+
+	plant, err := state.plant.Get(plantEntity)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, plant.Maturity)
+
+	clonedPlant, err := stateClone.plant.Get(plantEntity)
+	assert.NoError(t, err)
+	assert.Greater(t, clonedPlant.Maturity, float32(0))
+}
+
+func TestPlantSystem_Get(t *testing.T) {
+	state := NewState()
+	planetEntity := createTestPlantPlanet(t, state)
+
+	_, err := state.Plant().Get(planetEntity)
+	assert.ErrorIs(t, err, ErrPlantComponentNotFound)
+	assert.Equal(t, 0, state.Plant().Count())
+
+	plantEntity := createTestPlant(t, state, planetEntity, 1, 1)
+
+	plant, err := state.Plant().Get(plantEntity)
+	assert.NoError(t, err)
+	assert.Equal(t, component.PlantKindOakTree, plant.Kind)
+	assert.Equal(t, component.PlantStageSapling, plant.Stage())
+	assert.Equal(t, 1, state.Plant().Count())
+}
+
+func TestPlantSystem_add(t *testing.T) {
+	state := NewState()
+	entity := state.Create(component.EntityKindPlantWheat)
+
+	err := state.plant.add(entity, component.Plant{Kind: component.PlantKindEmpty})
+	assert.Equal(t, ErrUnsupportedPlant, errors.Cause(err))
+
+	err = state.plant.add(entity, component.Plant{Kind: component.PlantKindWheat})
+	assert.NoError(t, err)
+
+	err = state.plant.add(entity, component.Plant{Kind: component.PlantKindWheat})
+	assert.ErrorIs(t, err, ErrPlantComponentAlreadyExists)
+}
+
+func TestPlantSystem_remove(t *testing.T) {
+	state := NewState()
+	entity := state.Create(component.EntityKindPlantWheat)
+
+	err := state.plant.remove(entity)
+	assert.ErrorIs(t, err, ErrPlantComponentNotFound)
+
+	assert.NoError(t, state.plant.add(entity, component.Plant{Kind: component.PlantKindWheat}))
+	assert.NoError(t, state.plant.remove(entity))
+	assert.False(t, state.plant.exists(entity))
+}
+
+func TestPlantSystem_applyDeltaTime(t *testing.T) {
+	plantKinds := []component.PlantKind{
+		component.PlantKindOakTree,
+		component.PlantKindPineTree,
+		component.PlantKindWheat,
+		component.PlantKindCorn,
+		component.PlantKindCannabis,
+	}
+
+	for _, plantKind := range plantKinds {
+		state := NewState()
+		entity := state.Create(component.EntityKindUnknown)
+		assert.NoError(t, state.plant.add(entity, component.Plant{Kind: plantKind}))
+
+		lifecycle := plantLifecycles[plantKind]
+
+		assert.NoError(t, state.plant.applyDeltaTime(1000, testRandom()))
+
+		plant, err := state.plant.Get(entity)
+		assert.NoError(t, err)
+		assert.InDelta(t, lifecycle.maturityFactor, plant.Maturity, 1e-9, plantKind.String())
+		assert.InDelta(t, lifecycle.agingFactor, plant.Age, 1e-9, plantKind.String())
+		assert.Greater(t, lifecycle.maturityFactor, lifecycle.agingFactor, plantKind.String())
+	}
+}
+
+func TestPlantSystem_applyDeltaTime_Stages(t *testing.T) {
+	state := NewState()
+	planetEntity := createTestPlantPlanet(t, state)
+	entity := createTestPlant(t, state, planetEntity, 1, 1)
+	state.plant.plants[entity] = component.Plant{Kind: component.PlantKindCannabis}
+
+	lifecycle := plantLifecycles[component.PlantKindCannabis]
+	matureDelta := uint64(1000 / lifecycle.maturityFactor)
+
+	assert.NoError(t, state.plant.applyDeltaTime(matureDelta/2, testRandom()))
+	plant, err := state.plant.Get(entity)
+	assert.NoError(t, err)
+	assert.Equal(t, component.PlantStageSapling, plant.Stage())
+
+	assert.NoError(t, state.plant.applyDeltaTime(matureDelta, testRandom()))
+	plant, err = state.plant.Get(entity)
+	assert.NoError(t, err)
+	assert.Equal(t, component.PlantStageMature, plant.Stage())
+	assert.EqualValues(t, 1, plant.Maturity)
+
+	witheredDelta := uint64(1000*(component.PlantWitheredAge-plant.Age)/lifecycle.agingFactor) + 1000
+
+	assert.NoError(t, state.plant.applyDeltaTime(witheredDelta, testRandom()))
+	plant, err = state.plant.Get(entity)
+	assert.NoError(t, err)
+	assert.Equal(t, component.PlantStageWithered, plant.Stage())
+	assert.EqualValues(t, 1, plant.Maturity)
+}
+
+func TestPlantSystem_applyDeltaTime_Death(t *testing.T) {
+	state := NewState()
+	planetEntity := createTestPlantPlanet(t, state)
+	plantEntity := createTestPlant(t, state, planetEntity, 2, 3)
+	otherPlantEntity := createTestPlant(t, state, planetEntity, 4, 4)
+
+	state.plant.plants[otherPlantEntity] = component.Plant{Kind: component.PlantKindWheat}
+
+	lifecycle := plantLifecycles[component.PlantKindWheat]
+	assert.NoError(t, state.ApplyDeltaTime(uint64(1000/lifecycle.agingFactor)+1000, 1))
+
+	assert.False(t, state.Exists(otherPlantEntity))
+	assert.False(t, state.plant.exists(otherPlantEntity))
+	assert.False(t, state.area.hasPosition(otherPlantEntity))
+	assert.False(t, state.possession.exists(otherPlantEntity))
+
+	assert.True(t, state.plant.exists(plantEntity))
+
+	position := component.AreaPosition{Entity: planetEntity, Layer: component.AreaPositionLayerSurface, X: 4, Y: 4, Width: 1, Height: 1}
+	assert.NoError(t, state.area.takePosition(position))
+}
+
+func TestPlantSystem_applyDeltaTime_Dispersal(t *testing.T) {
+	state := NewState()
+	planetEntity := createTestPlantPlanet(t, state)
+	plantEntity := createTestPlant(t, state, planetEntity, 5, 5)
+	state.plant.plants[plantEntity] = component.Plant{Kind: component.PlantKindPineTree, Maturity: 1, AnemochoryMaturity: 0.99}
+
+	lifecycle := plantLifecycles[component.PlantKindPineTree]
+	delta := uint64(1000*0.02/lifecycle.anemochoryFactor) + 1000
+
+	stateClone := state.Clone()
+
+	assert.NoError(t, state.plant.applyDeltaTime(delta, testRandom()))
+	assert.NoError(t, stateClone.plant.applyDeltaTime(delta, testRandom()))
+
+	plant, err := state.plant.Get(plantEntity)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.01+lifecycle.anemochoryFactor, plant.AnemochoryMaturity, 0.001)
+
+	seedEntities := sortedEntities(state.seed.Entities())
+	assert.Len(t, seedEntities, lifecycle.dispersalSeeds)
+	assert.Equal(t, seedEntities, sortedEntities(stateClone.seed.Entities()))
+
+	for _, seedEntity := range seedEntities {
+		seed, err := state.seed.Get(seedEntity)
+		assert.NoError(t, err)
+		assert.Equal(t, component.SeedKindPineTree, seed.Kind)
+
+		position, err := state.area.GetPosition(seedEntity)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, position.X, uint32(7))
+		assert.GreaterOrEqual(t, position.X, uint32(3))
+		assert.LessOrEqual(t, position.Y, uint32(7))
+		assert.GreaterOrEqual(t, position.Y, uint32(3))
+
+		clonePosition, err := stateClone.area.GetPosition(seedEntity)
+		assert.NoError(t, err)
+		assert.Equal(t, position, clonePosition)
+
+		possession, err := state.possession.Get(seedEntity)
+		assert.NoError(t, err)
+		assert.Equal(t, planetEntity, possession.OwnerEntity)
+	}
+}
+
+func TestPlantSystem_applyDeltaTime_DispersalLargeDelta(t *testing.T) {
+	state := NewState()
+	planetEntity := createTestPlantPlanet(t, state)
+	plantEntity := createTestPlant(t, state, planetEntity, 5, 5)
+	state.plant.plants[plantEntity] = component.Plant{Kind: component.PlantKindOakTree, Maturity: 1}
+
+	lifecycle := plantLifecycles[component.PlantKindOakTree]
+	delta := uint64(1000 * 3.5 / lifecycle.anemochoryFactor)
+
+	assert.NoError(t, state.plant.applyDeltaTime(delta, testRandom()))
+
+	plant, err := state.plant.Get(plantEntity)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.5, plant.AnemochoryMaturity, 0.001)
+	assert.Len(t, state.seed.Entities(), 3*lifecycle.dispersalSeeds)
+}
+
+func TestPlantSystem_applyDeltaTime_DispersalOnFreeSuitableTiles(t *testing.T) {
+	state := NewState()
+	planetEntity := state.Create(component.EntityKindPlanet)
+
+	areaTiles := createAreaTiles(10, 10, component.AreaTileKindWater)
+	for _, index := range []uint32{11, 12, 21} {
+		areaTiles[index].Kind = component.AreaTileKindGround
+	}
+	assert.NoError(t, state.area.addArea(planetEntity, component.Area{Width: 10, Height: 10}, areaTiles))
+
+	plantEntity := createTestPlant(t, state, planetEntity, 1, 1)
+	state.plant.plants[plantEntity] = component.Plant{Kind: component.PlantKindWheat, Maturity: 1, AnemochoryMaturity: 1}
+
+	occupiedSeedEntity, err := state.Actions().Seed().CreateWheatSeed(planetEntity, planetEntity, 2, 1)
+	assert.NoError(t, err)
+
+	assert.NoError(t, state.plant.applyDeltaTime(0, testRandom()))
+
+	seedEntities := state.seed.Entities()
+	assert.Len(t, seedEntities, 2)
+
+	for _, seedEntity := range seedEntities {
+		if seedEntity == *occupiedSeedEntity {
+			continue
+		}
+
+		position, err := state.area.GetPosition(seedEntity)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, position.X)
+		assert.EqualValues(t, 2, position.Y)
+	}
+}
+
+func TestPlantSystem_applyDeltaTime_SaplingDoesNotDisperse(t *testing.T) {
+	state := NewState()
+	planetEntity := createTestPlantPlanet(t, state)
+	plantEntity := createTestPlant(t, state, planetEntity, 5, 5)
+
+	assert.NoError(t, state.plant.applyDeltaTime(1000, testRandom()))
+
+	plant, err := state.plant.Get(plantEntity)
+	assert.NoError(t, err)
+	assert.Equal(t, component.PlantStageSapling, plant.Stage())
+	assert.EqualValues(t, 0, plant.AnemochoryMaturity)
+	assert.Empty(t, state.seed.Entities())
+}
+
+func TestPlantSystem_applyDeltaTime_DispersalWithoutPossession(t *testing.T) {
+	state := NewState()
+	planetEntity := createTestPlantPlanet(t, state)
+	plantEntity := createTestPlant(t, state, planetEntity, 5, 5)
+	state.plant.plants[plantEntity] = component.Plant{Kind: component.PlantKindWheat, Maturity: 1, AnemochoryMaturity: 1}
+	assert.NoError(t, state.possession.remove(plantEntity))
+
+	lifecycle := plantLifecycles[component.PlantKindWheat]
+	assert.NoError(t, state.plant.applyDeltaTime(1000, testRandom()))
+
+	plant, err := state.plant.Get(plantEntity)
+	assert.NoError(t, err)
+	assert.InDelta(t, lifecycle.agingFactor, plant.Age, 0.001)
+	assert.Less(t, plant.AnemochoryMaturity, float32(1))
+	assert.Empty(t, state.seed.Entities())
+}
+
+func TestPlantSystem_writeSnapshot(t *testing.T) {
+	state := NewState()
+	entity := state.Create(component.EntityKindPlantCorn)
+	assert.NoError(t, state.plant.add(entity, component.Plant{Kind: component.PlantKindCorn, Maturity: 0.5, AnemochoryMaturity: 0.25, Age: 0.125}))
+
+	buffer := &bytes.Buffer{}
+	writer := newSnapshotWriter(buffer)
+	state.plant.writeSnapshot(writer)
+	assert.NoError(t, writer.flush())
+
+	restoredState := NewState()
+	reader := newSnapshotReader(buffer)
+	restoredState.plant.readSnapshot(reader)
+	assert.NoError(t, reader.err)
+
+	plant, err := restoredState.plant.Get(entity)
+	assert.NoError(t, err)
+	assert.Equal(t, component.Plant{Kind: component.PlantKindCorn, Maturity: 0.5, AnemochoryMaturity: 0.25, Age: 0.125}, *plant)
+}
+
+func createTestPlantPlanet(t *testing.T, state *State) component.Entity {
+	planetEntity := state.Create(component.EntityKindPlanet)
+
+	err := state.area.addArea(planetEntity, component.Area{Width: 10, Height: 10}, createAreaTiles(10, 10, component.AreaTileKindGround))
+	assert.NoError(t, err)
+
+	return planetEntity
+}
+
+func createTestPlant(t *testing.T, state *State, planetEntity component.Entity, x, y uint32) component.Entity {
+	seedEntity, err := state.Actions().Seed().CreateOakSeed(planetEntity, planetEntity, x, y)
+	assert.NoError(t, err)
+
+	plantEntity, err := state.Actions().Plant().CreateFromSeedAndRemoveSeed(*seedEntity)
+	assert.NoError(t, err)
+
+	return *plantEntity
+}
+
+func testRandom() *rand.Rand {
+	return rand.New(rand.NewSource(1))
+}
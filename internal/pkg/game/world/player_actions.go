@@ -0,0 +1,61 @@
+package world
+
+import (
+	"crypto/ed25519"
+	"github.com/dominati-one/backend/internal/pkg/game/world/component"
+	"github.com/pkg/errors"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	PlayerNameMinLength = 3
+	PlayerNameMaxLength = 32
+)
+
+type PlayerActions struct {
+	state *State
+}
+
+func newPlayerActions(state *State) *PlayerActions {
+	return &PlayerActions{
+		state: state,
+	}
+}
+
+// Create creates player entity with given name and public key. Name and public key must not be used by other player.
+func (f *PlayerActions) Create(name string, publicKey []byte) (*component.Entity, error) {
+	if name != strings.TrimSpace(name) {
+		return nil, ErrPlayerInvalidName
+	}
+
+	if nameLength := utf8.RuneCountInString(name); nameLength < PlayerNameMinLength || nameLength > PlayerNameMaxLength {
+		return nil, ErrPlayerInvalidName
+	}
+
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, ErrPlayerInvalidPublicKey
+	}
+
+	playerComponent := component.Player{
+		Name:      name,
+		PublicKey: append([]byte{}, publicKey...),
+	}
+
+	if err := f.state.player.validate(component.Entity(0), playerComponent); err != nil {
+		return nil, errors.Wrap(err, "unable to validate player component")
+	}
+
+	playerEntity := f.state.Create(component.EntityKindPlayer)
+
+	if err := f.state.player.add(playerEntity, playerComponent); err != nil {
+		return nil, errors.Wrap(err, "player component add to player system failed")
+	}
+
+	return &playerEntity, nil
+}
+
+var (
+	ErrPlayerInvalidName      = errors.New("player invalid name")
+	ErrPlayerInvalidPublicKey = errors.New("player invalid public key")
+)
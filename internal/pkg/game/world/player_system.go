@@ -0,0 +1,180 @@
+package world
+
+import (
+	"github.com/dominati-one/backend/internal/pkg/game/world/component"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"strings"
+)
+
+type PlayerSystem struct {
+	log   zerolog.Logger
+	state *State
+
+	players map[component.Entity]component.Player
+	// names and publicKeys index players, so both stay unique and signer of event can be resolved to player. Names
+	// are compared case insensitively.
+	names      map[string]component.Entity
+	publicKeys map[string]component.Entity
+}
+
+func newPlayerSystem(state *State) *PlayerSystem {
+	return &PlayerSystem{
+		log:        log.With().Str("applicationComponent", "game").Str("gameComponent", "PlayerSystem").Logger(),
+		state:      state,
+		players:    map[component.Entity]component.Player{},
+		names:      map[string]component.Entity{},
+		publicKeys: map[string]component.Entity{},
+	}
+}
+
+func (s *PlayerSystem) clone(newState *State) *PlayerSystem {
+	playerSystemClone := &PlayerSystem{
+		log:        zerolog.Nop(),
+		state:      newState,
+		players:    map[component.Entity]component.Player{},
+		names:      map[string]component.Entity{},
+		publicKeys: map[string]component.Entity{},
+	}
+
+	for entity, player := range s.players {
+		playerSystemClone.insert(entity, player)
+	}
+
+	return playerSystemClone
+}
+
+func (s *PlayerSystem) writeSnapshot(writer *snapshotWriter) {
+	entities := sortedEntities(s.Entities())
+
+	writer.writeUint64(uint64(len(entities)))
+	for _, entity := range entities {
+		player := s.players[entity]
+
+		writer.writeEntity(entity)
+		writer.writeString(player.Name)
+		writer.writeString(string(player.PublicKey))
+	}
+}
+
+func (s *PlayerSystem) readSnapshot(reader *snapshotReader) {
+	count := reader.readUint64()
+	for i := uint64(0); i < count && reader.err == nil; i++ {
+		entity := reader.readEntity()
+
+		s.insert(entity, component.Player{
+			Name:      reader.readString(),
+			PublicKey: []byte(reader.readString()),
+		})
+	}
+}
+
+func (s *PlayerSystem) remove(entity component.Entity) error {
+	player, exists := s.players[entity]
+	if !exists {
+		return ErrPlayerComponentNotFound
+	}
+
+	delete(s.players, entity)
+	delete(s.names, playerNameKey(player.Name))
+	delete(s.publicKeys, string(player.PublicKey))
+
+	return nil
+}
+
+func (s *PlayerSystem) exists(entity component.Entity) bool {
+	_, exists := s.players[entity]
+
+	return exists
+}
+
+func (s *PlayerSystem) validate(entity component.Entity, player component.Player) error {
+	if _, exists := s.names[playerNameKey(player.Name)]; exists {
+		return ErrPlayerNameAlreadyTaken
+	}
+
+	if _, exists := s.publicKeys[string(player.PublicKey)]; exists {
+		return ErrPlayerPublicKeyAlreadyTaken
+	}
+
+	return nil
+}
+
+func (s *PlayerSystem) add(entity component.Entity, player component.Player) error {
+	if s.exists(entity) {
+		return ErrPlayerAlreadyExists
+	}
+
+	if err := s.validate(entity, player); err != nil {
+		return errors.Wrap(err, "unable to validate")
+	}
+
+	s.insert(entity, player)
+
+	s.log.Info().EmbedObject(entity).EmbedObject(player).Msg("Added player component.")
+
+	return nil
+}
+
+func (s *PlayerSystem) insert(entity component.Entity, player component.Player) {
+	s.players[entity] = player
+	s.names[playerNameKey(player.Name)] = entity
+	s.publicKeys[string(player.PublicKey)] = entity
+}
+
+func (s *PlayerSystem) Get(entity component.Entity) (*component.Player, error) {
+	if !s.exists(entity) {
+		return nil, ErrPlayerComponentNotFound
+	}
+
+	playerCopy := s.players[entity]
+
+	return &playerCopy, nil
+}
+
+// GetByPublicKey returns entity of player with given public key, so signer of event can be authorized as player.
+func (s *PlayerSystem) GetByPublicKey(publicKey []byte) (*component.Entity, error) {
+	entity, exists := s.publicKeys[string(publicKey)]
+	if !exists {
+		return nil, ErrPlayerComponentNotFound
+	}
+
+	return &entity, nil
+}
+
+// NameTaken checks, if name is used by any player.
+func (s *PlayerSystem) NameTaken(name string) bool {
+	_, exists := s.names[playerNameKey(name)]
+
+	return exists
+}
+
+func (s *PlayerSystem) Count() int {
+	return len(s.players)
+}
+
+func (s *PlayerSystem) Entities() []component.Entity {
+	entities := []component.Entity{}
+
+	for entity := range s.players {
+		entities = append(entities, entity)
+	}
+
+	return entities
+}
+
+func (s *PlayerSystem) applyDeltaTime(delta uint64) error {
+	return nil
+}
+
+func playerNameKey(name string) string {
+	return strings.ToLower(name)
+}
+
+var (
+	ErrPlayerAlreadyExists         = errors.New("player already exists")
+	ErrPlayerComponentNotFound     = errors.New("player component not found")
+	ErrPlayerNameAlreadyTaken      = errors.New("player name already taken")
+	ErrPlayerPublicKeyAlreadyTaken = errors.New("player public key already taken")
+)
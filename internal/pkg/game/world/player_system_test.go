@@ -0,0 +1,68 @@
+package world
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"github.com/dominati-one/backend/internal/pkg/game/world/component"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPlayerActions_Create(t *testing.T) {
+	state := NewState()
+	firstPublicKey := bytes.Repeat([]byte{1}, ed25519.PublicKeySize)
+	secondPublicKey := bytes.Repeat([]byte{2}, ed25519.PublicKeySize)
+
+	playerEntity, err := state.Actions().Player().Create("Player", firstPublicKey)
+	assert.NoError(t, err)
+
+	kind, err := state.GetKind(*playerEntity)
+	assert.NoError(t, err)
+	assert.Equal(t, component.EntityKindPlayer, *kind)
+
+	_, err = state.Actions().Player().Create("player", secondPublicKey)
+	assert.Equal(t, ErrPlayerNameAlreadyTaken, errors.Cause(err))
+
+	_, err = state.Actions().Player().Create("Other", firstPublicKey)
+	assert.Equal(t, ErrPlayerPublicKeyAlreadyTaken, errors.Cause(err))
+
+	_, err = state.Actions().Player().Create(" Other", secondPublicKey)
+	assert.ErrorIs(t, err, ErrPlayerInvalidName)
+
+	_, err = state.Actions().Player().Create("Ot", secondPublicKey)
+	assert.ErrorIs(t, err, ErrPlayerInvalidName)
+
+	_, err = state.Actions().Player().Create("Other", secondPublicKey[1:])
+	assert.ErrorIs(t, err, ErrPlayerInvalidPublicKey)
+
+	assert.Equal(t, 1, state.Player().Count())
+	assert.True(t, state.Player().NameTaken("PLAYER"))
+
+	foundEntity, err := state.Player().GetByPublicKey(firstPublicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, *playerEntity, *foundEntity)
+
+	_, err = state.Player().GetByPublicKey(secondPublicKey)
+	assert.ErrorIs(t, err, ErrPlayerComponentNotFound)
+}
+
+func TestPlayerSystem_Clone(t *testing.T) {
+	state := NewState()
+	publicKey := bytes.Repeat([]byte{1}, ed25519.PublicKeySize)
+
+	playerEntity, err := state.Actions().Player().Create("Player", publicKey)
+	assert.NoError(t, err)
+
+	stateClone := state.Clone()
+	assert.NoError(t, stateClone.Remove(*playerEntity))
+
+	assert.False(t, stateClone.Player().NameTaken("Player"))
+	_, err = stateClone.Player().GetByPublicKey(publicKey)
+	assert.ErrorIs(t, err, ErrPlayerComponentNotFound)
+
+	player, err := state.Player().Get(*playerEntity)
+	assert.NoError(t, err)
+	assert.Equal(t, "Player", player.Name)
+	assert.Equal(t, publicKey, player.PublicKey)
+}
@@ -40,6 +40,34 @@ func (s *PossessionSystem) clone(newState *State) *PossessionSystem {
 	}
 }
 
+func (s *PossessionSystem) writeSnapshot(writer *snapshotWriter) {
+	s.possessionsMutex.Lock()
+	entities := make([]component.Entity, 0, len(s.possessions))
+	for entity := range s.possessions {
+		entities = append(entities, entity)
+	}
+	s.possessionsMutex.Unlock()
+
+	writer.writeUint64(uint64(len(entities)))
+	for _, entity := range sortedEntities(entities) {
+		possession := s.possessions[entity]
+
+		writer.writeEntity(entity)
+		writer.writeEntity(possession.OwnerEntity)
+	}
+}
+
+func (s *PossessionSystem) readSnapshot(reader *snapshotReader) {
+	count := reader.readUint64()
+	for i := uint64(0); i < count && reader.err == nil; i++ {
+		entity := reader.readEntity()
+
+		s.possessions[entity] = component.Possession{
+			OwnerEntity: reader.readEntity(),
+		}
+	}
+}
+
 func (s *PossessionSystem) validate(entity component.Entity, possession component.Possession) error {
 	ownerKind, err := s.state.GetKind(possession.OwnerEntity)
 	if err != nil {
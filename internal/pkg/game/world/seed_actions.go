@@ -51,8 +51,7 @@ func (b *SeedActions) CreateRandomSeedsOnPlanet(planetEntity component.Entity) (
 				continue
 			}
 
-			if !((areaTiles[index].Kind == component.AreaTileKindGround) ||
-				(areaTiles[index].Kind == component.AreaTileKindFertileGround)) {
+			if !seedAreaTileKindSuitable(areaTiles[index].Kind) {
 				continue
 			}
 
@@ -91,40 +90,72 @@ func (b *SeedActions) CreateRandomSeedsOnPlanet(planetEntity component.Entity) (
 	return seedEntities, nil
 }
 
-func (b *SeedActions) CreateWheatSeed(owner, planet component.Entity, x, y uint32) (*component.Entity, error) {
-	entity := b.state.Create(component.EntityKindSeedWheat)
+// CreateSeed creates seed of given kind.
+func (b *SeedActions) CreateSeed(kind component.SeedKind, owner, planet component.Entity, x, y uint32) (*component.Entity, error) {
+	switch kind {
+	case component.SeedKindOakTree:
+		return b.CreateOakSeed(owner, planet, x, y)
+	case component.SeedKindPineTree:
+		return b.CreatePineSeed(owner, planet, x, y)
+	case component.SeedKindWheat:
+		return b.CreateWheatSeed(owner, planet, x, y)
+	case component.SeedKindCorn:
+		return b.CreateCornSeed(owner, planet, x, y)
+	case component.SeedKindCannabis:
+		return b.CreateCannabisSeed(owner, planet, x, y)
+	default:
+		return nil, ErrUnsupportedSeed
+	}
+}
 
+func (b *SeedActions) CreateWheatSeed(owner, planet component.Entity, x, y uint32) (*component.Entity, error) {
 	seed := component.Seed{
 		Kind:     component.SeedKindWheat,
 		Maturity: 0,
 	}
 
-	return b.create(entity, owner, planet, x, y, seed)
+	return b.create(component.EntityKindSeedWheat, owner, planet, x, y, seed)
 }
 
 func (b *SeedActions) CreatePineSeed(owner, planet component.Entity, x, y uint32) (*component.Entity, error) {
-	entity := b.state.Create(component.EntityKindSeedPineTree)
-
 	seed := component.Seed{
 		Kind:     component.SeedKindPineTree,
 		Maturity: 0,
 	}
 
-	return b.create(entity, owner, planet, x, y, seed)
+	return b.create(component.EntityKindSeedPineTree, owner, planet, x, y, seed)
 }
 
 func (b *SeedActions) CreateOakSeed(owner, planet component.Entity, x, y uint32) (*component.Entity, error) {
-	entity := b.state.Create(component.EntityKindSeedOakTree)
-
 	seed := component.Seed{
 		Kind:     component.SeedKindOakTree,
 		Maturity: 0,
 	}
 
-	return b.create(entity, owner, planet, x, y, seed)
+	return b.create(component.EntityKindSeedOakTree, owner, planet, x, y, seed)
+}
+
+func (b *SeedActions) CreateCornSeed(owner, planet component.Entity, x, y uint32) (*component.Entity, error) {
+	seed := component.Seed{
+		Kind:     component.SeedKindCorn,
+		Maturity: 0,
+	}
+
+	return b.create(component.EntityKindSeedCorn, owner, planet, x, y, seed)
 }
 
-func (b *SeedActions) create(entity, owner, planet component.Entity, x, y uint32, seed component.Seed) (*component.Entity, error) {
+func (b *SeedActions) CreateCannabisSeed(owner, planet component.Entity, x, y uint32) (*component.Entity, error) {
+	seed := component.Seed{
+		Kind:     component.SeedKindCannabis,
+		Maturity: 0,
+	}
+
+	return b.create(component.EntityKindSeedCannabis, owner, planet, x, y, seed)
+}
+
+// create creates seed entity with its components. Position is validated before entity is created, and entity is
+// removed with all components added so far, when any component can not be added, so failure leaves state untouched.
+func (b *SeedActions) create(kind component.EntityKind, owner, planet component.Entity, x, y uint32, seed component.Seed) (*component.Entity, error) {
 	position := component.AreaPosition{
 		Entity: planet,
 		X:      x,
@@ -138,17 +169,41 @@ func (b *SeedActions) create(entity, owner, planet component.Entity, x, y uint32
 		OwnerEntity: owner,
 	}
 
+	if err := b.state.seed.validatePosition(position); err != nil {
+		return nil, errors.Wrap(err, "unable to validate seed area position")
+	}
+
+	entity := b.state.Create(kind)
+
+	if err := b.addComponents(entity, position, seed, possession); err != nil {
+		if removeErr := b.state.Remove(entity); removeErr != nil {
+			return nil, errors.Wrapf(removeErr, "unable to remove seed entity after failure: %v", err)
+		}
+
+		return nil, err
+	}
+
+	return &entity, nil
+}
+
+func (b *SeedActions) addComponents(entity component.Entity, position component.AreaPosition, seed component.Seed, possession component.Possession) error {
 	if err := b.state.area.addPosition(entity, position); err != nil {
-		return nil, errors.Wrap(err, "unable to add area position component to seed entity")
+		return errors.Wrap(err, "unable to add area position component to seed entity")
 	}
 
 	if err := b.state.seed.add(entity, seed); err != nil {
-		return nil, errors.Wrap(err, "unable to add seed component to seed entity")
+		return errors.Wrap(err, "unable to add seed component to seed entity")
 	}
 
 	if err := b.state.possession.add(entity, possession); err != nil {
-		return nil, errors.Wrap(err, "unable to add possessions component to seed entity")
+		return errors.Wrap(err, "unable to add possessions component to seed entity")
 	}
 
-	return &entity, nil
+	return nil
+}
+
+// seedAreaTileKindSuitable checks, if seeds spread on their own to tile of given kind. Seeds spread to ground only,
+// other tiles in seedAreaTileFertilities accept only seeds created explicitly.
+func seedAreaTileKindSuitable(kind component.AreaTileKind) bool {
+	return kind == component.AreaTileKindGround || kind == component.AreaTileKindFertileGround
 }
@@ -0,0 +1,30 @@
+package world
+
+import (
+	"github.com/dominati-one/backend/internal/pkg/game/world/component"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSeedActions_CreateOnTakenPosition(t *testing.T) {
+	state := NewState()
+	planetEntity := state.Create(component.EntityKindPlanet)
+	assert.NoError(t, state.area.addArea(planetEntity, component.Area{Width: 10, Height: 10}, createAreaTiles(10, 10, component.AreaTileKindGround)))
+
+	seedEntity, err := state.Actions().Seed().CreateWheatSeed(planetEntity, planetEntity, 1, 1)
+	assert.NoError(t, err)
+
+	// Seed entity, which can not take its position, is removed, so no component-less entity is left in state.
+	_, err = state.Actions().Seed().CreateCornSeed(planetEntity, planetEntity, 1, 1)
+	assert.Equal(t, ErrAreaPositionAlreadyTaken, errors.Cause(err))
+	assert.Len(t, state.entities, 2)
+	assert.Equal(t, []component.Entity{*seedEntity}, state.seed.Entities())
+	assert.False(t, state.possession.exists(*seedEntity+1))
+
+	position, err := state.area.GetPosition(*seedEntity)
+	assert.NoError(t, err)
+	taken, err := state.area.positionTaken(*position)
+	assert.NoError(t, err)
+	assert.True(t, taken)
+}
@@ -10,6 +10,22 @@ import (
 
 type SeedUpdateFn func(seed component.Seed) (*component.Seed, error)
 
+const (
+	// seedWaterProximityRadius is distance in tiles, in which water boosts growth of seed.
+	seedWaterProximityRadius uint32  = 2
+	seedWaterProximityBoost  float32 = 1.25
+)
+
+// seedAreaTileFertilities holds growth multiplier of seeds on tile kind. Seeds can not be planted on tile kinds
+// missing here, e.g. water, lava or stone.
+var seedAreaTileFertilities = map[component.AreaTileKind]float32{
+	component.AreaTileKindFertileGround: 1.5,
+	component.AreaTileKindGround:        1,
+	component.AreaTileKindSand:          0.5,
+	component.AreaTileKindGravel:        0,
+	component.AreaTileKindSnow:          0,
+}
+
 type SeedSystem struct {
 	log   zerolog.Logger
 	state *State
@@ -40,14 +56,133 @@ func (s *SeedSystem) clone(newState *State) *SeedSystem {
 	}
 }
 
+func (s *SeedSystem) writeSnapshot(writer *snapshotWriter) {
+	entities := sortedEntities(s.Entities())
+
+	writer.writeUint64(uint64(len(entities)))
+	for _, entity := range entities {
+		seed := s.seeds[entity]
+
+		writer.writeEntity(entity)
+		writer.writeUint8(uint8(seed.Kind))
+		writer.writeFloat32(seed.Maturity)
+	}
+}
+
+func (s *SeedSystem) readSnapshot(reader *snapshotReader) {
+	count := reader.readUint64()
+	for i := uint64(0); i < count && reader.err == nil; i++ {
+		entity := reader.readEntity()
+
+		s.seeds[entity] = component.Seed{
+			Kind:     component.SeedKind(reader.readUint8()),
+			Maturity: reader.readFloat32(),
+		}
+	}
+}
+
 func (s *SeedSystem) validate(entity component.Entity, seed component.Seed) error {
 	if seed.Maturity > 1.0 {
 		return ErrSeedComponentMaturityOverflow
 	}
 
+	if position, err := s.state.area.GetPosition(entity); err == nil {
+		if err := s.validatePosition(*position); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// validatePosition checks, if seed can be planted on tiles of area position.
+func (s *SeedSystem) validatePosition(position component.AreaPosition) error {
+	for y := position.Y; y < position.Y+uint32(position.Height); y++ {
+		for x := position.X; x < position.X+uint32(position.Width); x++ {
+			tile, err := s.state.area.GetTile(position.Entity, x, y)
+			if err != nil {
+				return errors.Wrapf(err, "unable to get tile at %d,%d", x, y)
+			}
+
+			if _, plantable := seedAreaTileFertilities[tile.Kind]; !plantable {
+				return ErrSeedAreaTileNotPlantable
+			}
+		}
+	}
+
+	return nil
+}
+
+// fertility returns growth multiplier of seed at area position given by tile kind and proximity of water.
+func (s *SeedSystem) fertility(position component.AreaPosition) (float32, error) {
+	tile, err := s.state.area.GetTile(position.Entity, position.X, position.Y)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to get tile")
+	}
+
+	fertility, plantable := seedAreaTileFertilities[tile.Kind]
+	if !plantable {
+		return 0, ErrSeedAreaTileNotPlantable
+	}
+
+	if fertility == 0 {
+		return 0, nil
+	}
+
+	nearWater, err := s.nearWater(position)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to check water proximity")
+	}
+
+	if nearWater {
+		fertility *= seedWaterProximityBoost
+	}
+
+	return fertility, nil
+}
+
+func (s *SeedSystem) nearWater(position component.AreaPosition) (bool, error) {
+	area, err := s.state.area.GetArea(position.Entity)
+	if err != nil {
+		return false, errors.Wrap(err, "unable to get area")
+	}
+
+	left, top := uint32(0), uint32(0)
+	if position.X > seedWaterProximityRadius {
+		left = position.X - seedWaterProximityRadius
+	}
+	if position.Y > seedWaterProximityRadius {
+		top = position.Y - seedWaterProximityRadius
+	}
+
+	right := position.X + seedWaterProximityRadius
+	if right >= area.Width {
+		right = area.Width - 1
+	}
+	bottom := position.Y + seedWaterProximityRadius
+	if bottom >= area.Height {
+		bottom = area.Height - 1
+	}
+
+	areaTiles, err := s.state.area.GetAreaTiles(position.Entity, AreaTilesExtent{
+		Left:   left,
+		Top:    top,
+		Right:  right,
+		Bottom: bottom,
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "unable to get area tiles")
+	}
+
+	for _, areaTile := range areaTiles {
+		if areaTile.Kind == component.AreaTileKindWater || areaTile.Kind == component.AreaTileKindShallowWater {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func (s *SeedSystem) add(entity component.Entity, seed component.Seed) error {
 	if s.exists(entity) {
 		return ErrSeedComponentAlreadyExists
@@ -118,27 +253,40 @@ func (s *SeedSystem) Get(entity component.Entity) (*component.Seed, error) {
 func (s *SeedSystem) applyDeltaTime(delta uint64) error {
 	deltaSeconds := float32(delta) / 1000
 
-	for entity := range s.seeds {
+	// Seeds are processed in order of entities, so plants created from seeds get identical entities on all nodes.
+	for _, entity := range sortedEntities(s.Entities()) {
 		err := s.update(entity, func(seed component.Seed) (*component.Seed, error) {
-			if !s.state.area.hasPosition(entity) {
+			position, err := s.state.area.GetPosition(entity)
+			if err != nil {
 				return nil, nil
 			}
 
+			fertility, err := s.fertility(*position)
+			if err != nil {
+				return nil, errors.Wrap(err, "unable to get fertility")
+			}
+
+			var growthFactor float32
+
 			switch seed.Kind {
 			case component.SeedKindOakTree:
-				seed.Maturity += ThreeDaysDeltaFactor * deltaSeconds
+				growthFactor = ThreeDaysDeltaFactor
 			case component.SeedKindPineTree:
-				seed.Maturity += TwoDaysDeltaFactor * deltaSeconds
+				growthFactor = TwoDaysDeltaFactor
 			case component.SeedKindWheat:
-				seed.Maturity += TwoDaysDeltaFactor * deltaSeconds
+				growthFactor = TwoDaysDeltaFactor
 			case component.SeedKindCorn:
-				seed.Maturity += TwoDaysDeltaFactor * deltaSeconds
+				growthFactor = TwoDaysDeltaFactor
 			case component.SeedKindCannabis:
-				seed.Maturity += DayDeltaFactor * deltaSeconds
+				growthFactor = DayDeltaFactor
 			default:
 				s.log.Panic().Msg("Unsupported seed.")
 			}
 
+			// Explicit conversions round every product, so compiler can not fuse it with addition, which would change
+			// result on some architectures and diverge state hash between nodes.
+			seed.Maturity += float32(float32(growthFactor*deltaSeconds) * fertility)
+
 			if seed.Maturity > 1 {
 				_, err := s.state.actions.plant.CreateFromSeedAndRemoveSeed(entity)
 				if err != nil {
@@ -181,4 +329,5 @@ var (
 	ErrSeedComponentAlreadyExists    = errors.New("seed component already hasPosition")
 	ErrSeedComponentNotFound         = errors.New("seed component not found")
 	ErrSeedComponentMaturityOverflow = errors.New("seed component maturity overflow")
+	ErrSeedAreaTileNotPlantable      = errors.New("seed area tile not plantable")
 )
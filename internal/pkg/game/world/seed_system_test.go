@@ -0,0 +1,133 @@
+package world
+
+import (
+	"github.com/dominati-one/backend/internal/pkg/game/world/component"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSeedSystem_validatePosition(t *testing.T) {
+	tileKinds := map[component.AreaTileKind]bool{
+		component.AreaTileKindFertileGround: true,
+		component.AreaTileKindGround:        true,
+		component.AreaTileKindSand:          true,
+		component.AreaTileKindGravel:        true,
+		component.AreaTileKindSnow:          true,
+		component.AreaTileKindWater:         false,
+		component.AreaTileKindShallowWater:  false,
+		component.AreaTileKindLava:          false,
+		component.AreaTileKindStone:         false,
+		component.AreaTileKindEmpty:         false,
+	}
+
+	for tileKind, plantable := range tileKinds {
+		state := NewState()
+		planetEntity := state.Create(component.EntityKindPlanet)
+
+		err := state.area.addArea(planetEntity, component.Area{Width: 10, Height: 10}, createAreaTiles(10, 10, tileKind))
+		assert.NoError(t, err)
+
+		seedEntity, err := state.Actions().Seed().CreateWheatSeed(planetEntity, planetEntity, 1, 1)
+		if plantable {
+			assert.NoError(t, err)
+			assert.True(t, state.seed.exists(*seedEntity))
+			continue
+		}
+
+		assert.Equal(t, ErrSeedAreaTileNotPlantable, errors.Cause(err))
+		assert.Empty(t, state.seed.Entities())
+		assert.Len(t, state.entities, 1)
+
+		taken, err := state.area.positionTaken(component.AreaPosition{Entity: planetEntity, Layer: component.AreaPositionLayerSurface, X: 1, Y: 1, Width: 1, Height: 1})
+		assert.NoError(t, err)
+		assert.False(t, taken)
+	}
+}
+
+func TestSeedSystem_applyDeltaTime_Fertility(t *testing.T) {
+	tileKinds := []component.AreaTileKind{
+		component.AreaTileKindFertileGround,
+		component.AreaTileKindGround,
+		component.AreaTileKindSand,
+		component.AreaTileKindGravel,
+	}
+
+	maturities := []float32{}
+
+	for _, tileKind := range tileKinds {
+		state := NewState()
+		planetEntity := state.Create(component.EntityKindPlanet)
+
+		err := state.area.addArea(planetEntity, component.Area{Width: 10, Height: 10}, createAreaTiles(10, 10, tileKind))
+		assert.NoError(t, err)
+
+		seedEntity, err := state.Actions().Seed().CreateWheatSeed(planetEntity, planetEntity, 5, 5)
+		assert.NoError(t, err)
+
+		assert.NoError(t, state.seed.applyDeltaTime(1000))
+
+		seed, err := state.seed.Get(*seedEntity)
+		assert.NoError(t, err)
+		assert.InDelta(t, TwoDaysDeltaFactor*seedAreaTileFertilities[tileKind], seed.Maturity, 1e-9, tileKind)
+
+		maturities = append(maturities, seed.Maturity)
+	}
+
+	assert.Greater(t, maturities[0], maturities[1])
+	assert.Greater(t, maturities[1], maturities[2])
+	assert.Greater(t, maturities[2], maturities[3])
+	assert.EqualValues(t, 0, maturities[3])
+}
+
+func TestSeedSystem_applyDeltaTime_WaterProximity(t *testing.T) {
+	state := NewState()
+	planetEntity := state.Create(component.EntityKindPlanet)
+
+	areaTiles := createAreaTiles(10, 10, component.AreaTileKindGround)
+	areaTiles[0].Kind = component.AreaTileKindShallowWater
+	assert.NoError(t, state.area.addArea(planetEntity, component.Area{Width: 10, Height: 10}, areaTiles))
+
+	nearSeedEntity, err := state.Actions().Seed().CreateWheatSeed(planetEntity, planetEntity, 2, 2)
+	assert.NoError(t, err)
+	farSeedEntity, err := state.Actions().Seed().CreateWheatSeed(planetEntity, planetEntity, 3, 3)
+	assert.NoError(t, err)
+
+	assert.NoError(t, state.seed.applyDeltaTime(1000))
+
+	nearSeed, err := state.seed.Get(*nearSeedEntity)
+	assert.NoError(t, err)
+	assert.InDelta(t, TwoDaysDeltaFactor*seedWaterProximityBoost, nearSeed.Maturity, 1e-9)
+
+	farSeed, err := state.seed.Get(*farSeedEntity)
+	assert.NoError(t, err)
+	assert.InDelta(t, TwoDaysDeltaFactor, farSeed.Maturity, 1e-9)
+}
+
+func TestSeedSystem_applyDeltaTime_CreatesPlants(t *testing.T) {
+	state := NewState()
+	planetEntity := createTestPlantPlanet(t, state)
+
+	for x := uint32(0); x < 5; x++ {
+		_, err := state.Actions().Seed().CreateCannabisSeed(planetEntity, planetEntity, x, 0)
+		assert.NoError(t, err)
+	}
+
+	stateClone := state.Clone()
+
+	delta := uint64(1000/DayDeltaFactor) + 1000
+	assert.NoError(t, state.seed.applyDeltaTime(delta))
+	assert.NoError(t, stateClone.seed.applyDeltaTime(delta))
+
+	assert.Empty(t, state.seed.Entities())
+	assert.Equal(t, 5, state.plant.Count())
+
+	for _, plantEntity := range state.plant.Entities() {
+		position, err := state.area.GetPosition(plantEntity)
+		assert.NoError(t, err)
+
+		clonePosition, err := stateClone.area.GetPosition(plantEntity)
+		assert.NoError(t, err)
+		assert.Equal(t, position, clonePosition)
+	}
+}
@@ -0,0 +1,240 @@
+package world
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"github.com/RoaringBitmap/roaring/roaring64"
+	"github.com/dominati-one/backend/internal/pkg/game/world/component"
+	"github.com/pkg/errors"
+	"io"
+	"math"
+	"sort"
+)
+
+const (
+	snapshotMagic   uint32 = 0x444f5753 // "DOWS"
+	snapshotVersion uint32 = 4
+)
+
+// snapshotWriter writes big endian primitives and remembers first error, so systems can serialize without checking
+// every call.
+type snapshotWriter struct {
+	w      *bufio.Writer
+	buffer [8]byte
+	err    error
+}
+
+func newSnapshotWriter(w io.Writer) *snapshotWriter {
+	return &snapshotWriter{w: bufio.NewWriter(w)}
+}
+
+func (w *snapshotWriter) write(p []byte) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = w.w.Write(p)
+}
+
+func (w *snapshotWriter) writeUint8(v uint8) {
+	w.buffer[0] = v
+	w.write(w.buffer[:1])
+}
+
+func (w *snapshotWriter) writeUint32(v uint32) {
+	binary.BigEndian.PutUint32(w.buffer[:4], v)
+	w.write(w.buffer[:4])
+}
+
+func (w *snapshotWriter) writeUint64(v uint64) {
+	binary.BigEndian.PutUint64(w.buffer[:8], v)
+	w.write(w.buffer[:8])
+}
+
+func (w *snapshotWriter) writeFloat32(v float32) {
+	w.writeUint32(math.Float32bits(v))
+}
+
+func (w *snapshotWriter) writeString(v string) {
+	w.writeUint32(uint32(len(v)))
+	w.write([]byte(v))
+}
+
+func (w *snapshotWriter) writeEntity(entity component.Entity) {
+	w.writeUint64(uint64(entity))
+}
+
+func (w *snapshotWriter) writeBitmap(bitmap *roaring64.Bitmap) {
+	values := bitmap.ToArray()
+
+	w.writeUint64(uint64(len(values)))
+	for _, value := range values {
+		w.writeUint64(value)
+	}
+}
+
+func (w *snapshotWriter) flush() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	return w.w.Flush()
+}
+
+// snapshotReader is counterpart of snapshotWriter. Reader passed in is wrapped only when it is not already buffered,
+// so consecutive snapshots can be read from one stream.
+type snapshotReader struct {
+	r      *bufio.Reader
+	buffer [8]byte
+	err    error
+}
+
+func newSnapshotReader(r io.Reader) *snapshotReader {
+	return &snapshotReader{r: bufio.NewReader(r)}
+}
+
+func (r *snapshotReader) read(p []byte) {
+	if r.err != nil {
+		return
+	}
+	_, r.err = io.ReadFull(r.r, p)
+}
+
+func (r *snapshotReader) readUint8() uint8 {
+	r.read(r.buffer[:1])
+	return r.buffer[0]
+}
+
+func (r *snapshotReader) readUint32() uint32 {
+	r.read(r.buffer[:4])
+	return binary.BigEndian.Uint32(r.buffer[:4])
+}
+
+func (r *snapshotReader) readUint64() uint64 {
+	r.read(r.buffer[:8])
+	return binary.BigEndian.Uint64(r.buffer[:8])
+}
+
+func (r *snapshotReader) readFloat32() float32 {
+	return math.Float32frombits(r.readUint32())
+}
+
+func (r *snapshotReader) readString() string {
+	length := r.readUint32()
+	if r.err != nil {
+		return ""
+	}
+
+	buffer := make([]byte, length)
+	r.read(buffer)
+
+	return string(buffer)
+}
+
+func (r *snapshotReader) readEntity() component.Entity {
+	return component.Entity(r.readUint64())
+}
+
+func (r *snapshotReader) readBitmap() *roaring64.Bitmap {
+	bitmap := roaring64.New()
+
+	count := r.readUint64()
+	for i := uint64(0); i < count && r.err == nil; i++ {
+		bitmap.Add(r.readUint64())
+	}
+
+	return bitmap
+}
+
+// sortedEntities returns map keys in ascending order, so snapshot bytes do not depend on map iteration order.
+func sortedEntities(entities []component.Entity) []component.Entity {
+	sort.Slice(entities, func(i, j int) bool {
+		return entities[i] < entities[j]
+	})
+
+	return entities
+}
+
+// WriteSnapshot serializes whole state with all systems.
+func (m *State) WriteSnapshot(w io.Writer) error {
+	writer := newSnapshotWriter(w)
+
+	writer.writeUint32(snapshotMagic)
+	writer.writeUint32(snapshotVersion)
+
+	m.entitiesMutex.Lock()
+	writer.writeUint64(m.freeEntityId)
+	entities := make([]component.Entity, 0, len(m.entities))
+	for entity := range m.entities {
+		entities = append(entities, entity)
+	}
+	writer.writeUint64(uint64(len(entities)))
+	for _, entity := range sortedEntities(entities) {
+		writer.writeEntity(entity)
+		writer.writeUint8(uint8(m.entities[entity]))
+	}
+	m.entitiesMutex.Unlock()
+
+	m.area.writeSnapshot(writer)
+	m.seed.writeSnapshot(writer)
+	m.plant.writeSnapshot(writer)
+	m.planet.writeSnapshot(writer)
+	m.possession.writeSnapshot(writer)
+	m.player.writeSnapshot(writer)
+
+	if err := writer.flush(); err != nil {
+		return errors.Wrap(err, "unable to write state snapshot")
+	}
+
+	return nil
+}
+
+// Hash returns SHA-256 hash of canonical state serialization. Equal states have equal hashes on every node.
+func (m *State) Hash() ([]byte, error) {
+	hash := sha256.New()
+
+	if err := m.WriteSnapshot(hash); err != nil {
+		return nil, errors.Wrap(err, "unable to serialize state")
+	}
+
+	return hash.Sum(nil), nil
+}
+
+// NewStateFromSnapshot creates state from snapshot written by State.WriteSnapshot.
+func NewStateFromSnapshot(r io.Reader) (*State, error) {
+	reader := newSnapshotReader(r)
+
+	if reader.readUint32() != snapshotMagic {
+		return nil, ErrSnapshotInvalidFormat
+	}
+	if reader.readUint32() != snapshotVersion {
+		return nil, ErrSnapshotUnsupportedVersion
+	}
+
+	state := NewState()
+
+	state.freeEntityId = reader.readUint64()
+	entitiesCount := reader.readUint64()
+	for i := uint64(0); i < entitiesCount && reader.err == nil; i++ {
+		entity := reader.readEntity()
+		state.entities[entity] = component.EntityKind(reader.readUint8())
+	}
+
+	state.area.readSnapshot(reader)
+	state.seed.readSnapshot(reader)
+	state.plant.readSnapshot(reader)
+	state.planet.readSnapshot(reader)
+	state.possession.readSnapshot(reader)
+	state.player.readSnapshot(reader)
+
+	if reader.err != nil {
+		return nil, errors.Wrap(reader.err, "unable to read state snapshot")
+	}
+
+	return state, nil
+}
+
+var (
+	ErrSnapshotInvalidFormat      = errors.New("snapshot invalid format")
+	ErrSnapshotUnsupportedVersion = errors.New("snapshot unsupported version")
+)
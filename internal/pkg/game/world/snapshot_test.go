@@ -0,0 +1,101 @@
+package world
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"github.com/dominati-one/backend/internal/pkg/game/world/component"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestState_WriteSnapshot(t *testing.T) {
+	width := uint32(10)
+	height := uint32(10)
+
+	state := NewState()
+	planetEntity := state.Create(component.EntityKindPlanet)
+
+	areaTiles := createAreaTiles(width, height, component.AreaTileKindGround)
+	areaTiles[5] = component.AreaTile{Kind: component.AreaTileKindWater, OwnerEntity: planetEntity}
+
+	err := state.planet.add(planetEntity, component.Planet{Seed: 42, Name: "Test", GenerationVersion: 1})
+	assert.NoError(t, err)
+	err = state.area.addArea(planetEntity, component.Area{Width: width, Height: height}, areaTiles)
+	assert.NoError(t, err)
+
+	seedEntity, err := state.Actions().Seed().CreateOakSeed(planetEntity, planetEntity, 3, 4)
+	assert.NoError(t, err)
+
+	playerPublicKey := bytes.Repeat([]byte{1}, ed25519.PublicKeySize)
+	playerEntity, err := state.Actions().Player().Create("Player", playerPublicKey)
+	assert.NoError(t, err)
+
+	snapshot := &bytes.Buffer{}
+	assert.NoError(t, state.WriteSnapshot(snapshot))
+
+	restoredState, err := NewStateFromSnapshot(bytes.NewReader(snapshot.Bytes()))
+	assert.NoError(t, err)
+
+	planet, err := restoredState.planet.Get(planetEntity)
+	assert.NoError(t, err)
+	assert.Equal(t, "Test", planet.Name)
+	assert.EqualValues(t, 42, planet.Seed)
+	assert.EqualValues(t, 1, planet.GenerationVersion)
+
+	tile, err := restoredState.area.GetTile(planetEntity, 5, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, component.AreaTileKindWater, tile.Kind)
+
+	position, err := restoredState.area.GetPosition(*seedEntity)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, position.X)
+	assert.EqualValues(t, 4, position.Y)
+
+	restoredPlayerEntity, err := restoredState.player.GetByPublicKey(playerPublicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, *playerEntity, *restoredPlayerEntity)
+	assert.True(t, restoredState.player.NameTaken("Player"))
+
+	restoredSnapshot := &bytes.Buffer{}
+	assert.NoError(t, restoredState.WriteSnapshot(restoredSnapshot))
+	assert.Equal(t, snapshot.Bytes(), restoredSnapshot.Bytes())
+
+	err = restoredState.area.takePosition(*position)
+	assert.ErrorIs(t, err, ErrAreaPositionAlreadyTaken)
+
+	assert.Equal(t, state.Create(component.EntityKindUnknown), restoredState.Create(component.EntityKindUnknown))
+}
+
+func TestNewStateFromSnapshot(t *testing.T) {
+	_, err := NewStateFromSnapshot(bytes.NewReader([]byte{1, 2, 3, 4}))
+	assert.ErrorIs(t, err, ErrSnapshotInvalidFormat)
+
+	snapshot := &bytes.Buffer{}
+	assert.NoError(t, NewState().WriteSnapshot(snapshot))
+
+	_, err = NewStateFromSnapshot(bytes.NewReader(snapshot.Bytes()[:snapshot.Len()-1]))
+	assert.Error(t, err)
+}
+
+func TestState_Hash(t *testing.T) {
+	state := NewState()
+	entity := state.Create(component.EntityKindPlanet)
+
+	err := state.area.addArea(entity, component.Area{Width: 10, Height: 10}, createAreaTiles(10, 10, component.AreaTileKindGround))
+	assert.NoError(t, err)
+
+	hash, err := state.Hash()
+	assert.NoError(t, err)
+	assert.Len(t, hash, 32)
+
+	cloneHash, err := state.Clone().Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, hash, cloneHash)
+
+	_, err = state.Actions().Seed().CreateWheatSeed(entity, entity, 1, 1)
+	assert.NoError(t, err)
+
+	changedHash, err := state.Hash()
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+}
@@ -3,6 +3,7 @@ package world
 import (
 	"github.com/dominati-one/backend/internal/pkg/game/world/component"
 	"github.com/pkg/errors"
+	"math/rand"
 	"sync"
 )
 
@@ -17,6 +18,7 @@ type State struct {
 	plant      *PlantSystem
 	planet     *PlanetSystem
 	possession *PossessionSystem
+	player     *PlayerSystem
 }
 
 func NewState() *State {
@@ -30,6 +32,7 @@ func NewState() *State {
 	state.plant = newPlantSystem(state)
 	state.planet = newPlanetSystem(state)
 	state.possession = newPossessionSystem(state)
+	state.player = newPlayerSystem(state)
 
 	state.actions = newActions(state)
 
@@ -55,6 +58,7 @@ func (m *State) Clone() *State {
 	stateClone.plant = m.plant.clone(stateClone)
 	stateClone.planet = m.planet.clone(stateClone)
 	stateClone.possession = m.possession.clone(stateClone)
+	stateClone.player = m.player.clone(stateClone)
 
 	stateClone.actions = newActions(stateClone)
 
@@ -136,6 +140,12 @@ func (m *State) Remove(entity component.Entity) error {
 		}
 	}
 
+	if m.player.exists(entity) {
+		if err := m.player.remove(entity); err != nil {
+			return errors.Wrap(err, "unable to remove components from player system")
+		}
+	}
+
 	m.entitiesMutex.Lock()
 	delete(m.entities, entity)
 	m.entitiesMutex.Unlock()
@@ -143,7 +153,9 @@ func (m *State) Remove(entity component.Entity) error {
 	return nil
 }
 
-func (m *State) ApplyDeltaTime(delta uint64) error {
+// ApplyDeltaTime advances world by delta milliseconds. Random seed must be derived from data shared by all nodes,
+// e.g. block, so random outcomes are identical everywhere.
+func (m *State) ApplyDeltaTime(delta uint64, randomSeed int64) error {
 	if err := m.area.applyDeltaTime(delta); err != nil {
 		return errors.Wrap(err, "unable to apply delta time on area system")
 	}
@@ -152,7 +164,7 @@ func (m *State) ApplyDeltaTime(delta uint64) error {
 		return errors.Wrap(err, "unable to apply delta time on seed system")
 	}
 
-	if err := m.plant.applyDeltaTime(delta); err != nil {
+	if err := m.plant.applyDeltaTime(delta, rand.New(rand.NewSource(randomSeed))); err != nil {
 		return errors.Wrap(err, "unable to apply delta time on plant system")
 	}
 
@@ -164,6 +176,10 @@ func (m *State) ApplyDeltaTime(delta uint64) error {
 		return errors.Wrap(err, "unable to apply delta time on possessions system")
 	}
 
+	if err := m.player.applyDeltaTime(delta); err != nil {
+		return errors.Wrap(err, "unable to apply delta time on player system")
+	}
+
 	return nil
 }
 
@@ -180,7 +196,7 @@ func (m *State) Seed() *SeedSystem {
 }
 
 func (m *State) Plant() *PlantSystem {
-	return m.Plant()
+	return m.plant
 }
 
 func (m *State) Planet() *PlanetSystem {
@@ -191,6 +207,10 @@ func (m *State) Possession() *PossessionSystem {
 	return m.possession
 }
 
+func (m *State) Player() *PlayerSystem {
+	return m.player
+}
+
 var (
 	ErrEntityNotExists = errors.New("component not hasPosition")
 )
@@ -4,6 +4,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"io"
 	"time"
 )
 
@@ -44,6 +45,37 @@ func (c *WorldClock) Clone() *WorldClock {
 	}
 }
 
+// WriteSnapshot serializes clock ticks and compression.
+func (c *WorldClock) WriteSnapshot(w io.Writer) error {
+	writer := newSnapshotWriter(w)
+
+	writer.writeUint64(c.firstTickTimestamp)
+	writer.writeUint64(c.lastTickTimestamp)
+	writer.writeUint64(c.compression)
+
+	if err := writer.flush(); err != nil {
+		return errors.Wrap(err, "unable to write world clock snapshot")
+	}
+
+	return nil
+}
+
+// NewWorldClockFromSnapshot creates clock from snapshot written by WorldClock.WriteSnapshot.
+func NewWorldClockFromSnapshot(r io.Reader) (*WorldClock, error) {
+	reader := newSnapshotReader(r)
+
+	clock := NewWorldClock(0)
+	clock.firstTickTimestamp = reader.readUint64()
+	clock.lastTickTimestamp = reader.readUint64()
+	clock.compression = reader.readUint64()
+
+	if reader.err != nil {
+		return nil, errors.Wrap(reader.err, "unable to read world clock snapshot")
+	}
+
+	return clock, nil
+}
+
 func (c *WorldClock) SetCurrentTimestamp(timestamp uint64) (uint64, error) {
 	if c.firstTickTimestamp == 0 && c.lastTickTimestamp == 0 {
 		c.firstTickTimestamp = timestamp
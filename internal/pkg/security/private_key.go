@@ -0,0 +1,68 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"strings"
+)
+
+type PrivateKey struct {
+	key ed25519.PrivateKey
+}
+
+func GeneratePrivateKey() (*PrivateKey, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to generate ed25519 key")
+	}
+
+	return &PrivateKey{key: key}, nil
+}
+
+func NewPrivateKeyFromSeed(seed []byte) (*PrivateKey, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, ErrPrivateKeyInvalidSeedSize
+	}
+
+	return &PrivateKey{key: ed25519.NewKeyFromSeed(seed)}, nil
+}
+
+// NewPrivateKeyFromHexSeed creates private key from hex encoded ed25519 seed.
+func NewPrivateKeyFromHexSeed(hexSeed string) (*PrivateKey, error) {
+	seed, err := hex.DecodeString(strings.TrimSpace(hexSeed))
+	if err != nil {
+		return nil, ErrPrivateKeyInvalidSeedEncoding
+	}
+
+	return NewPrivateKeyFromSeed(seed)
+}
+
+// LoadPrivateKeyFromFile reads private key from file with hex encoded ed25519 seed.
+func LoadPrivateKeyFromFile(path string) (*PrivateKey, error) {
+	hexSeed, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read private key file")
+	}
+
+	return NewPrivateKeyFromHexSeed(string(hexSeed))
+}
+
+func (k *PrivateKey) PublicKey() ed25519.PublicKey {
+	return k.key.Public().(ed25519.PublicKey)
+}
+
+func (k *PrivateKey) Seed() []byte {
+	return k.key.Seed()
+}
+
+func (k *PrivateKey) sign(message []byte) []byte {
+	return ed25519.Sign(k.key, message)
+}
+
+var (
+	ErrPrivateKeyInvalidSeedSize     = errors.New("private key invalid seed size")
+	ErrPrivateKeyInvalidSeedEncoding = errors.New("private key invalid seed encoding")
+)
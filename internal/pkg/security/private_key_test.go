@@ -0,0 +1,42 @@
+package security
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPrivateKeyFromFile(t *testing.T) {
+	privateKey, err := GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "authority.key")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(hex.EncodeToString(privateKey.Seed())+"\n"), 0600))
+
+	loadedPrivateKey, err := LoadPrivateKeyFromFile(path)
+	assert.NoError(t, err)
+	assert.True(t, privateKey.PublicKey().Equal(loadedPrivateKey.PublicKey()))
+
+	_, err = LoadPrivateKeyFromFile(filepath.Join(t.TempDir(), "missing.key"))
+	assert.Error(t, err)
+
+	_, err = NewPrivateKeyFromHexSeed("not hex")
+	assert.ErrorIs(t, err, ErrPrivateKeyInvalidSeedEncoding)
+
+	_, err = NewPrivateKeyFromHexSeed("abcd")
+	assert.ErrorIs(t, err, ErrPrivateKeyInvalidSeedSize)
+}
+
+func TestNewPublicKeyFromHex(t *testing.T) {
+	privateKey, err := GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	publicKey, err := NewPublicKeyFromHex(hex.EncodeToString(privateKey.PublicKey()))
+	assert.NoError(t, err)
+	assert.True(t, privateKey.PublicKey().Equal(publicKey))
+
+	_, err = NewPublicKeyFromHex("not hex")
+	assert.ErrorIs(t, err, ErrPublicKeyInvalidEncoding)
+}
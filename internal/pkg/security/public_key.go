@@ -0,0 +1,35 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// NewPublicKey parses raw ed25519 public key bytes, e.g. EventCreatePlayer.public_key.
+func NewPublicKey(buffer []byte) (ed25519.PublicKey, error) {
+	if len(buffer) != ed25519.PublicKeySize {
+		return nil, ErrPublicKeyInvalidSize
+	}
+
+	publicKey := make(ed25519.PublicKey, ed25519.PublicKeySize)
+	copy(publicKey, buffer)
+
+	return publicKey, nil
+}
+
+// NewPublicKeyFromHex parses hex encoded ed25519 public key.
+func NewPublicKeyFromHex(hexPublicKey string) (ed25519.PublicKey, error) {
+	buffer, err := hex.DecodeString(strings.TrimSpace(hexPublicKey))
+	if err != nil {
+		return nil, ErrPublicKeyInvalidEncoding
+	}
+
+	return NewPublicKey(buffer)
+}
+
+var (
+	ErrPublicKeyInvalidSize     = errors.New("public key invalid size")
+	ErrPublicKeyInvalidEncoding = errors.New("public key invalid encoding")
+)
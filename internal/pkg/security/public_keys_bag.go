@@ -1,6 +1,11 @@
 package security
 
-import "crypto"
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"sort"
+)
 
 type PublicKeysBag struct {
 	keys []crypto.PublicKey
@@ -12,6 +17,35 @@ func NewPublicKeysBag(keys []crypto.PublicKey) *PublicKeysBag {
 	}
 }
 
-func (b *PublicKeysBag) VerifySignature(signature Signature) bool {
+// Contains checks if public key is one of keys in bag.
+func (b *PublicKeysBag) Contains(publicKey ed25519.PublicKey) bool {
+	for _, key := range b.keys {
+		if bagKey, ok := key.(ed25519.PublicKey); ok && bagKey.Equal(publicKey) {
+			return true
+		}
+	}
+
 	return false
 }
+
+// Keys returns ed25519 keys in bag sorted by their bytes, so order does not depend on order of configuration.
+func (b *PublicKeysBag) Keys() []ed25519.PublicKey {
+	keys := []ed25519.PublicKey{}
+
+	for _, key := range b.keys {
+		if bagKey, ok := key.(ed25519.PublicKey); ok {
+			keys = append(keys, bagKey)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+
+	return keys
+}
+
+// VerifySignature checks if signature was created by one of keys in bag. Signed data have to be verified separately.
+func (b *PublicKeysBag) VerifySignature(signature Signature) bool {
+	return b.Contains(signature.PublicKey())
+}
@@ -1,18 +1,113 @@
 package security
 
-import blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+import (
+	"crypto/ed25519"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// SignatureSize is the size of serialized signature: signer public key followed by ed25519 signature.
+const SignatureSize = ed25519.PublicKeySize + ed25519.SignatureSize
 
 type Signature struct {
+	publicKey ed25519.PublicKey
+	signature []byte
+}
+
+func CreateSignatureFromBody(eventBody *blockchainProtocol.Event_Body, privateKey *PrivateKey) (*Signature, error) {
+	return createSignatureFromMessage(eventBody, privateKey)
 }
 
-func CreateSignatureFromBody(eventBody *blockchainProtocol.Event_Body) (*Signature, error) {
-	return NewSignature([]byte{})
+func CreateSignatureFromBlockBody(blockBody *blockchainProtocol.Block_Body, privateKey *PrivateKey) (*Signature, error) {
+	return createSignatureFromMessage(blockBody, privateKey)
 }
 
 func NewSignature(buffer []byte) (*Signature, error) {
-	return &Signature{}, nil
+	if len(buffer) != SignatureSize {
+		return nil, ErrSignatureInvalidSize
+	}
+
+	publicKey, err := NewPublicKey(buffer[:ed25519.PublicKeySize])
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse signer public key")
+	}
+
+	signature := make([]byte, ed25519.SignatureSize)
+	copy(signature, buffer[ed25519.PublicKeySize:])
+
+	return &Signature{
+		publicKey: publicKey,
+		signature: signature,
+	}, nil
 }
 
 func (s *Signature) Verify(eventBody *blockchainProtocol.Event_Body) error {
+	return s.verifyMessage(eventBody)
+}
+
+func (s *Signature) VerifyBlockBody(blockBody *blockchainProtocol.Block_Body) error {
+	return s.verifyMessage(blockBody)
+}
+
+// PublicKey returns public key of signer.
+func (s *Signature) PublicKey() ed25519.PublicKey {
+	return s.publicKey
+}
+
+func (s *Signature) Bytes() []byte {
+	buffer := make([]byte, 0, SignatureSize)
+	buffer = append(buffer, s.publicKey...)
+	buffer = append(buffer, s.signature...)
+
+	return buffer
+}
+
+func (s *Signature) verifyMessage(message proto.Message) error {
+	if message == nil {
+		return ErrSignatureEmptyMessage
+	}
+
+	messageBytes, err := marshalDeterministic(message)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(s.publicKey, messageBytes, s.signature) {
+		return ErrSignatureMismatch
+	}
+
 	return nil
 }
+
+func createSignatureFromMessage(message proto.Message, privateKey *PrivateKey) (*Signature, error) {
+	if message == nil {
+		return nil, ErrSignatureEmptyMessage
+	}
+
+	messageBytes, err := marshalDeterministic(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signature{
+		publicKey: privateKey.PublicKey(),
+		signature: privateKey.sign(messageBytes),
+	}, nil
+}
+
+func marshalDeterministic(message proto.Message) ([]byte, error) {
+	messageBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		return nil, ErrSignatureInvalidMessageBytes
+	}
+
+	return messageBytes, nil
+}
+
+var (
+	ErrSignatureInvalidSize         = errors.New("signature invalid size")
+	ErrSignatureEmptyMessage        = errors.New("signature empty message")
+	ErrSignatureInvalidMessageBytes = errors.New("signature invalid message bytes")
+	ErrSignatureMismatch            = errors.New("signature mismatch")
+)
@@ -0,0 +1,79 @@
+package security
+
+import (
+	"crypto"
+	blockchainProtocol "github.com/dominati-one/backend/pkg/protocol/blockchain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCreateSignatureFromBody(t *testing.T) {
+	privateKey, err := GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	eventBody := &blockchainProtocol.Event_Body{
+		Event: &blockchainProtocol.Event_Body_CreatePlanet{CreatePlanet: &blockchainProtocol.EventCreatePlanet{Seed: 1}},
+	}
+
+	signature, err := CreateSignatureFromBody(eventBody, privateKey)
+	assert.NoError(t, err)
+	assert.Len(t, signature.Bytes(), SignatureSize)
+	assert.True(t, signature.PublicKey().Equal(privateKey.PublicKey()))
+	assert.NoError(t, signature.Verify(eventBody))
+
+	eventBody.GetCreatePlanet().Seed = 2
+	assert.ErrorIs(t, signature.Verify(eventBody), ErrSignatureMismatch)
+}
+
+func TestNewSignature(t *testing.T) {
+	privateKey, err := GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	eventBody := &blockchainProtocol.Event_Body{
+		Event: &blockchainProtocol.Event_Body_CreatePlayer{CreatePlayer: &blockchainProtocol.EventCreatePlayer{Name: "player"}},
+	}
+
+	signature, err := NewSignature([]byte{})
+	assert.ErrorIs(t, err, ErrSignatureInvalidSize)
+	assert.Nil(t, signature)
+
+	createdSignature, err := CreateSignatureFromBody(eventBody, privateKey)
+	assert.NoError(t, err)
+
+	signature, err = NewSignature(createdSignature.Bytes())
+	assert.NoError(t, err)
+	assert.EqualValues(t, createdSignature.Bytes(), signature.Bytes())
+	assert.NoError(t, signature.Verify(eventBody))
+}
+
+func TestPublicKeysBag_VerifySignature(t *testing.T) {
+	authorityKey, err := GeneratePrivateKey()
+	assert.NoError(t, err)
+	otherKey, err := GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	bag := NewPublicKeysBag([]crypto.PublicKey{authorityKey.PublicKey()})
+
+	eventBody := &blockchainProtocol.Event_Body{}
+
+	authoritySignature, err := CreateSignatureFromBody(eventBody, authorityKey)
+	assert.NoError(t, err)
+	assert.True(t, bag.VerifySignature(*authoritySignature))
+
+	otherSignature, err := CreateSignatureFromBody(eventBody, otherKey)
+	assert.NoError(t, err)
+	assert.False(t, bag.VerifySignature(*otherSignature))
+}
+
+func TestPublicKeysBag_Keys(t *testing.T) {
+	firstKey, err := GeneratePrivateKey()
+	assert.NoError(t, err)
+	secondKey, err := GeneratePrivateKey()
+	assert.NoError(t, err)
+
+	keys := NewPublicKeysBag([]crypto.PublicKey{firstKey.PublicKey(), secondKey.PublicKey()}).Keys()
+	reversedKeys := NewPublicKeysBag([]crypto.PublicKey{secondKey.PublicKey(), firstKey.PublicKey()}).Keys()
+
+	assert.Len(t, keys, 2)
+	assert.Equal(t, keys, reversedKeys)
+}
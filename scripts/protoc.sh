@@ -34,6 +34,7 @@ function main() {
 
   generate_golang "entity" "planet"
   generate_golang "entity" "seed"
+  generate_golang "entity" "player"
 
   generate_golang "component" "planet"
   generate_golang "component" "seed"
@@ -41,11 +42,21 @@ function main() {
   generate_golang "component" "area_tile"
   generate_golang "component" "area"
   generate_golang "component" "possession"
+  generate_golang "component" "player"
 
   generate_golang "blockchain" "block"
   generate_golang "blockchain" "event"
   generate_golang "blockchain" "event_create_planet"
   generate_golang "blockchain" "event_create_player"
+  generate_golang "blockchain" "event_inclusion_proof"
+
+  generate_golang "p2p" "peer_service"
+  generate_golang "p2p" "gossip_message"
+  generate_golang "p2p" "gossip_response"
+  generate_golang "p2p" "get_tip_request"
+  generate_golang "p2p" "get_tip_response"
+  generate_golang "p2p" "get_blocks_request"
+  generate_golang "p2p" "get_blocks_response"
 
   generate_golang "gameapi" "game_api_service"
   generate_golang "gameapi" "query_param_area_position"
@@ -56,10 +67,23 @@ function main() {
   generate_golang "gameapi" "get_planet_response"
   generate_golang "gameapi" "get_planets_request"
   generate_golang "gameapi" "get_planets_response"
+  generate_golang "gameapi" "get_player_request"
+  generate_golang "gameapi" "get_player_response"
+  generate_golang "gameapi" "get_players_request"
+  generate_golang "gameapi" "get_players_response"
+  generate_golang "gameapi" "get_player_by_public_key_request"
+  generate_golang "gameapi" "get_player_by_public_key_response"
   generate_golang "gameapi" "get_seeds_request"
   generate_golang "gameapi" "get_seeds_response"
   generate_golang "gameapi" "get_area_tiles_request"
   generate_golang "gameapi" "get_area_tiles_response"
+  generate_golang "gameapi" "get_event_proof_request"
+  generate_golang "gameapi" "get_event_proof_response"
+  generate_golang "gameapi" "event_status"
+  generate_golang "gameapi" "get_event_status_request"
+  generate_golang "gameapi" "get_event_status_response"
+  generate_golang "gameapi" "watch_event_request"
+  generate_golang "gameapi" "watch_event_response"
 
   echo -e "Done!"
 }